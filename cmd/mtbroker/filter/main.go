@@ -23,6 +23,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	configmap "knative.dev/pkg/configmap/informer"
@@ -43,6 +47,7 @@ import (
 	"knative.dev/pkg/injection/sharedmain"
 
 	eventingv1alpha1 "knative.dev/eventing/pkg/client/clientset/versioned"
+	eventingscheme "knative.dev/eventing/pkg/client/clientset/versioned/scheme"
 	eventinginformers "knative.dev/eventing/pkg/client/informers/externalversions"
 )
 
@@ -57,6 +62,10 @@ type envConfig struct {
 	PodName       string `envconfig:"POD_NAME" required:"true"`
 	ContainerName string `envconfig:"CONTAINER_NAME" required:"true"`
 	Port          int    `envconfig:"FILTER_PORT" default:"8080"`
+	// ExplainEnabled enables the endpoint explaining the filter results of the Triggers of a Broker.
+	ExplainEnabled bool `envconfig:"FILTER_EXPLAIN_ENABLED" default:"false"`
+	// HopTraceEnabled enables the hop trace of the events, reporting reply loops on the Triggers.
+	HopTraceEnabled bool `envconfig:"FILTER_HOP_TRACE_ENABLED" default:"false"`
 }
 
 func main() {
@@ -118,6 +127,18 @@ func main() {
 	if err != nil {
 		logger.Fatal("Error creating Handler", zap.Error(err))
 	}
+	if env.ExplainEnabled {
+		handler.EnableExplain()
+	}
+	if env.HopTraceEnabled {
+		eventBroadcaster := record.NewBroadcaster()
+		eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+		defer eventBroadcaster.Shutdown()
+		handler.EnableHopTrace(eventBroadcaster.NewRecorder(eventingscheme.Scheme, corev1.EventSource{Component: component}))
+	}
+	triggerInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: handler.TriggerDeleted,
+	})
 
 	// configMapWatcher does not block, so start it first.
 	if err = configMapWatcher.Start(ctx.Done()); err != nil {
@@ -18,6 +18,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
 
 	// Uncomment the following line to load the gcp plugin (only required to authenticate against GKE clusters).
@@ -26,6 +27,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
 
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	configmap "knative.dev/pkg/configmap/informer"
@@ -33,6 +37,7 @@ import (
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/injection/sharedmain"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/leaderelection"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/metrics"
 	"knative.dev/pkg/signals"
@@ -42,9 +47,11 @@ import (
 
 	cmdbroker "knative.dev/eventing/cmd/mtbroker"
 	brokerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker"
+	eventtypeinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1beta1/eventtype"
 	"knative.dev/eventing/pkg/kncloudevents"
 	broker "knative.dev/eventing/pkg/mtbroker"
 	"knative.dev/eventing/pkg/mtbroker/ingress"
+	"knative.dev/eventing/pkg/reconciler/eventtype/discovery"
 	"knative.dev/eventing/pkg/reconciler/names"
 )
 
@@ -67,6 +74,20 @@ type envConfig struct {
 	ContainerName string `envconfig:"CONTAINER_NAME" required:"true"`
 	Port          int    `envconfig:"INGRESS_PORT" default:"8080"`
 	MaxTTL        int    `envconfig:"MAX_TTL" default:"255"`
+	// DedupCapacity is the number of events remembered for each Broker
+	// dropping duplicates, ingress.DefaultDedupCapacity if unset.
+	DedupCapacity int `envconfig:"DEDUP_CAPACITY"`
+	// JWTKeyFile is the path of the key verifying the JWT bearer tokens sent
+	// to Brokers requiring JWT authentication.
+	JWTKeyFile string `envconfig:"JWT_KEY_FILE"`
+	// JWTIssuer is the issuer the JWT bearer tokens must be issued by, it
+	// isn't verified if empty.
+	JWTIssuer string `envconfig:"JWT_ISSUER"`
+	// SpoolDir is the directory where the events which can't be sent to the
+	// channels are spooled. Such events are rejected if it isn't set.
+	SpoolDir string `envconfig:"SPOOL_DIR"`
+	// SpoolMaxEvents is the maximum number of spooled events.
+	SpoolMaxEvents int `envconfig:"SPOOL_MAX_EVENTS" default:"10000"`
 }
 
 func main() {
@@ -86,6 +107,17 @@ func main() {
 		log.Fatalf("Invalid MaxTTL value, must be >=0, was: %d", env.MaxTTL)
 	}
 
+	if env.DedupCapacity == 0 {
+		env.DedupCapacity = ingress.DefaultDedupCapacity
+	}
+	if env.DedupCapacity < 0 {
+		log.Fatalf("Invalid DedupCapacity value, must be >0, was: %d", env.DedupCapacity)
+	}
+
+	if env.SpoolMaxEvents <= 0 {
+		log.Fatalf("Invalid SpoolMaxEvents value, must be >0, was: %d", env.SpoolMaxEvents)
+	}
+
 	log.Printf("Using TTL of %d", env.MaxTTL)
 	log.Printf("Registering %d clients", len(injection.Default.GetClients()))
 	log.Printf("Registering %d informer factories", len(injection.Default.GetInformerFactories()))
@@ -103,6 +135,14 @@ func main() {
 	logger.Info("Starting the Broker Ingress")
 
 	brokerLister := brokerinformer.Get(ctx).Lister()
+	eventTypeInformer := eventtypeinformer.Get(ctx).Informer()
+	if err := eventTypeInformer.AddIndexers(ingress.EventTypeIndexers); err != nil {
+		logger.Fatal("Failed to index the EventTypes", zap.Error(err))
+	}
+	schemaValidator := ingress.NewSchemaValidator(eventTypeInformer.GetIndexer(), logger)
+	eventTypeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: schemaValidator.EventTypeDeleted,
+	})
 
 	// Watch the logging config map and dynamically update logging levels.
 	configMapWatcher := configmap.NewInformedWatcher(kubeclient.Get(ctx), system.Namespace())
@@ -119,6 +159,15 @@ func main() {
 	// Watch the observability config map and dynamically update request logs.
 	configMapWatcher.Watch(logging.ConfigMapName(), logging.UpdateLevelFromConfigMap(sl, atomicLevel, component))
 
+	// Watch the rate limits config map and dynamically update the rate limits.
+	rateLimiter := ingress.NewRateLimiter()
+	configMapWatcher.WatchWithDefault(corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ingress.RateLimitsConfigName,
+			Namespace: system.Namespace(),
+		},
+	}, rateLimiter.UpdateFromConfigMap(logger))
+
 	bin := fmt.Sprintf("%s.%s", names.BrokerIngressName, system.Namespace())
 	if err = tracing.SetupDynamicPublishing(sl, configMapWatcher, bin, tracingconfig.ConfigName); err != nil {
 		logger.Fatal("Error setting up trace publishing", zap.Error(err))
@@ -134,15 +183,52 @@ func main() {
 		logger.Fatal("Unable to create message sender", zap.Error(err))
 	}
 
+	authenticator := &ingress.Authenticator{
+		KubeClient: kubeclient.Get(ctx),
+		JWTIssuer:  env.JWTIssuer,
+	}
+	if env.JWTKeyFile != "" {
+		data, err := ioutil.ReadFile(env.JWTKeyFile)
+		if err != nil {
+			logger.Fatal("Failed to read the JWT key", zap.Error(err))
+		}
+		if authenticator.JWTKey, err = ingress.ParseJWTKey(data); err != nil {
+			logger.Fatal("Failed to parse the JWT key", zap.Error(err))
+		}
+	}
+
+	// The discovery controller creates the EventTypes observed by the handler.
+	// It runs under leader election, so that a single replica writes each
+	// EventType.
+	leConfig, err := sharedmain.GetLeaderElectionConfig(ctx)
+	if err != nil {
+		logger.Fatal("Error loading the leader election configuration", zap.Error(err))
+	}
+	discoveryCtx := leaderelection.WithStandardLeaderElectorBuilder(logging.WithLogger(ctx, sl), kubeclient.Get(ctx),
+		leConfig.GetComponentConfig("mt-broker-ingress"))
+	eventTypeRecorder := discovery.NewRecorder(discovery.DefaultRefreshInterval, discovery.DefaultMaxObservations)
+	discoveryController := discovery.NewController(discoveryCtx, eventTypeRecorder)
+
 	reporter := ingress.NewStatsReporter(env.ContainerName, kmeta.ChildName(env.PodName, uuid.New().String()))
 
 	h := &ingress.Handler{
-		Receiver:     kncloudevents.NewHTTPMessageReceiver(env.Port),
-		Sender:       sender,
-		Defaulter:    broker.TTLDefaulter(logger, int32(env.MaxTTL)),
-		Reporter:     reporter,
-		Logger:       logger,
-		BrokerLister: brokerLister,
+		Receiver:          kncloudevents.NewHTTPMessageReceiver(env.Port),
+		Sender:            sender,
+		Defaulter:         broker.TTLDefaulter(logger, int32(env.MaxTTL)),
+		Reporter:          reporter,
+		Logger:            logger,
+		BrokerLister:      brokerLister,
+		Authenticator:     authenticator,
+		RateLimiter:       rateLimiter,
+		SchemaValidator:   schemaValidator,
+		EventTypeRecorder: eventTypeRecorder,
+		DedupStore:        ingress.NewLRUDedupStore(env.DedupCapacity),
+	}
+
+	if env.SpoolDir != "" {
+		if h.Spool, err = ingress.NewSpool(env.SpoolDir, env.SpoolMaxEvents, reporter, logger); err != nil {
+			logger.Fatal("Failed to create the spool", zap.Error(err))
+		}
 	}
 
 	// configMapWatcher does not block, so start it first.
@@ -156,6 +242,12 @@ func main() {
 		logger.Fatal("Failed to start informers", zap.Error(err))
 	}
 
+	go func() {
+		if err := discoveryController.RunContext(discoveryCtx, controller.DefaultThreadsPerController); err != nil {
+			logger.Error("EventType discovery controller returned an error", zap.Error(err))
+		}
+	}()
+
 	// Start blocks forever.
 	if err = h.Start(ctx); err != nil {
 		logger.Error("ingress.Start() returned an error", zap.Error(err))
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Implements a utility sending the events of a dead letter sink back to
+// a Broker or to the subscriber of a Trigger.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	nethttp "net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/pkg/signals"
+
+	"knative.dev/eventing/pkg/redrive"
+)
+
+var (
+	source  string
+	types   string
+	since   string
+	until   string
+	perSec  float64
+	dryRun  bool
+	verbose bool
+)
+
+func init() {
+	flag.StringVar(&source, "source", "-", "The dead lettered events to redrive: the path of a JSON or JSON lines export, an http(s) URL serving one, or - for the standard input")
+	flag.StringVar(&types, "types", "", "Comma separated list of the types of the events to redrive. Defaults to all of them")
+	flag.StringVar(&since, "since", "", "Only redrive the events dead lettered at or after this RFC 3339 time")
+	flag.StringVar(&until, "until", "", "Only redrive the events dead lettered at or before this RFC 3339 time")
+	flag.Float64Var(&perSec, "rate", 10, "The maximum number of events sent per second, unlimited if not greater than 0")
+	flag.BoolVar(&dryRun, "dry-run", false, "Log the events which would be redriven instead of sending them")
+	flag.BoolVar(&verbose, "verbose", false, "Log each event sent")
+}
+
+func main() {
+	flag.Parse()
+
+	if len(flag.Args()) != 1 {
+		fmt.Println("Usage: redrive [flags] <target>\nThe target is the address of a Broker or the URI of a Trigger subscriber.\nFor details about valid flags, run redrive --help")
+		os.Exit(1)
+	}
+	target := flag.Arg(0)
+
+	filter := redrive.Filter{}
+	if types != "" {
+		filter.Types = strings.Split(types, ",")
+	}
+	var err error
+	if filter.Since, err = parseTime(since); err != nil {
+		log.Fatalf("invalid --since: %v", err)
+	}
+	if filter.Until, err = parseTime(until); err != nil {
+		log.Fatalf("invalid --until: %v", err)
+	}
+
+	config := zap.NewDevelopmentConfig()
+	if !verbose {
+		config.Level.SetLevel(zap.InfoLevel)
+	}
+	logger, err := config.Build()
+	if err != nil {
+		log.Fatalf("failed to create the logger: %v", err)
+	}
+	defer logger.Sync()
+
+	ctx := signals.NewContext()
+
+	events, err := redrive.LoadEvents(ctx, nethttp.DefaultClient, source)
+	if err != nil {
+		logger.Fatal("Failed to load the events", zap.String("source", source), zap.Error(err))
+	}
+
+	r, err := redrive.NewRedriver(target, perSec, dryRun, logger)
+	if err != nil {
+		logger.Fatal("Failed to create the redriver", zap.Error(err))
+	}
+	result, err := r.Redrive(ctx, events, filter)
+	logger.Info("Redrive done",
+		zap.Bool("dryRun", dryRun),
+		zap.Int("sent", result.Sent),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("failed", result.Failed))
+	if err != nil {
+		logger.Fatal("Redrive interrupted", zap.Error(err))
+	}
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
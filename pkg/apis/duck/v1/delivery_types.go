@@ -37,7 +37,8 @@ type DeliverySpec struct {
 	// +optional
 	Retry *int32 `json:"retry,omitempty"`
 
-	// BackoffPolicy is the retry backoff policy (linear, exponential).
+	// BackoffPolicy is the retry backoff policy (linear, exponential,
+	// exponential-jitter).
 	// +optional
 	BackoffPolicy *BackoffPolicyType `json:"backoffPolicy,omitempty"`
 
@@ -48,8 +49,43 @@ type DeliverySpec struct {
 	//
 	// For linear policy, backoff delay is backoffDelay*<numberOfRetries>.
 	// For exponential policy, backoff delay is backoffDelay*2^<numberOfRetries>.
+	// For exponential-jitter policy, backoff delay is a random delay between
+	// half and all of backoffDelay*2^<numberOfRetries>.
 	// +optional
 	BackoffDelay *string `json:"backoffDelay,omitempty"`
+
+	// BackoffMaxDelay is the maximum delay before retrying, including the
+	// delay requested by the Retry-After header of the responses, which is
+	// capped to 30 seconds if not set.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	BackoffMaxDelay *string `json:"backoffMaxDelay,omitempty"`
+
+	// Timeout is the timeout of each single request sent to the subscriber,
+	// including each retry. A request timing out is retried.
+	// More information on Duration format:
+	//  - https://www.iso.org/iso-8601-date-and-time-format.html
+	//  - https://en.wikipedia.org/wiki/ISO_8601
+	// +optional
+	Timeout *string `json:"timeout,omitempty"`
+
+	// RetryableStatusCodes are the status codes of the responses which are
+	// retried, either a status code, e.g. 429, or a class of status codes,
+	// e.g. 5xx. Other failed responses are sent to the dead letter sink
+	// without being retried. Requests which failed without a response, e.g.
+	// because they timed out, are always retried.
+	// Defaults to 5xx, 408 and 429.
+	// +optional
+	RetryableStatusCodes []string `json:"retryableStatusCodes,omitempty"`
+
+	// MaxConcurrency is the maximum number of requests sent concurrently to
+	// a destination. Events exceeding it wait for a request to complete and
+	// are sent to the dead letter sink if they wait for too long.
+	// No limit is enforced if it is not set.
+	// +optional
+	MaxConcurrency *int32 `json:"maxConcurrency,omitempty"`
 }
 
 func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
@@ -67,7 +103,7 @@ func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
 
 	if ds.BackoffPolicy != nil {
 		switch *ds.BackoffPolicy {
-		case BackoffPolicyExponential, BackoffPolicyLinear:
+		case BackoffPolicyExponential, BackoffPolicyExponentialJitter, BackoffPolicyLinear:
 			// nothing
 		default:
 			errs = errs.Also(apis.ErrInvalidValue(*ds.BackoffPolicy, "backoffPolicy"))
@@ -80,9 +116,45 @@ func (ds *DeliverySpec) Validate(ctx context.Context) *apis.FieldError {
 			errs = errs.Also(apis.ErrInvalidValue(*ds.BackoffDelay, "backoffDelay"))
 		}
 	}
+
+	if ds.BackoffMaxDelay != nil {
+		maxDelay, te := period.Parse(*ds.BackoffMaxDelay)
+		if te != nil || !maxDelay.IsPositive() {
+			errs = errs.Also(apis.ErrInvalidValue(*ds.BackoffMaxDelay, "backoffMaxDelay"))
+		}
+	}
+
+	if ds.Timeout != nil {
+		timeout, te := period.Parse(*ds.Timeout)
+		if te != nil || !timeout.IsPositive() {
+			errs = errs.Also(apis.ErrInvalidValue(*ds.Timeout, "timeout"))
+		}
+	}
+
+	for i, code := range ds.RetryableStatusCodes {
+		if !isStatusCodeOrClass(code) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(code, "retryableStatusCodes", i))
+		}
+	}
+
+	if ds.MaxConcurrency != nil && *ds.MaxConcurrency < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*ds.MaxConcurrency, "maxConcurrency"))
+	}
 	return errs
 }
 
+// isStatusCodeOrClass returns whether s is an HTTP status code, e.g. 429, or a
+// class of status codes, e.g. 5xx.
+func isStatusCodeOrClass(s string) bool {
+	if len(s) != 3 || s[0] < '1' || s[0] > '5' {
+		return false
+	}
+	if s[1:] == "xx" {
+		return true
+	}
+	return s[1] >= '0' && s[1] <= '9' && s[2] >= '0' && s[2] <= '9'
+}
+
 // BackoffPolicyType is the type for backoff policies
 type BackoffPolicyType string
 
@@ -92,6 +164,9 @@ const (
 
 	// Exponential backoff policy
 	BackoffPolicyExponential BackoffPolicyType = "exponential"
+
+	// Exponential backoff policy with a random jitter
+	BackoffPolicyExponentialJitter BackoffPolicyType = "exponential-jitter"
 )
 
 // DeliveryStatus contains the Status of an object supporting delivery options.
@@ -31,6 +31,7 @@ func (source *DeliverySpec) ConvertTo(ctx context.Context, to apis.Convertible)
 	case *eventingduckv1.DeliverySpec:
 		sink.Retry = source.Retry
 		sink.BackoffDelay = source.BackoffDelay
+		sink.BackoffMaxDelay = source.BackoffMaxDelay
 		if source.BackoffPolicy != nil {
 			if *source.BackoffPolicy == BackoffPolicyLinear {
 				linear := eventingduckv1.BackoffPolicyLinear
@@ -38,11 +39,17 @@ func (source *DeliverySpec) ConvertTo(ctx context.Context, to apis.Convertible)
 			} else if *source.BackoffPolicy == BackoffPolicyExponential {
 				exponential := eventingduckv1.BackoffPolicyExponential
 				sink.BackoffPolicy = &exponential
+			} else if *source.BackoffPolicy == BackoffPolicyExponentialJitter {
+				exponentialJitter := eventingduckv1.BackoffPolicyExponentialJitter
+				sink.BackoffPolicy = &exponentialJitter
 			} else {
 				return fmt.Errorf("unknown BackoffPolicy, got: %q", *source.BackoffPolicy)
 			}
 		}
 		sink.DeadLetterSink = source.DeadLetterSink
+		sink.Timeout = source.Timeout
+		sink.RetryableStatusCodes = source.RetryableStatusCodes
+		sink.MaxConcurrency = source.MaxConcurrency
 		return nil
 	default:
 		return fmt.Errorf("unknown version, got: %T", sink)
@@ -55,6 +62,7 @@ func (sink *DeliverySpec) ConvertFrom(ctx context.Context, from apis.Convertible
 	case *eventingduckv1.DeliverySpec:
 		sink.Retry = source.Retry
 		sink.BackoffDelay = source.BackoffDelay
+		sink.BackoffMaxDelay = source.BackoffMaxDelay
 		if source.BackoffPolicy != nil {
 			if *source.BackoffPolicy == eventingduckv1.BackoffPolicyLinear {
 				linear := BackoffPolicyLinear
@@ -62,12 +70,18 @@ func (sink *DeliverySpec) ConvertFrom(ctx context.Context, from apis.Convertible
 			} else if *source.BackoffPolicy == eventingduckv1.BackoffPolicyExponential {
 				exponential := BackoffPolicyExponential
 				sink.BackoffPolicy = &exponential
+			} else if *source.BackoffPolicy == eventingduckv1.BackoffPolicyExponentialJitter {
+				exponentialJitter := BackoffPolicyExponentialJitter
+				sink.BackoffPolicy = &exponentialJitter
 			} else {
 				return fmt.Errorf("unknown BackoffPolicy, got: %q", *source.BackoffPolicy)
 			}
 
 		}
 		sink.DeadLetterSink = source.DeadLetterSink
+		sink.Timeout = source.Timeout
+		sink.RetryableStatusCodes = source.RetryableStatusCodes
+		sink.MaxConcurrency = source.MaxConcurrency
 		return nil
 	default:
 		return fmt.Errorf("unknown version, got: %T", source)
@@ -54,10 +54,15 @@ func TestDeliveryStatusConversionBadType(t *testing.T) {
 // Test v1beta1 -> v1 -> v1beta1
 func TestDeliverySpecConversion(t *testing.T) {
 	var retryCount int32 = 10
+	var maxConcurrency int32 = 5
 	var backoffPolicy BackoffPolicyType = BackoffPolicyLinear
 	var backoffPolicyExp BackoffPolicyType = BackoffPolicyExponential
 	var backoffPolicyBad BackoffPolicyType = "garbage"
 	badPolicyString := `unknown BackoffPolicy, got: "garbage"`
+	timeout := "PT10S"
+	var backoffPolicyJitter BackoffPolicyType = BackoffPolicyExponentialJitter
+	backoffDelay := "PT1S"
+	backoffMaxDelay := "PT1M"
 
 	tests := []struct {
 		name string
@@ -96,6 +101,44 @@ func TestDeliverySpecConversion(t *testing.T) {
 				URI: apis.HTTP("example.com"),
 			},
 		},
+	}, {
+		name: "with exp jitter backoff",
+		in: &DeliverySpec{
+			Retry:           &retryCount,
+			BackoffPolicy:   &backoffPolicyJitter,
+			BackoffDelay:    &backoffDelay,
+			BackoffMaxDelay: &backoffMaxDelay,
+			DeadLetterSink: &pkgduck.Destination{
+				URI: apis.HTTP("example.com"),
+			},
+		},
+	}, {
+		name: "with timeout",
+		in: &DeliverySpec{
+			Retry:   &retryCount,
+			Timeout: &timeout,
+			DeadLetterSink: &pkgduck.Destination{
+				URI: apis.HTTP("example.com"),
+			},
+		},
+	}, {
+		name: "with retryable status codes",
+		in: &DeliverySpec{
+			Retry:                &retryCount,
+			RetryableStatusCodes: []string{"5xx", "429"},
+			DeadLetterSink: &pkgduck.Destination{
+				URI: apis.HTTP("example.com"),
+			},
+		},
+	}, {
+		name: "with max concurrency",
+		in: &DeliverySpec{
+			Retry:          &retryCount,
+			MaxConcurrency: &maxConcurrency,
+			DeadLetterSink: &pkgduck.Destination{
+				URI: apis.HTTP("example.com"),
+			},
+		},
 	}, {
 		name: "with bad backoff",
 		in: &DeliverySpec{
@@ -28,6 +28,7 @@ import (
 func TestDeliverySpecValidation(t *testing.T) {
 	invalidString := "invalid time"
 	bop := BackoffPolicyExponential
+	jitter := BackoffPolicyExponentialJitter
 	validBackoffDelay := "PT2S"
 	invalidBackoffDelay := "1985-04-12T23:20:50.52Z"
 	tests := []struct {
@@ -76,6 +77,54 @@ func TestDeliverySpecValidation(t *testing.T) {
 	}, {
 		name: "valid retry 1",
 		spec: &DeliverySpec{Retry: pointer.Int32Ptr(1)},
+	}, {
+		name: "valid exponential-jitter backoffPolicy",
+		spec: &DeliverySpec{BackoffPolicy: &jitter},
+	}, {
+		name: "valid backoffMaxDelay",
+		spec: &DeliverySpec{BackoffMaxDelay: pointer.StringPtr("PT1M")},
+	}, {
+		name: "invalid backoffMaxDelay",
+		spec: &DeliverySpec{BackoffMaxDelay: &invalidString},
+		want: func() *apis.FieldError {
+			return apis.ErrInvalidValue(invalidString, "backoffMaxDelay")
+		}(),
+	}, {
+		name: "valid timeout",
+		spec: &DeliverySpec{Timeout: pointer.StringPtr("PT30S")},
+	}, {
+		name: "invalid timeout",
+		spec: &DeliverySpec{Timeout: &invalidString},
+		want: func() *apis.FieldError {
+			return apis.ErrInvalidValue(invalidString, "timeout")
+		}(),
+	}, {
+		name: "zero timeout",
+		spec: &DeliverySpec{Timeout: pointer.StringPtr("PT0S")},
+		want: func() *apis.FieldError {
+			return apis.ErrInvalidValue("PT0S", "timeout")
+		}(),
+	}, {
+		name: "valid retryableStatusCodes",
+		spec: &DeliverySpec{RetryableStatusCodes: []string{"5xx", "404", "429"}},
+		want: nil,
+	}, {
+		name: "invalid retryableStatusCodes",
+		spec: &DeliverySpec{RetryableStatusCodes: []string{"5xx", "600", "4yy"}},
+		want: func() *apis.FieldError {
+			return apis.ErrInvalidArrayValue("600", "retryableStatusCodes", 1).Also(
+				apis.ErrInvalidArrayValue("4yy", "retryableStatusCodes", 2))
+		}(),
+	}, {
+		name: "valid maxConcurrency",
+		spec: &DeliverySpec{MaxConcurrency: pointer.Int32Ptr(10)},
+		want: nil,
+	}, {
+		name: "zero maxConcurrency",
+		spec: &DeliverySpec{MaxConcurrency: pointer.Int32Ptr(0)},
+		want: func() *apis.FieldError {
+			return apis.ErrInvalidValue(0, "maxConcurrency")
+		}(),
 	}}
 
 	for _, test := range tests {
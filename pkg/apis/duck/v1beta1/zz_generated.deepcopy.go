@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -156,6 +157,26 @@ func (in *DeliverySpec) DeepCopyInto(out *DeliverySpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.BackoffMaxDelay != nil {
+		in, out := &in.BackoffMaxDelay, &out.BackoffMaxDelay
+		*out = new(string)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.RetryableStatusCodes != nil {
+		in, out := &in.RetryableStatusCodes, &out.RetryableStatusCodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxConcurrency != nil {
+		in, out := &in.MaxConcurrency, &out.MaxConcurrency
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -35,6 +35,64 @@ const (
 	// pkg/reconciler/mtbroker
 	MTChannelBrokerClassValue = "MTChannelBasedBroker"
 
+	// DispatchModeAnnotationKey is the annotation key on Brokers to indicate
+	// how the multi-tenant channel based Broker fans events out to its Triggers.
+	// Valid values are: trigger, broker.
+	DispatchModeAnnotationKey = GroupName + "/broker.dispatchMode"
+
+	// DispatchModeTrigger indicates that every Trigger gets its own
+	// Subscription to the Broker's channel. This is the default.
+	DispatchModeTrigger = "trigger"
+
+	// DispatchModeBroker indicates that the Broker gets a single
+	// Subscription to its channel and the filter evaluates all of its
+	// Triggers in one pass.
+	DispatchModeBroker = "broker"
+
+	// AuthModeAnnotationKey is the annotation key on Brokers to indicate
+	// how the ingress authenticates the requests sent to the Broker.
+	// Valid values are: jwt, tokenreview. Requests are not authenticated
+	// if the annotation is not set.
+	AuthModeAnnotationKey = GroupName + "/broker.auth"
+
+	// AuthModeJWT indicates that requests must carry a JWT bearer token
+	// signed by the key configured in the ingress.
+	AuthModeJWT = "jwt"
+
+	// AuthModeTokenReview indicates that requests must carry a Kubernetes
+	// ServiceAccount bearer token, which is verified with a TokenReview.
+	AuthModeTokenReview = "tokenreview"
+
+	// AuthAudienceAnnotationKey is the annotation key on Brokers to indicate
+	// the audience that bearer tokens must be issued for.
+	AuthAudienceAnnotationKey = GroupName + "/broker.authAudience"
+
+	// AuthSubjectsAnnotationKey is the annotation key on Brokers holding a
+	// comma separated list of the subjects allowed to send events to the
+	// Broker, e.g. system:serviceaccount:default:producer. Any authenticated
+	// subject is allowed if the annotation is not set.
+	AuthSubjectsAnnotationKey = GroupName + "/broker.authSubjects"
+
+	// SchemaValidationAnnotationKey is the annotation key on Brokers to
+	// indicate that the ingress validates the data of the events against the
+	// JSON Schema of their EventType, when set to "true".
+	SchemaValidationAnnotationKey = GroupName + "/broker.schemaValidation"
+
+	// DefaultTTLAnnotationKey is the annotation key on Brokers to indicate the
+	// TTL set by the ingress on the events without one, i.e. the number of
+	// times an event can be replied to before being dropped.
+	DefaultTTLAnnotationKey = GroupName + "/broker.defaultTTL"
+
+	// DedupWindowAnnotationKey is the annotation key on Brokers to indicate
+	// that the ingress drops the events with the same source and id as an
+	// event received within the given duration, e.g. 10m.
+	DedupWindowAnnotationKey = GroupName + "/broker.dedupWindow"
+
+	// EventTypeDiscoveryAnnotationKey is the annotation key on Brokers to
+	// indicate that EventTypes are created from the events observed by the
+	// ingress, when set to "true".
+	EventTypeDiscoveryAnnotationKey = GroupName + "/broker.eventTypeDiscovery"
+
 	// ScopeAnnotationKey is the annotation key to indicate
 	// the scope of the component handling a given resource.
 	// Valid values are: cluster, namespace, resource.
@@ -52,6 +110,10 @@ const (
 	// handled by the cluster-scoped component
 	ScopeCluster = "cluster"
 
+	// AutoDiscoveredLabelKey is the label key on EventTypes to indicate that
+	// they were created from the events observed by a Broker ingress.
+	AutoDiscoveredLabelKey = GroupName + "/autoDiscovered"
+
 	// EventTypesAnnotationKey is the annotation key to specify
 	// if a Source has event types defines in its CRD.
 	EventTypesAnnotationKey = "registry.knative.dev/eventTypes"
@@ -18,7 +18,10 @@ package v1
 
 import (
 	"context"
+	"strconv"
+	"time"
 
+	"knative.dev/eventing/pkg/apis/eventing"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmp"
 )
@@ -35,6 +38,32 @@ func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
 	if bc, ok := b.GetAnnotations()[BrokerClassAnnotationKey]; !ok || bc == "" {
 		errs = errs.Also(apis.ErrMissingField(BrokerClassAnnotationKey))
 	}
+	if mode, ok := b.GetAnnotations()[eventing.AuthModeAnnotationKey]; ok {
+		_, hasAudience := b.GetAnnotations()[eventing.AuthAudienceAnnotationKey]
+		_, hasSubjects := b.GetAnnotations()[eventing.AuthSubjectsAnnotationKey]
+		switch mode {
+		case eventing.AuthModeJWT:
+			if !hasAudience {
+				errs = errs.Also(apis.ErrMissingField("annotations[" + eventing.AuthAudienceAnnotationKey + "]"))
+			}
+		case eventing.AuthModeTokenReview:
+			if !hasAudience && !hasSubjects {
+				errs = errs.Also(apis.ErrMissingOneOf("annotations["+eventing.AuthAudienceAnnotationKey+"]", "annotations["+eventing.AuthSubjectsAnnotationKey+"]"))
+			}
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(mode, "annotations["+eventing.AuthModeAnnotationKey+"]"))
+		}
+	}
+	if ttl, ok := b.GetAnnotations()[eventing.DefaultTTLAnnotationKey]; ok {
+		if v, err := strconv.ParseInt(ttl, 10, 32); err != nil || v <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(ttl, "annotations["+eventing.DefaultTTLAnnotationKey+"]"))
+		}
+	}
+	if window, ok := b.GetAnnotations()[eventing.DedupWindowAnnotationKey]; ok {
+		if d, err := time.ParseDuration(window); err != nil || d <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(window, "annotations["+eventing.DedupWindowAnnotationKey+"]"))
+		}
+	}
 
 	errs = errs.Also(b.Spec.Validate(withNS).ViaField("spec"))
 	if apis.IsInUpdate(ctx) {
@@ -93,6 +93,104 @@ func TestValidate(t *testing.T) {
 			},
 		},
 		want: apis.ErrMissingField("eventing.knative.dev/broker.class"),
+	}, {
+		name: "valid auth mode",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":        "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.auth":         "tokenreview",
+					"eventing.knative.dev/broker.authSubjects": "system:serviceaccount:default:producer",
+				},
+			},
+		},
+	}, {
+		name: "tokenreview auth mode without audience nor subjects",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class": "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.auth":  "tokenreview",
+				},
+			},
+		},
+		want: apis.ErrMissingOneOf("annotations[eventing.knative.dev/broker.authAudience]", "annotations[eventing.knative.dev/broker.authSubjects]"),
+	}, {
+		name: "valid jwt auth mode",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":        "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.auth":         "jwt",
+					"eventing.knative.dev/broker.authAudience": "my-broker",
+				},
+			},
+		},
+	}, {
+		name: "jwt auth mode without audience",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":        "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.auth":         "jwt",
+					"eventing.knative.dev/broker.authSubjects": "producer",
+				},
+			},
+		},
+		want: apis.ErrMissingField("annotations[eventing.knative.dev/broker.authAudience]"),
+	}, {
+		name: "invalid auth mode",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class": "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.auth":  "basic",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("basic", "annotations[eventing.knative.dev/broker.auth]"),
+	}, {
+		name: "valid default TTL",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":      "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.defaultTTL": "10",
+				},
+			},
+		},
+	}, {
+		name: "invalid default TTL",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":      "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.defaultTTL": "0",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("0", "annotations[eventing.knative.dev/broker.defaultTTL]"),
+	}, {
+		name: "valid dedup window",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":       "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.dedupWindow": "10m",
+				},
+			},
+		},
+	}, {
+		name: "invalid dedup window",
+		b: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"eventing.knative.dev/broker.class":       "MTChannelBasedBroker",
+					"eventing.knative.dev/broker.dedupWindow": "-1s",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("-1s", "annotations[eventing.knative.dev/broker.dedupWindow]"),
 	}, {
 		name: "valid empty",
 		b: Broker{
@@ -26,6 +26,10 @@ import (
 	"knative.dev/pkg/kmp"
 
 	corev1 "k8s.io/api/core/v1"
+
+	"knative.dev/eventing/pkg/eventfilter/cel"
+	"knative.dev/eventing/pkg/eventfilter/cesql"
+	"knative.dev/eventing/pkg/eventfilter/data"
 )
 
 var (
@@ -54,15 +58,7 @@ func (ts *TriggerSpec) Validate(ctx context.Context) *apis.FieldError {
 	}
 
 	if ts.Filter != nil {
-		for attr := range map[string]string(ts.Filter.Attributes) {
-			if !validAttributeName.MatchString(attr) {
-				fe := &apis.FieldError{
-					Message: fmt.Sprintf("Invalid attribute name: %q", attr),
-					Paths:   []string{"filter.attributes"},
-				}
-				errs = errs.Also(fe)
-			}
-		}
+		errs = errs.Also(ts.Filter.Validate(ctx).ViaField("filter"))
 	}
 
 	if fe := ts.Subscriber.Validate(ctx); fe != nil {
@@ -78,6 +74,77 @@ func (ts *TriggerSpec) Validate(ctx context.Context) *apis.FieldError {
 	return errs
 }
 
+// Validate the TriggerFilter.
+func (tf *TriggerFilter) Validate(ctx context.Context) *apis.FieldError {
+	errs := validateAttributeNames(tf.Attributes, "attributes")
+	errs = errs.Also(validateAttributeNames(tf.Prefix, "prefix"))
+	errs = errs.Also(validateAttributeValues(tf.Prefix, "prefix"))
+	errs = errs.Also(validateAttributeNames(tf.Suffix, "suffix"))
+	errs = errs.Also(validateAttributeValues(tf.Suffix, "suffix"))
+	if tf.SQL != "" {
+		if _, err := cesql.Parse(tf.SQL); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: "Invalid SQL expression",
+				Paths:   []string{"sql"},
+				Details: err.Error(),
+			})
+		}
+	}
+	if tf.Data != "" {
+		if _, err := data.ParsePredicate(tf.Data); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: "Invalid JSONPath predicate",
+				Paths:   []string{"data"},
+				Details: err.Error(),
+			})
+		}
+	}
+	if tf.CEL != "" {
+		if err := cel.Check(tf.CEL); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: "Invalid CEL expression",
+				Paths:   []string{"cel"},
+				Details: err.Error(),
+			})
+		}
+	}
+	for i := range tf.All {
+		errs = errs.Also(tf.All[i].Validate(ctx).ViaFieldIndex("all", i))
+	}
+	for i := range tf.Any {
+		errs = errs.Also(tf.Any[i].Validate(ctx).ViaFieldIndex("any", i))
+	}
+	if tf.Not != nil {
+		errs = errs.Also(tf.Not.Validate(ctx).ViaField("not"))
+	}
+	return errs
+}
+
+func validateAttributeNames(attrs map[string]string, field string) *apis.FieldError {
+	var errs *apis.FieldError
+	for attr := range attrs {
+		if !validAttributeName.MatchString(attr) {
+			fe := &apis.FieldError{
+				Message: fmt.Sprintf("Invalid attribute name: %q", attr),
+				Paths:   []string{field},
+			}
+			errs = errs.Also(fe)
+		}
+	}
+	return errs
+}
+
+// validateAttributeValues rejects empty values, which would match every event.
+func validateAttributeValues(attrs map[string]string, field string) *apis.FieldError {
+	var errs *apis.FieldError
+	for attr, value := range attrs {
+		if value == "" {
+			errs = errs.Also(apis.ErrInvalidValue(value, attr).ViaField(field))
+		}
+	}
+	return errs
+}
+
 // CheckImmutableFields checks that any immutable fields were not changed.
 func (t *Trigger) CheckImmutableFields(ctx context.Context, original *Trigger) *apis.FieldError {
 	if original == nil {
@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -170,6 +171,39 @@ func (in *TriggerFilter) DeepCopyInto(out *TriggerFilter) {
 			(*out)[key] = val
 		}
 	}
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Suffix != nil {
+		in, out := &in.Suffix, &out.Suffix
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.All != nil {
+		in, out := &in.All, &out.All
+		*out = make([]TriggerFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Any != nil {
+		in, out := &in.Any, &out.Any
+		*out = make([]TriggerFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Not != nil {
+		in, out := &in.Not, &out.Not
+		*out = new(TriggerFilter)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -28,6 +28,11 @@ const (
 	EventTypeConditionReady                           = apis.ConditionReady
 	EventTypeConditionBrokerExists apis.ConditionType = "BrokerExists"
 	EventTypeConditionBrokerReady  apis.ConditionType = "BrokerReady"
+
+	// EventTypeConditionSchemaValid reports whether the SchemaData is a JSON
+	// Schema the Broker ingress can validate events against. It doesn't affect
+	// the readiness of the EventType, as the SchemaData may be in another format.
+	EventTypeConditionSchemaValid apis.ConditionType = "SchemaValid"
 )
 
 // GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
@@ -101,3 +106,17 @@ func (et *EventTypeStatus) PropagateBrokerStatus(bs *BrokerStatus) {
 		et.MarkBrokerUnknown("BrokerUnknown", "The status of Broker is invalid: %v", bc.Status)
 	}
 }
+
+func (et *EventTypeStatus) MarkSchemaValid() {
+	eventTypeCondSet.Manage(et).MarkTrue(EventTypeConditionSchemaValid)
+}
+
+func (et *EventTypeStatus) MarkSchemaInvalid(reason, messageFormat string, messageA ...interface{}) {
+	eventTypeCondSet.Manage(et).MarkFalse(EventTypeConditionSchemaValid, reason, messageFormat, messageA...)
+}
+
+// ClearSchemaValid removes the SchemaValid condition, for the EventTypes
+// without SchemaData.
+func (et *EventTypeStatus) ClearSchemaValid() {
+	_ = eventTypeCondSet.Manage(et).ClearCondition(EventTypeConditionSchemaValid)
+}
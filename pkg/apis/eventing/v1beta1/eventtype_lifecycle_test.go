@@ -255,3 +255,28 @@ func TestEventTypeConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestEventTypeSchemaValid(t *testing.T) {
+	ets := &EventTypeStatus{}
+	ets.MarkBrokerExists()
+	ets.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+
+	// An invalid schema doesn't affect the readiness.
+	ets.MarkSchemaInvalid("InvalidJSONSchema", "invalid")
+	if got := ets.GetCondition(EventTypeConditionSchemaValid); got == nil || got.Status != corev1.ConditionFalse {
+		t.Errorf("unexpected SchemaValid condition: %v", got)
+	}
+	if !ets.IsReady() {
+		t.Error("expected the EventType to be ready")
+	}
+
+	ets.MarkSchemaValid()
+	if got := ets.GetCondition(EventTypeConditionSchemaValid); got == nil || got.Status != corev1.ConditionTrue {
+		t.Errorf("unexpected SchemaValid condition: %v", got)
+	}
+
+	ets.ClearSchemaValid()
+	if got := ets.GetCondition(EventTypeConditionSchemaValid); got != nil {
+		t.Errorf("expected the SchemaValid condition to be cleared, got %v", got)
+	}
+}
@@ -33,12 +33,8 @@ func (source *Trigger) ConvertTo(ctx context.Context, to apis.Convertible) error
 		sink.Spec.Broker = source.Spec.Broker
 		sink.Spec.Subscriber = source.Spec.Subscriber
 		if source.Spec.Filter != nil {
-			sink.Spec.Filter = &v1.TriggerFilter{
-				Attributes: make(v1.TriggerFilterAttributes),
-			}
-			for k, v := range source.Spec.Filter.Attributes {
-				sink.Spec.Filter.Attributes[k] = v
-			}
+			sink.Spec.Filter = &v1.TriggerFilter{}
+			source.Spec.Filter.convertTo(sink.Spec.Filter)
 		}
 		if source.Spec.Delivery != nil {
 			sink.Spec.Delivery = &eventingduckv1.DeliverySpec{}
@@ -60,13 +56,8 @@ func (sink *Trigger) ConvertFrom(ctx context.Context, from apis.Convertible) err
 		sink.Spec.Broker = source.Spec.Broker
 		sink.Spec.Subscriber = source.Spec.Subscriber
 		if source.Spec.Filter != nil {
-			attributes := TriggerFilterAttributes{}
-			for k, v := range source.Spec.Filter.Attributes {
-				attributes[k] = v
-			}
-			sink.Spec.Filter = &TriggerFilter{
-				Attributes: attributes,
-			}
+			sink.Spec.Filter = &TriggerFilter{}
+			sink.Spec.Filter.convertFrom(source.Spec.Filter)
 		}
 		if source.Spec.Delivery != nil {
 			sink.Spec.Delivery = &eventingduckv1.DeliverySpec{}
@@ -79,3 +70,70 @@ func (sink *Trigger) ConvertFrom(ctx context.Context, from apis.Convertible) err
 		return fmt.Errorf("unknown version, got: %T", source)
 	}
 }
+
+func (source *TriggerFilter) convertTo(sink *v1.TriggerFilter) {
+	sink.Attributes = make(v1.TriggerFilterAttributes)
+	for k, v := range source.Attributes {
+		sink.Attributes[k] = v
+	}
+	sink.Prefix = copyAttributes(source.Prefix)
+	sink.Suffix = copyAttributes(source.Suffix)
+	sink.SQL = source.SQL
+	sink.Data = source.Data
+	sink.CEL = source.CEL
+	if source.All != nil {
+		sink.All = make([]v1.TriggerFilter, len(source.All))
+		for i := range source.All {
+			source.All[i].convertTo(&sink.All[i])
+		}
+	}
+	if source.Any != nil {
+		sink.Any = make([]v1.TriggerFilter, len(source.Any))
+		for i := range source.Any {
+			source.Any[i].convertTo(&sink.Any[i])
+		}
+	}
+	if source.Not != nil {
+		sink.Not = &v1.TriggerFilter{}
+		source.Not.convertTo(sink.Not)
+	}
+}
+
+func (sink *TriggerFilter) convertFrom(source *v1.TriggerFilter) {
+	sink.Attributes = make(TriggerFilterAttributes)
+	for k, v := range source.Attributes {
+		sink.Attributes[k] = v
+	}
+	sink.Prefix = copyAttributes(source.Prefix)
+	sink.Suffix = copyAttributes(source.Suffix)
+	sink.SQL = source.SQL
+	sink.Data = source.Data
+	sink.CEL = source.CEL
+	if source.All != nil {
+		sink.All = make([]TriggerFilter, len(source.All))
+		for i := range source.All {
+			sink.All[i].convertFrom(&source.All[i])
+		}
+	}
+	if source.Any != nil {
+		sink.Any = make([]TriggerFilter, len(source.Any))
+		for i := range source.Any {
+			sink.Any[i].convertFrom(&source.Any[i])
+		}
+	}
+	if source.Not != nil {
+		sink.Not = &TriggerFilter{}
+		sink.Not.convertFrom(source.Not)
+	}
+}
+
+func copyAttributes(attrs map[string]string) map[string]string {
+	if attrs == nil {
+		return nil
+	}
+	out := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	return out
+}
@@ -126,6 +126,45 @@ func TestTriggerConversionRoundTripV1beta1(t *testing.T) {
 				},
 			},
 		},
+	}, {name: "filter dialects",
+		in: &Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "trigger-name",
+				Namespace:  "trigger-ns",
+				Generation: 17,
+			},
+			Spec: TriggerSpec{
+				Broker: "default",
+				Filter: &TriggerFilter{
+					Attributes: TriggerFilterAttributes{"source": "mysource"},
+					Prefix:     map[string]string{"type": "com.acme.orders."},
+					Suffix:     map[string]string{"subject": ".json"},
+					SQL:        "amount > 100",
+					Data:       `$.customer.tier == "gold"`,
+					CEL:        `ce.type == "com.acme.order"`,
+					Any: []TriggerFilter{{
+						Attributes: TriggerFilterAttributes{"type": "mytype"},
+					}, {
+						Attributes: TriggerFilterAttributes{"type": "othertype"},
+						Not: &TriggerFilter{
+							Attributes: TriggerFilterAttributes{"subject": "mysubject"},
+						},
+					}},
+					All: []TriggerFilter{{
+						Attributes: TriggerFilterAttributes{"id": "myid"},
+					}},
+				},
+			},
+			Status: TriggerStatus{
+				Status: duckv1.Status{
+					ObservedGeneration: 1,
+					Conditions: duckv1.Conditions{{
+						Type:   "Ready",
+						Status: "True",
+					}},
+				},
+			},
+		},
 	}, {name: "full configuration",
 		in: &Trigger{
 			ObjectMeta: metav1.ObjectMeta{
@@ -255,6 +294,45 @@ func TestTriggerConversionRoundTripV1(t *testing.T) {
 				},
 			},
 		},
+	}, {name: "filter dialects",
+		in: &v1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "trigger-name",
+				Namespace:  "trigger-ns",
+				Generation: 17,
+			},
+			Spec: v1.TriggerSpec{
+				Broker: "default",
+				Filter: &v1.TriggerFilter{
+					Attributes: v1.TriggerFilterAttributes{"source": "mysource"},
+					Prefix:     map[string]string{"type": "com.acme.orders."},
+					Suffix:     map[string]string{"subject": ".json"},
+					SQL:        "amount > 100",
+					Data:       `$.customer.tier == "gold"`,
+					CEL:        `ce.type == "com.acme.order"`,
+					Any: []v1.TriggerFilter{{
+						Attributes: v1.TriggerFilterAttributes{"type": "mytype"},
+					}, {
+						Attributes: v1.TriggerFilterAttributes{"type": "othertype"},
+						Not: &v1.TriggerFilter{
+							Attributes: v1.TriggerFilterAttributes{"subject": "mysubject"},
+						},
+					}},
+					All: []v1.TriggerFilter{{
+						Attributes: v1.TriggerFilterAttributes{"id": "myid"},
+					}},
+				},
+			},
+			Status: v1.TriggerStatus{
+				Status: duckv1.Status{
+					ObservedGeneration: 1,
+					Conditions: duckv1.Conditions{{
+						Type:   "Ready",
+						Status: "True",
+					}},
+				},
+			},
+		},
 	}, {name: "full configuration",
 		in: &v1.Trigger{
 			ObjectMeta: metav1.ObjectMeta{
@@ -105,6 +105,63 @@ type TriggerFilter struct {
 	//
 	// +optional
 	Attributes TriggerFilterAttributes `json:"attributes,omitempty"`
+
+	// Prefix filters events by prefix match on event context attributes.
+	// Each key in the map is compared with the equivalent key in the event
+	// context. An event passes the filter if all values start with the
+	// specified values.
+	//
+	// Nested context attributes are not supported as keys. Only string values are supported.
+	//
+	// +optional
+	Prefix map[string]string `json:"prefix,omitempty"`
+
+	// Suffix filters events by suffix match on event context attributes.
+	// Each key in the map is compared with the equivalent key in the event
+	// context. An event passes the filter if all values end with the
+	// specified values.
+	//
+	// Nested context attributes are not supported as keys. Only string values are supported.
+	//
+	// +optional
+	Suffix map[string]string `json:"suffix,omitempty"`
+
+	// SQL filters events using a CloudEvents SQL (CESQL) expression. An event
+	// passes the filter if the expression evaluates to true.
+	// See https://github.com/cloudevents/spec/blob/master/cesql/spec.md
+	//
+	// +optional
+	SQL string `json:"sql,omitempty"`
+
+	// Data filters events using a JSONPath predicate on the event data, for example
+	// `$.customer.tier == "gold"`. Events whose data is not JSON don't pass the filter.
+	//
+	// +optional
+	Data string `json:"data,omitempty"`
+
+	// CEL filters events using a Common Expression Language (CEL) expression, for
+	// example `ce.type == "com.acme.order" && data.amount > 100.0`. The context
+	// attributes and extensions of the event are available as `ce` and its JSON
+	// data as `data`. An event passes the filter if the expression evaluates to true.
+	// See https://github.com/google/cel-spec
+	//
+	// +optional
+	CEL string `json:"cel,omitempty"`
+
+	// All filters events by requiring all of the nested filters to pass.
+	//
+	// +optional
+	All []TriggerFilter `json:"all,omitempty"`
+
+	// Any filters events by requiring at least one of the nested filters to pass.
+	//
+	// +optional
+	Any []TriggerFilter `json:"any,omitempty"`
+
+	// Not filters events by requiring the nested filter not to pass.
+	//
+	// +optional
+	Not *TriggerFilter `json:"not,omitempty"`
 }
 
 // TriggerFilterAttributes is a map of context attribute names to values for
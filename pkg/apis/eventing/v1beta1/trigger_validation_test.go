@@ -26,6 +26,7 @@ import (
 	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
 )
 
 var (
@@ -361,6 +362,191 @@ func TestTriggerSpecValidation(t *testing.T) {
 			Message: `Invalid attribute name: "invALID"`,
 			Paths:   []string{"filter.attributes"},
 		},
+	}, {
+		name: "invalid prefix attribute name",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Prefix: map[string]string{
+					"0invalid": "my-value",
+				},
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{
+			Message: `Invalid attribute name: "0invalid"`,
+			Paths:   []string{"filter.prefix"},
+		},
+	}, {
+		name: "invalid suffix attribute name",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Suffix: map[string]string{
+					"invALID": "my-value",
+				},
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{
+			Message: `Invalid attribute name: "invALID"`,
+			Paths:   []string{"filter.suffix"},
+		},
+	}, {
+		name: "empty prefix value",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Prefix: map[string]string{
+					"type": "",
+				},
+			},
+			Subscriber: validSubscriber,
+		},
+		want: apis.ErrInvalidValue("", "filter.prefix.type"),
+	}, {
+		name: "empty suffix value",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Suffix: map[string]string{
+					"type": "",
+				},
+			},
+			Subscriber: validSubscriber,
+		},
+		want: apis.ErrInvalidValue("", "filter.suffix.type"),
+	}, {
+		name: "invalid SQL expression",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				SQL: "type = ",
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{
+			Message: "Invalid SQL expression",
+			Paths:   []string{"filter.sql"},
+			Details: "unexpected end of expression",
+		},
+	}, {
+		name: "valid SQL expression",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				SQL: "type = 'order.created' AND amount > 100 OR source LIKE 'billing/%'",
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{},
+	}, {
+		name: "invalid data predicate",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Data: "customer.tier == 'gold'",
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{
+			Message: "Invalid JSONPath predicate",
+			Paths:   []string{"filter.data"},
+			Details: "path must start with '$'",
+		},
+	}, {
+		name: "valid data predicate",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Data: `$.customer.tier == "gold"`,
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{},
+	}, {
+		name: "invalid CEL expression",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				CEL: `ce.type + "suffix"`,
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{
+			Message: "Invalid CEL expression",
+			Paths:   []string{"filter.cel"},
+			Details: "expected a boolean expression, got string",
+		},
+	}, {
+		name: "valid CEL expression",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				CEL: `ce.type == "com.acme.order" && data.amount > 100.0`,
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{},
+	}, {
+		name: "invalid nested filters",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Any: []TriggerFilter{{
+					Attributes: TriggerFilterAttributes{"type": "my-type"},
+				}, {
+					Attributes: TriggerFilterAttributes{"0invalid": "my-value"},
+				}},
+				All: []TriggerFilter{{
+					Not: &TriggerFilter{
+						SQL: "type =",
+					},
+				}},
+			},
+			Subscriber: validSubscriber,
+		},
+		want: (&apis.FieldError{
+			Message: `Invalid attribute name: "0invalid"`,
+			Paths:   []string{"filter.any[1].attributes"},
+		}).Also(&apis.FieldError{
+			Message: "Invalid SQL expression",
+			Paths:   []string{"filter.all[0].not.sql"},
+			Details: "unexpected end of expression",
+		}),
+	}, {
+		name: "valid nested filters",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Any: []TriggerFilter{{
+					Prefix: map[string]string{"type": "com.acme.orders."},
+				}, {
+					All: []TriggerFilter{{
+						Attributes: TriggerFilterAttributes{"source": "billing"},
+					}, {
+						Not: &TriggerFilter{SQL: "amount > 100"},
+					}},
+				}},
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{},
+	}, {
+		name: "valid prefix and suffix filter",
+		ts: &TriggerSpec{
+			Broker: "test_broker",
+			Filter: &TriggerFilter{
+				Prefix: map[string]string{
+					"type": "com.acme.orders.",
+				},
+				Suffix: map[string]string{
+					"source": "/billing",
+				},
+			},
+			Subscriber: validSubscriber,
+		},
+		want: &apis.FieldError{},
 	}, {
 		name: "missing subscriber",
 		ts: &TriggerSpec{
@@ -44,6 +44,26 @@ func TestInMemoryChannelSetDefaults(t *testing.T) {
 			initial:  InMemoryChannel{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"somethingelse": "yup"}}},
 			expected: InMemoryChannel{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1", "somethingelse": "yup"}}},
 		},
+		"ordering gets the default partition key extension": {
+			initial: InMemoryChannel{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1"}},
+				Spec:       InMemoryChannelSpec{Ordering: &InMemoryChannelOrdering{}},
+			},
+			expected: InMemoryChannel{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1"}},
+				Spec:       InMemoryChannelSpec{Ordering: &InMemoryChannelOrdering{PartitionKeyExtension: "partitionkey"}},
+			},
+		},
+		"ordering keeps its partition key extension": {
+			initial: InMemoryChannel{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1"}},
+				Spec:       InMemoryChannelSpec{Ordering: &InMemoryChannelOrdering{PartitionKeyExtension: "aggregateid"}},
+			},
+			expected: InMemoryChannel{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"messaging.knative.dev/subscribable": "v1"}},
+				Spec:       InMemoryChannelSpec{Ordering: &InMemoryChannelOrdering{PartitionKeyExtension: "aggregateid"}},
+			},
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
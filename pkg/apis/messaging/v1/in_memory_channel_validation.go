@@ -19,6 +19,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"knative.dev/pkg/apis"
 
@@ -52,5 +53,24 @@ func (imcs *InMemoryChannelSpec) Validate(ctx context.Context) *apis.FieldError
 		}
 	}
 
+	if imcs.Ordering != nil {
+		errs = errs.Also(imcs.Ordering.Validate(ctx).ViaField("ordering"))
+	}
+
 	return errs
 }
+
+// extensionName matches the valid CloudEvents extension names.
+var extensionName = regexp.MustCompile(`^[a-z0-9]+$`)
+
+func (o *InMemoryChannelOrdering) Validate(ctx context.Context) *apis.FieldError {
+	if o.PartitionKeyExtension == "" {
+		return apis.ErrMissingField("partitionKeyExtension")
+	}
+	if !extensionName.MatchString(o.PartitionKeyExtension) {
+		fe := apis.ErrInvalidValue(o.PartitionKeyExtension, "partitionKeyExtension")
+		fe.Details = "expected a CloudEvents extension name, made of lower-case letters and digits"
+		return fe
+	}
+	return nil
+}
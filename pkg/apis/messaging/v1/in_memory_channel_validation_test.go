@@ -101,6 +101,34 @@ func TestInMemoryChannelValidation(t *testing.T) {
 			fe.Details = "expected either 'cluster' or 'namespace'"
 			return fe
 		}(),
+	}, {
+		name: "valid ordering",
+		cr: &InMemoryChannel{
+			Spec: InMemoryChannelSpec{
+				Ordering: &InMemoryChannelOrdering{PartitionKeyExtension: "partitionkey"},
+			},
+		},
+		want: nil,
+	}, {
+		name: "missing partition key extension",
+		cr: &InMemoryChannel{
+			Spec: InMemoryChannelSpec{
+				Ordering: &InMemoryChannelOrdering{},
+			},
+		},
+		want: apis.ErrMissingField("spec.ordering.partitionKeyExtension"),
+	}, {
+		name: "invalid partition key extension",
+		cr: &InMemoryChannel{
+			Spec: InMemoryChannelSpec{
+				Ordering: &InMemoryChannelOrdering{PartitionKeyExtension: "partition-key"},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("partition-key", "spec.ordering.partitionKeyExtension")
+			fe.Details = "expected a CloudEvents extension name, made of lower-case letters and digits"
+			return fe
+		}(),
 	}}
 
 	doValidateTest(t, tests)
@@ -47,6 +47,11 @@ func (source *InMemoryChannel) ConvertTo(ctx context.Context, obj apis.Convertib
 func (source *InMemoryChannelSpec) ConvertTo(ctx context.Context, sink *v1.InMemoryChannelSpec) error {
 	sink.SubscribableSpec = eventingduckv1.SubscribableSpec{}
 	source.SubscribableSpec.ConvertTo(ctx, &sink.SubscribableSpec)
+	if source.Ordering != nil {
+		sink.Ordering = &v1.InMemoryChannelOrdering{
+			PartitionKeyExtension: source.Ordering.PartitionKeyExtension,
+		}
+	}
 	if source.Delivery != nil {
 		sink.Delivery = &eventingduckv1.DeliverySpec{}
 		return source.Delivery.ConvertTo(ctx, sink.Delivery)
@@ -89,6 +94,11 @@ func (sink *InMemoryChannelSpec) ConvertFrom(ctx context.Context, source v1.InMe
 	}
 	sink.SubscribableSpec = eventingduckv1beta1.SubscribableSpec{}
 	sink.SubscribableSpec.ConvertFrom(ctx, &source.SubscribableSpec)
+	if source.Ordering != nil {
+		sink.Ordering = &InMemoryChannelOrdering{
+			PartitionKeyExtension: source.Ordering.PartitionKeyExtension,
+		}
+	}
 	return nil
 }
 
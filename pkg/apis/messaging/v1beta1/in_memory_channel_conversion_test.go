@@ -117,6 +117,7 @@ func TestInMemoryChannelConversion(t *testing.T) {
 						BackoffDelay:  pointer.StringPtr("5s"),
 					},
 				},
+				Ordering: &InMemoryChannelOrdering{PartitionKeyExtension: "partitionkey"},
 			},
 			Status: InMemoryChannelStatus{
 				ChannelableStatus: eventingduck.ChannelableStatus{
@@ -466,6 +467,7 @@ func TestInMemoryChannelConversionWithV1Beta1(t *testing.T) {
 						BackoffDelay:  pointer.StringPtr("5s"),
 					},
 				},
+				Ordering: &v1.InMemoryChannelOrdering{PartitionKeyExtension: "partitionkey"},
 			},
 			Status: v1.InMemoryChannelStatus{
 				ChannelableStatus: eventingduckv1.ChannelableStatus{
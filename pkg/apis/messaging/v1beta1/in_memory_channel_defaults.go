@@ -22,6 +22,11 @@ import (
 	"knative.dev/eventing/pkg/apis/messaging"
 )
 
+// DefaultPartitionKeyExtension is the default extension holding the partition
+// key of the events of the InMemoryChannels delivering them in order, as
+// defined by the CloudEvents partitioning extension.
+const DefaultPartitionKeyExtension = "partitionkey"
+
 func (imc *InMemoryChannel) SetDefaults(ctx context.Context) {
 	// Set the duck subscription to the stored version of the duck
 	// we support. Reason for this is that the stored version will
@@ -39,5 +44,7 @@ func (imc *InMemoryChannel) SetDefaults(ctx context.Context) {
 }
 
 func (imcs *InMemoryChannelSpec) SetDefaults(ctx context.Context) {
-	// TODO: Nothing to default here...
+	if imcs.Ordering != nil && imcs.Ordering.PartitionKeyExtension == "" {
+		imcs.Ordering.PartitionKeyExtension = DefaultPartitionKeyExtension
+	}
 }
@@ -67,6 +67,26 @@ var (
 type InMemoryChannelSpec struct {
 	// Channel conforms to Duck type Channelable.
 	eventingduckv1beta1.ChannelableSpec `json:",inline"`
+
+	// Ordering enables the ordered delivery of the events sharing a
+	// partition key. Events are delivered without ordering guarantees
+	// if it is not set.
+	// +optional
+	Ordering *InMemoryChannelOrdering `json:"ordering,omitempty"`
+}
+
+// InMemoryChannelOrdering configures the ordered delivery of the events of an
+// InMemoryChannel.
+type InMemoryChannelOrdering struct {
+	// PartitionKeyExtension is the name of the CloudEvents extension holding
+	// the partition key of the events. The events with the same partition key
+	// are delivered to each subscriber one at a time, in the order they were
+	// received, and the delivery of an event, including its retries, only
+	// delays the events with the same partition key. The events without a
+	// partition key are delivered without ordering guarantees.
+	// Defaults to partitionkey.
+	// +optional
+	PartitionKeyExtension string `json:"partitionKeyExtension,omitempty"`
 }
 
 // ChannelStatus represents the current state of a Channel.
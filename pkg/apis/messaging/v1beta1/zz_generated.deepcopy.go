@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -223,10 +224,31 @@ func (in *InMemoryChannelList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InMemoryChannelOrdering) DeepCopyInto(out *InMemoryChannelOrdering) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InMemoryChannelOrdering.
+func (in *InMemoryChannelOrdering) DeepCopy() *InMemoryChannelOrdering {
+	if in == nil {
+		return nil
+	}
+	out := new(InMemoryChannelOrdering)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InMemoryChannelSpec) DeepCopyInto(out *InMemoryChannelSpec) {
 	*out = *in
 	in.ChannelableSpec.DeepCopyInto(&out.ChannelableSpec)
+	if in.Ordering != nil {
+		in, out := &in.Ordering, &out.Ordering
+		*out = new(InMemoryChannelOrdering)
+		**out = **in
+	}
 	return
 }
 
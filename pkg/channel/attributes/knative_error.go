@@ -17,16 +17,52 @@ limitations under the License.
 package attributes
 
 import (
+	"time"
+
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/transformer"
 )
 
+// The extensions added to the events sent to a dead letter sink, describing
+// why they couldn't be dispatched.
 const (
-	KnativeErrorCodeExtensionKey       = "knativeerrorcode"
-	KnativeErrorDataExtensionKey       = "knativeerrordata"
+	// KnativeErrorCodeExtensionKey is the status code of the last response of
+	// the destination, or 500 if there was no response.
+	KnativeErrorCodeExtensionKey = "knativeerrorcode"
+	// KnativeErrorDataExtensionKey is the body of the last response of the
+	// destination, or the dispatch error if there was no response, truncated
+	// to KnativeErrorDataExtensionMaxLength bytes.
+	KnativeErrorDataExtensionKey = "knativeerrordata"
+	// KnativeErrorDestExtensionKey is the URI of the destination the event
+	// couldn't be sent to: the subscriber or the reply, depending on
+	// KnativeErrorStageExtensionKey.
+	KnativeErrorDestExtensionKey = "knativeerrordest"
+	// KnativeErrorStageExtensionKey is the stage of the dispatch which failed,
+	// either KnativeErrorStageSubscriber or KnativeErrorStageReply.
+	KnativeErrorStageExtensionKey = "knativeerrorstage"
+	// KnativeErrorAttemptsExtensionKey is the number of attempts made to send
+	// the event to the destination, including the retries.
+	KnativeErrorAttemptsExtensionKey = "knativeerrorattempts"
+	// KnativeErrorFirstAttemptExtensionKey is the time of the first attempt
+	// made to send the event to the destination.
+	KnativeErrorFirstAttemptExtensionKey = "knativeerrorfirstattempt"
+	// KnativeErrorLastAttemptExtensionKey is the time of the last attempt
+	// made to send the event to the destination.
+	KnativeErrorLastAttemptExtensionKey = "knativeerrorlastattempt"
+
 	KnativeErrorDataExtensionMaxLength = 1024
 )
 
+// The values of the KnativeErrorStageExtensionKey extension.
+const (
+	// KnativeErrorStageSubscriber indicates that the event couldn't be sent
+	// to the subscriber.
+	KnativeErrorStageSubscriber = "subscriber"
+	// KnativeErrorStageReply indicates that the reply of the subscriber
+	// couldn't be sent to the reply destination.
+	KnativeErrorStageReply = "reply"
+)
+
 // KnativeErrorTransformers returns Transformers which add the specified error code and data extensions.
 func KnativeErrorTransformers(code int, data string) binding.Transformers {
 	codeTransformer := transformer.AddExtension(KnativeErrorCodeExtensionKey, code)
@@ -36,3 +72,20 @@ func KnativeErrorTransformers(code int, data string) binding.Transformers {
 	dataTransformer := transformer.AddExtension(KnativeErrorDataExtensionKey, data)
 	return binding.Transformers{codeTransformer, dataTransformer}
 }
+
+// KnativeErrorAttemptsTransformers returns Transformers which add the
+// destination, stage and attempts extensions. The attempt time extensions
+// are not added if no attempt was made.
+func KnativeErrorAttemptsTransformers(dest string, stage string, attempts int, firstAttempt time.Time, lastAttempt time.Time) binding.Transformers {
+	transformers := binding.Transformers{
+		transformer.AddExtension(KnativeErrorDestExtensionKey, dest),
+		transformer.AddExtension(KnativeErrorStageExtensionKey, stage),
+		transformer.AddExtension(KnativeErrorAttemptsExtensionKey, attempts),
+	}
+	if attempts > 0 {
+		transformers = append(transformers,
+			transformer.AddExtension(KnativeErrorFirstAttemptExtensionKey, firstAttempt),
+			transformer.AddExtension(KnativeErrorLastAttemptExtensionKey, lastAttempt))
+	}
+	return transformers
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	cebindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
@@ -100,6 +101,41 @@ func TestKnativeErrorTransformers(t *testing.T) {
 	}
 }
 
+// Test the KnativeErrorAttemptsTransformers() functionality
+func TestKnativeErrorAttemptsTransformers(t *testing.T) {
+	firstAttempt := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	lastAttempt := firstAttempt.Add(time.Minute)
+
+	inputEvent := cetest.MinEvent()
+
+	withAttempts := inputEvent.Clone()
+	withAttempts.SetExtension(KnativeErrorDestExtensionKey, "http://subscriber.example.com/")
+	withAttempts.SetExtension(KnativeErrorStageExtensionKey, KnativeErrorStageSubscriber)
+	withAttempts.SetExtension(KnativeErrorAttemptsExtensionKey, 3)
+	withAttempts.SetExtension(KnativeErrorFirstAttemptExtensionKey, firstAttempt)
+	withAttempts.SetExtension(KnativeErrorLastAttemptExtensionKey, lastAttempt)
+
+	withoutAttempts := inputEvent.Clone()
+	withoutAttempts.SetExtension(KnativeErrorDestExtensionKey, "http://reply.example.com/")
+	withoutAttempts.SetExtension(KnativeErrorStageExtensionKey, KnativeErrorStageReply)
+	withoutAttempts.SetExtension(KnativeErrorAttemptsExtensionKey, 0)
+
+	cebindingtest.RunTransformerTests(t, context.Background(), []cebindingtest.TransformerTestArgs{
+		{
+			Name:         "With Attempts",
+			InputEvent:   inputEvent,
+			WantEvent:    withAttempts,
+			Transformers: KnativeErrorAttemptsTransformers("http://subscriber.example.com/", KnativeErrorStageSubscriber, 3, firstAttempt, lastAttempt),
+		},
+		{
+			Name:         "Without Attempts",
+			InputEvent:   inputEvent,
+			WantEvent:    withoutAttempts,
+			Transformers: KnativeErrorAttemptsTransformers("http://reply.example.com/", KnativeErrorStageReply, 0, time.Time{}, time.Time{}),
+		},
+	})
+}
+
 // randomString returns a randomly generated string of the specified length
 func randomString(t *testing.T, length int) string {
 	bytes := make([]byte, length)
@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrCircuitOpen is returned for the requests to a destination whose circuit
+// is open, without sending them.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of the circuit of a destination.
+type CircuitState int
+
+const (
+	// CircuitClosed lets the requests through, this is the initial state.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects the requests, after too many consecutive failures.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial requests through once
+	// the circuit has been open for long enough. The circuit closes when
+	// they succeed and opens again when one of them fails.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig configures the circuit breakers.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// circuit of a destination.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before letting trial
+	// requests through.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of trial requests which must succeed
+	// to close the circuit again.
+	HalfOpenRequests int
+}
+
+// CircuitBreakers tracks a circuit breaker per destination host, so that the
+// events sent to a destination which keeps failing are rejected right away
+// instead of going through the full retry schedule.
+type CircuitBreakers struct {
+	config   CircuitBreakerConfig
+	reporter StatsReporter
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state CircuitState
+	// generation is incremented at each transition, so that the outcome of
+	// a request allowed in a previous state is ignored.
+	generation uint64
+	// failures is the number of consecutive failures while closed.
+	failures int
+	// openedAt is when the circuit last opened.
+	openedAt time.Time
+	// trials and successes are the number of trial requests let through and
+	// the number of them which succeeded while half-open.
+	trials    int
+	successes int
+}
+
+// NewCircuitBreakers creates the circuit breakers, reporting their state to
+// reporter when it isn't nil.
+func NewCircuitBreakers(config CircuitBreakerConfig, reporter StatsReporter, logger *zap.Logger) *CircuitBreakers {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 1
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+	return &CircuitBreakers{
+		config:   config,
+		reporter: reporter,
+		logger:   logger,
+		now:      time.Now,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// State returns the state of the circuit of host.
+func (b *CircuitBreakers) State(host string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.circuits[host]; ok {
+		return c.state
+	}
+	return CircuitClosed
+}
+
+// Allow returns ErrCircuitOpen if a request to host must not be sent.
+// Otherwise, it returns the generation of the circuit the request is allowed
+// in: each allowed request must be followed by a call to Done with that
+// generation and its outcome, or to Cancel if it isn't sent.
+func (b *CircuitBreakers) Allow(host string) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	if c.state == CircuitOpen {
+		if b.now().Sub(c.openedAt) < b.config.OpenTimeout {
+			return 0, ErrCircuitOpen
+		}
+		b.transition(host, c, CircuitHalfOpen)
+	}
+	if c.state == CircuitHalfOpen {
+		if c.trials >= b.config.HalfOpenRequests {
+			return 0, ErrCircuitOpen
+		}
+		c.trials++
+	}
+	return c.generation, nil
+}
+
+// Done records the outcome of a request to host allowed by Allow in the
+// given generation. The outcome is ignored if the circuit changed state
+// since then: a slow request allowed while the circuit was closed is not a
+// trial request of the half-open circuit.
+func (b *CircuitBreakers) Done(host string, generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	if c.generation != generation {
+		return
+	}
+	switch c.state {
+	case CircuitClosed:
+		if success {
+			c.failures = 0
+			return
+		}
+		c.failures++
+		if c.failures >= b.config.FailureThreshold {
+			b.transition(host, c, CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		if !success {
+			b.transition(host, c, CircuitOpen)
+			return
+		}
+		c.successes++
+		if c.successes >= b.config.HalfOpenRequests {
+			b.transition(host, c, CircuitClosed)
+		}
+	}
+}
+
+// Cancel records that a request to host allowed by Allow in the given
+// generation was not sent, so that it doesn't count as a trial request.
+func (b *CircuitBreakers) Cancel(host string, generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	if c.generation == generation && c.state == CircuitHalfOpen {
+		c.trials--
+	}
+}
+
+func (b *CircuitBreakers) circuit(host string) *circuit {
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{}
+		b.circuits[host] = c
+	}
+	return c
+}
+
+// transition moves c to state and resets its counters, b.mu must be held.
+func (b *CircuitBreakers) transition(host string, c *circuit, state CircuitState) {
+	b.logger.Info("Circuit breaker state changed",
+		zap.String("host", host),
+		zap.Stringer("from", c.state),
+		zap.Stringer("to", state))
+
+	c.state = state
+	c.generation++
+	c.failures = 0
+	c.trials = 0
+	c.successes = 0
+	if state == CircuitOpen {
+		c.openedAt = b.now()
+	}
+	if b.reporter != nil {
+		if err := b.reporter.ReportCircuitBreakerState(host, state); err != nil {
+			b.logger.Warn("Failed to report the circuit breaker state", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeCircuitBreakerReporter struct {
+	StatsReporter
+	states []CircuitState
+}
+
+func (r *fakeCircuitBreakerReporter) ReportCircuitBreakerState(_ string, state CircuitState) error {
+	r.states = append(r.states, state)
+	return nil
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	const host = "subscriber.example.com"
+
+	now := time.Now()
+	reporter := &fakeCircuitBreakerReporter{}
+	b := NewCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 3,
+		OpenTimeout:      time.Minute,
+		HalfOpenRequests: 2,
+	}, reporter, zaptest.NewLogger(t))
+	b.now = func() time.Time { return now }
+
+	request := func(success bool) {
+		t.Helper()
+		generation, err := b.Allow(host)
+		if err != nil {
+			t.Fatalf("Allow() = %v, want nil", err)
+		}
+		b.Done(host, generation, success)
+	}
+	expectState := func(want CircuitState) {
+		t.Helper()
+		if got := b.State(host); got != want {
+			t.Fatalf("State() = %v, want %v", got, want)
+		}
+	}
+
+	// A success resets the consecutive failures.
+	request(false)
+	request(false)
+	request(true)
+	request(false)
+	request(false)
+	expectState(CircuitClosed)
+
+	request(false)
+	expectState(CircuitOpen)
+	if _, err := b.Allow(host); err != ErrCircuitOpen {
+		t.Fatalf("Allow() = %v, want %v", err, ErrCircuitOpen)
+	}
+	if got := b.State("other.example.com"); got != CircuitClosed {
+		t.Fatalf("State() of another host = %v, want %v", got, CircuitClosed)
+	}
+
+	// A failing trial request opens the circuit again.
+	now = now.Add(time.Minute)
+	request(false)
+	expectState(CircuitOpen)
+
+	// Only HalfOpenRequests trial requests are let through.
+	now = now.Add(time.Minute)
+	request(true)
+	expectState(CircuitHalfOpen)
+	generation, err := b.Allow(host)
+	if err != nil {
+		t.Fatalf("Allow() = %v, want nil", err)
+	}
+	if _, err := b.Allow(host); err != ErrCircuitOpen {
+		t.Fatalf("Allow() = %v, want %v", err, ErrCircuitOpen)
+	}
+	b.Done(host, generation, true)
+	expectState(CircuitClosed)
+
+	want := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}
+	if len(reporter.states) != len(want) {
+		t.Fatalf("Reported states %v, want %v", reporter.states, want)
+	}
+	for i := range want {
+		if reporter.states[i] != want[i] {
+			t.Fatalf("Reported states %v, want %v", reporter.states, want)
+		}
+	}
+}
+
+func TestCircuitBreakersStaleResult(t *testing.T) {
+	const host = "subscriber.example.com"
+
+	now := time.Now()
+	b := NewCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+		HalfOpenRequests: 1,
+	}, nil, zaptest.NewLogger(t))
+	b.now = func() time.Time { return now }
+
+	// A slow request is allowed while the circuit is closed.
+	slow, err := b.Allow(host)
+	if err != nil {
+		t.Fatalf("Allow() = %v, want nil", err)
+	}
+
+	// Another request fails and opens the circuit, which then lets a trial
+	// request through.
+	failed, err := b.Allow(host)
+	if err != nil {
+		t.Fatalf("Allow() = %v, want nil", err)
+	}
+	b.Done(host, failed, false)
+	now = now.Add(time.Minute)
+	trial, err := b.Allow(host)
+	if err != nil {
+		t.Fatalf("Allow() = %v, want nil", err)
+	}
+
+	// The slow request succeeds: it isn't the outcome of the trial request.
+	b.Done(host, slow, true)
+	if got := b.State(host); got != CircuitHalfOpen {
+		t.Fatalf("State() = %v, want %v", got, CircuitHalfOpen)
+	}
+
+	b.Done(host, trial, true)
+	if got := b.State(host); got != CircuitClosed {
+		t.Fatalf("State() = %v, want %v", got, CircuitClosed)
+	}
+}
+
+func TestCircuitBreakersCancel(t *testing.T) {
+	const host = "subscriber.example.com"
+
+	now := time.Now()
+	b := NewCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+		HalfOpenRequests: 1,
+	}, nil, zaptest.NewLogger(t))
+	b.now = func() time.Time { return now }
+
+	generation, err := b.Allow(host)
+	if err != nil {
+		t.Fatalf("Allow() = %v, want nil", err)
+	}
+	b.Done(host, generation, false)
+	now = now.Add(time.Minute)
+
+	// A trial request which isn't sent lets another one through.
+	generation, err = b.Allow(host)
+	if err != nil {
+		t.Fatalf("Allow() = %v, want nil", err)
+	}
+	b.Cancel(host, generation)
+	generation, err = b.Allow(host)
+	if err != nil {
+		t.Fatalf("Allow() = %v, want nil", err)
+	}
+	b.Done(host, generation, true)
+	if got := b.State(host); got != CircuitClosed {
+		t.Fatalf("State() = %v, want %v", got, CircuitClosed)
+	}
+}
@@ -24,6 +24,7 @@ package fanout
 import (
 	"context"
 	"errors"
+	"fmt"
 	nethttp "net/http"
 	"net/url"
 	"sync"
@@ -41,6 +42,10 @@ import (
 
 const (
 	defaultTimeout = 15 * time.Minute
+
+	// maxOrderedPending is the maximum number of events with the same
+	// partition key waiting to be dispatched to a subscription.
+	maxOrderedPending = 1000
 )
 
 type Subscription struct {
@@ -56,6 +61,11 @@ type Config struct {
 	// AsyncHandler controls whether the Subscriptions are called synchronous or asynchronously.
 	// It is expected to be false when used as a sidecar.
 	AsyncHandler bool `json:"asyncHandler,omitempty"`
+	// PartitionKeyExtension is the extension holding the partition key of
+	// the events which are delivered in order to each Subscription, when
+	// not empty. It is only used by the asynchronous handler, the synchronous
+	// one delivers each event before accepting the next one.
+	PartitionKeyExtension string `json:"partitionKeyExtension,omitempty"`
 }
 
 // MessageHandler is an http.Handler but has methods for managing
@@ -66,6 +76,8 @@ type MessageHandler interface {
 	nethttp.Handler
 	SetSubscriptions(ctx context.Context, subs []Subscription)
 	GetSubscriptions(ctx context.Context) []Subscription
+	SetPartitionKeyExtension(ctx context.Context, extension string)
+	GetPartitionKeyExtension(ctx context.Context) string
 }
 
 // MessageHandler is a http.Handler that takes a single request in and fans it out to N other servers.
@@ -74,8 +86,13 @@ type FanoutMessageHandler struct {
 	// It is expected to be false when used as a sidecar.
 	asyncHandler bool
 
-	subscriptionsMutex sync.RWMutex
-	subscriptions      []Subscription
+	subscriptionsMutex    sync.RWMutex
+	subscriptions         []Subscription
+	partitionKeyExtension string
+
+	// orderedQueues holds the pending dispatches of the events with a
+	// partition key, per Subscription and partition key.
+	orderedQueues *orderedQueues
 
 	receiver   *channel.MessageReceiver
 	dispatcher channel.MessageDispatcher
@@ -97,6 +114,9 @@ func NewFanoutMessageHandler(logger *zap.Logger, messageDispatcher channel.Messa
 		timeout:      defaultTimeout,
 		reporter:     reporter,
 		asyncHandler: config.AsyncHandler,
+
+		partitionKeyExtension: config.PartitionKeyExtension,
+		orderedQueues:         newOrderedQueues(maxOrderedPending),
 	}
 	handler.subscriptions = make([]Subscription, len(config.Subscriptions))
 	for i := range config.Subscriptions {
@@ -158,6 +178,20 @@ func (f *FanoutMessageHandler) GetSubscriptions(ctx context.Context) []Subscript
 	return ret
 }
 
+// SetPartitionKeyExtension sets the extension holding the partition key of the
+// events delivered in order, ordering is disabled if it is empty.
+func (f *FanoutMessageHandler) SetPartitionKeyExtension(ctx context.Context, extension string) {
+	f.subscriptionsMutex.Lock()
+	defer f.subscriptionsMutex.Unlock()
+	f.partitionKeyExtension = extension
+}
+
+func (f *FanoutMessageHandler) GetPartitionKeyExtension(ctx context.Context) string {
+	f.subscriptionsMutex.RLock()
+	defer f.subscriptionsMutex.RUnlock()
+	return f.partitionKeyExtension
+}
+
 func createMessageReceiverFunction(f *FanoutMessageHandler) func(context.Context, channel.ChannelReference, binding.Message, []binding.Transformer, nethttp.Header) error {
 	if f.asyncHandler {
 		return func(ctx context.Context, ref channel.ChannelReference, message binding.Message, transformers []binding.Transformer, additionalHeaders nethttp.Header) error {
@@ -172,6 +206,10 @@ func createMessageReceiverFunction(f *FanoutMessageHandler) func(context.Context
 			parentSpan := trace.FromContext(ctx)
 			te := kncloudevents.TypeExtractorTransformer("")
 			transformers = append(transformers, &te)
+			partitionKey := partitionKeyExtractorTransformer{extension: f.GetPartitionKeyExtension(ctx)}
+			if partitionKey.extension != "" {
+				transformers = append(transformers, &partitionKey)
+			}
 			// Message buffering here is done before starting the dispatch goroutine
 			// Because the message could be closed before the buffering happens
 			bufferedMessage, err := buffering.CopyMessage(ctx, message, transformers...)
@@ -185,6 +223,9 @@ func createMessageReceiverFunction(f *FanoutMessageHandler) func(context.Context
 
 			// We don't need the original message anymore
 			_ = message.Finish(nil)
+			if partitionKey.key != "" {
+				return f.dispatchOrdered(subs, bufferedMessage, additionalHeaders, parentSpan, partitionKey.key, reportArgs)
+			}
 			go func(m binding.Message, h nethttp.Header, s *trace.Span, r *channel.StatsReporter, args *channel.ReportArgs) {
 				// Run async dispatch with background context.
 				ctx = trace.NewContext(context.Background(), s)
@@ -233,6 +274,9 @@ func parseFanoutResultAndReportMetrics(result dispatchResult, reporter channel.S
 			_ = reporter.ReportEventDispatchTime(&reportArgs, nethttp.StatusInternalServerError, result.info.Time)
 		}
 	}
+	if result.info != nil && result.info.QueueTime > 0 {
+		_ = reporter.ReportEventQueueTime(&reportArgs, result.info.QueueTime)
+	}
 	err := result.err
 	if err != nil {
 		channel.ReportEventCountMetricsForDispatchError(err, reporter, &reportArgs)
@@ -276,6 +320,9 @@ func (f *FanoutMessageHandler) dispatch(ctx context.Context, subs []Subscription
 				}
 				dispatchResultForFanout.info.Time = totalDispatchTimeForFanout
 				dispatchResultForFanout.info.ResponseCode = dispatchResult.info.ResponseCode
+				if dispatchResult.info.QueueTime > dispatchResultForFanout.info.QueueTime {
+					dispatchResultForFanout.info.QueueTime = dispatchResult.info.QueueTime
+				}
 			}
 			if dispatchResult.err != nil {
 				f.logger.Error("Fanout had an error", zap.Error(dispatchResult.err))
@@ -292,6 +339,45 @@ func (f *FanoutMessageHandler) dispatch(ctx context.Context, subs []Subscription
 	return dispatchResultForFanout
 }
 
+// dispatchOrdered queues the dispatch of the message to each subscription in
+// subs after the dispatch of the messages previously received with the same
+// partition key, so that a subscription receives them in order. It rejects the
+// message if too many messages with the partition key are pending.
+func (f *FanoutMessageHandler) dispatchOrdered(subs []Subscription, bufferedMessage binding.Message, additionalHeaders nethttp.Header, span *trace.Span, partitionKey string, reportArgs channel.ReportArgs) error {
+	if len(subs) == 0 {
+		// Nothing to dispatch, no subscription would ack the message.
+		_ = bufferedMessage.Finish(nil)
+		return nil
+	}
+
+	// Bind the lifecycle of the buffered message to the number of subs
+	acked := buffering.WithAcksBeforeFinish(bufferedMessage, len(subs))
+
+	tasks := make([]orderedTask, 0, len(subs))
+	for _, sub := range subs {
+		sub := sub
+		tasks = append(tasks, orderedTask{
+			key: fmt.Sprintf("%s %s %s", sub.Subscriber, sub.Reply, partitionKey),
+			f: func() {
+				// Run async dispatch with background context, each dispatch
+				// holds up the next ones so it is bounded by the timeout.
+				ctx, cancel := context.WithTimeout(trace.NewContext(context.Background(), span), f.timeout)
+				defer cancel()
+				info, err := f.makeFanoutRequest(ctx, acked, additionalHeaders, sub)
+				if err != nil {
+					f.logger.Error("Ordered dispatch had an error", zap.Error(err), zap.String("partitionKey", partitionKey))
+				}
+				_ = parseFanoutResultAndReportMetrics(dispatchResult{err: err, info: info}, f.reporter, reportArgs)
+			},
+		})
+	}
+	if !f.orderedQueues.submit(tasks...) {
+		_ = bufferedMessage.Finish(nil)
+		return fmt.Errorf("too many pending events with the partition key %q", partitionKey)
+	}
+	return nil
+}
+
 // makeFanoutRequest sends the request to exactly one subscription. It handles both the `call` and
 // the `sink` portions of the subscription.
 func (f *FanoutMessageHandler) makeFanoutRequest(ctx context.Context, message binding.Message, additionalHeaders nethttp.Header, sub Subscription) (*channel.DispatchExecutionInfo, error) {
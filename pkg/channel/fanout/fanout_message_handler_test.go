@@ -284,6 +284,173 @@ func TestFanoutMessageHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestFanoutMessageHandler_Ordered(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan string, 10)
+	var blocked atomic.Bool
+	subscriberServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("ce-id")
+		// The first event of partition a fails until it's released, blocking
+		// the other events of the partition.
+		if id == "a-1" && blocked.CAS(false, true) {
+			<-release
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received <- id
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriberServer.Close()
+
+	// The dispatches still log after the last event was received.
+	logger := zap.NewNop()
+	retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(eventingduckv1.DeliverySpec{
+		Retry: func(i int32) *int32 { return &i }(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewFanoutMessageHandler(
+		logger,
+		channel.NewMessageDispatcher(logger),
+		Config{
+			Subscriptions: []Subscription{{
+				Subscriber:  apis.HTTP(subscriberServer.URL[7:]).URL(),
+				RetryConfig: &retryConfig,
+			}},
+			AsyncHandler:          true,
+			PartitionKeyExtension: "partitionkey",
+		},
+		channel.NewStatsReporter("testcontainer", "testpod"),
+	)
+	if err != nil {
+		t.Fatal("NewHandler failed =", err)
+	}
+
+	for _, id := range []string{"a-1", "a-2", "b-1", "a-3"} {
+		event := makeCloudEvent()
+		event.SetID(id)
+		event.SetExtension("partitionkey", id[:1])
+		req := httptest.NewRequest(http.MethodPost, "http://channelname.channelnamespace/", nil)
+		if err := bindingshttp.WriteRequest(context.Background(), binding.ToMessage(&event), req); err != nil {
+			t.Fatal("WriteRequest =", err)
+		}
+		resp := httptest.ResponseRecorder{}
+		h.ServeHTTP(&resp, req)
+		if resp.Code != http.StatusAccepted {
+			t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, resp.Code)
+		}
+	}
+
+	next := func() string {
+		select {
+		case id := <-received:
+			return id
+		case <-time.After(10 * time.Second):
+			t.Fatal("Timed out waiting for an event")
+			return ""
+		}
+	}
+	// Partition b isn't delayed by the retries of partition a.
+	if id := next(); id != "b-1" {
+		t.Fatalf("Expected b-1 to be delivered first, got %s", id)
+	}
+	close(release)
+	for _, want := range []string{"a-1", "a-2", "a-3"} {
+		if id := next(); id != want {
+			t.Fatalf("Expected %s to be delivered, got %s", want, id)
+		}
+	}
+}
+
+func TestFanoutMessageHandler_OrderedTimeout(t *testing.T) {
+	done := make(chan struct{})
+	received := make(chan string, 10)
+	subscriberServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("ce-id")
+		// The first event hangs, it must not block the partition forever.
+		if id == "a-1" {
+			<-done
+			return
+		}
+		received <- id
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriberServer.Close()
+	// Release the hanging request before closing the server.
+	defer close(done)
+
+	logger := zap.NewNop()
+	h, err := NewFanoutMessageHandler(
+		logger,
+		channel.NewMessageDispatcher(logger),
+		Config{
+			Subscriptions: []Subscription{{
+				Subscriber: apis.HTTP(subscriberServer.URL[7:]).URL(),
+			}},
+			AsyncHandler:          true,
+			PartitionKeyExtension: "partitionkey",
+		},
+		channel.NewStatsReporter("testcontainer", "testpod"),
+	)
+	if err != nil {
+		t.Fatal("NewHandler failed =", err)
+	}
+	h.timeout = 100 * time.Millisecond
+
+	for _, id := range []string{"a-1", "a-2"} {
+		event := makeCloudEvent()
+		event.SetID(id)
+		event.SetExtension("partitionkey", "a")
+		req := httptest.NewRequest(http.MethodPost, "http://channelname.channelnamespace/", nil)
+		if err := bindingshttp.WriteRequest(context.Background(), binding.ToMessage(&event), req); err != nil {
+			t.Fatal("WriteRequest =", err)
+		}
+		resp := httptest.ResponseRecorder{}
+		h.ServeHTTP(&resp, req)
+		if resp.Code != http.StatusAccepted {
+			t.Fatalf("Unexpected status code. Expected %v, Actual %v", http.StatusAccepted, resp.Code)
+		}
+	}
+
+	select {
+	case id := <-received:
+		if id != "a-2" {
+			t.Fatalf("Expected a-2 to be delivered, got %s", id)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the event after the one which timed out")
+	}
+}
+
+func TestFanoutMessageHandler_OrderedNoSubscriptions(t *testing.T) {
+	logger := zap.NewNop()
+	h, err := NewFanoutMessageHandler(
+		logger,
+		channel.NewMessageDispatcher(logger),
+		Config{
+			AsyncHandler:          true,
+			PartitionKeyExtension: "partitionkey",
+		},
+		channel.NewStatsReporter("testcontainer", "testpod"),
+	)
+	if err != nil {
+		t.Fatal("NewHandler failed =", err)
+	}
+
+	event := makeCloudEvent()
+	finished := false
+	message := binding.WithFinish(binding.ToMessage(&event), func(error) {
+		finished = true
+	})
+	if err := h.dispatchOrdered(nil, message, nil, nil, "a", channel.ReportArgs{}); err != nil {
+		t.Fatal("dispatchOrdered() =", err)
+	}
+	if !finished {
+		t.Error("Expected the message to be finished")
+	}
+}
+
 func testFanoutMessageHandler(t *testing.T, async bool, receiverFunc channel.UnbufferedMessageReceiverFunc, timeout time.Duration, inSubs []Subscription, subscriberHandler func(http.ResponseWriter, *http.Request), subscriberReqs int, replierHandler func(http.ResponseWriter, *http.Request), replierReqs int, expectedStatus int) {
 	var subscriberServerWg *sync.WaitGroup
 	reporter := channel.NewStatsReporter("testcontainer", "testpod")
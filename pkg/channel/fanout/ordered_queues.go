@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fanout
+
+import (
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// orderedQueues runs the functions submitted with the same key one at a time,
+// in the order they were submitted, and those with different keys
+// concurrently. A goroutine only runs while the queue of a key isn't empty.
+type orderedQueues struct {
+	// maxPending is the maximum number of functions waiting in the queue of
+	// a key.
+	maxPending int
+
+	mutex  sync.Mutex
+	queues map[string][]func()
+}
+
+// orderedTask is a function to run in the queue of key.
+type orderedTask struct {
+	key string
+	f   func()
+}
+
+func newOrderedQueues(maxPending int) *orderedQueues {
+	return &orderedQueues{
+		maxPending: maxPending,
+		queues:     make(map[string][]func()),
+	}
+}
+
+// submit runs each task after the functions previously submitted with its
+// key. It submits none of them and returns false if the queue of one of the
+// keys is full.
+func (q *orderedQueues) submit(tasks ...orderedTask) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for _, t := range tasks {
+		if len(q.queues[t.key]) >= q.maxPending {
+			return false
+		}
+	}
+	for _, t := range tasks {
+		if pending, ok := q.queues[t.key]; ok {
+			q.queues[t.key] = append(pending, t.f)
+			continue
+		}
+		q.queues[t.key] = nil
+		go q.run(t.key, t.f)
+	}
+	return true
+}
+
+func (q *orderedQueues) run(key string, f func()) {
+	for {
+		f()
+
+		q.mutex.Lock()
+		pending := q.queues[key]
+		if len(pending) == 0 {
+			delete(q.queues, key)
+			q.mutex.Unlock()
+			return
+		}
+		f = pending[0]
+		// Release the function, the backing array outlives it.
+		pending[0] = nil
+		q.queues[key] = pending[1:]
+		q.mutex.Unlock()
+	}
+}
+
+// partitionKeyExtractorTransformer extracts the partition key of a message
+// from the extension named extension, the key is empty if it is missing.
+type partitionKeyExtractorTransformer struct {
+	extension string
+	key       string
+}
+
+func (p *partitionKeyExtractorTransformer) Transform(reader binding.MessageMetadataReader, _ binding.MessageMetadataWriter) error {
+	if v := reader.GetExtension(p.extension); v != nil {
+		key, err := types.ToString(v)
+		if err != nil {
+			return err
+		}
+		p.key = key
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fanout
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOrderedQueues(t *testing.T) {
+	q := newOrderedQueues(10)
+
+	var mutex sync.Mutex
+	var got []int
+	record := func(i int) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		got = append(got, i)
+	}
+
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(6)
+	q.submit(orderedTask{key: "a", f: func() {
+		defer wg.Done()
+		<-release
+		record(1)
+	}})
+	for i := 2; i <= 5; i++ {
+		i := i
+		q.submit(orderedTask{key: "a", f: func() {
+			defer wg.Done()
+			record(i)
+		}})
+	}
+
+	// A different key isn't delayed by the blocked one.
+	done := make(chan struct{})
+	q.submit(orderedTask{key: "b", f: func() {
+		defer wg.Done()
+		close(done)
+	}})
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the function of another key")
+	}
+
+	close(release)
+	wg.Wait()
+	for i, v := range got {
+		if v != i+1 {
+			t.Fatalf("Functions ran in order %v, want 1 to 5", got)
+		}
+	}
+
+	// The queues are removed once empty, right after their last function ran.
+	for start := time.Now(); ; time.Sleep(time.Millisecond) {
+		q.mutex.Lock()
+		remaining := len(q.queues)
+		q.mutex.Unlock()
+		if remaining == 0 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("Expected the queues to be removed once empty, %d remain", remaining)
+		}
+	}
+}
+
+func TestOrderedQueuesFull(t *testing.T) {
+	q := newOrderedQueues(2)
+
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(3)
+	blocked := orderedTask{key: "a", f: func() {
+		defer wg.Done()
+		<-release
+	}}
+	noop := func(key string) orderedTask {
+		return orderedTask{key: key, f: wg.Done}
+	}
+
+	// The running function isn't pending.
+	if !q.submit(blocked) || !q.submit(noop("a")) || !q.submit(noop("a")) {
+		t.Fatal("Expected the functions to be submitted")
+	}
+	ran := false
+	if q.submit(orderedTask{key: "b", f: func() { ran = true }}, noop("a")) {
+		t.Fatal("Expected the functions to be rejected when a queue is full")
+	}
+
+	close(release)
+	wg.Wait()
+	if ran {
+		t.Error("Expected none of the rejected functions to run")
+	}
+}
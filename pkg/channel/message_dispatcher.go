@@ -61,6 +61,15 @@ var _ MessageDispatcher = &MessageDispatcherImpl{}
 type MessageDispatcherImpl struct {
 	sender           *kncloudevents.HTTPMessageSender
 	supportedSchemes sets.String
+	// webhookValidator performs the abuse protection handshake with the
+	// destinations, nil if it is disabled.
+	webhookValidator *kncloudevents.WebhookValidator
+	// circuitBreakers rejects the requests to the destinations which keep
+	// failing, nil if it is disabled.
+	circuitBreakers *CircuitBreakers
+	// concurrencyLimiters limits the requests sent concurrently to the
+	// destinations whose RetryConfig sets MaxConcurrency.
+	concurrencyLimiters *kncloudevents.ConcurrencyLimiters
 
 	logger *zap.Logger
 }
@@ -69,6 +78,15 @@ type DispatchExecutionInfo struct {
 	Time         time.Duration
 	ResponseCode int
 	ResponseBody []byte
+	// Attempts is the number of attempts made to send the request, including
+	// the retries, and FirstAttemptTime and LastAttemptTime are the times of
+	// the first and the last of them.
+	Attempts         int
+	FirstAttemptTime time.Time
+	LastAttemptTime  time.Time
+	// QueueTime is how long the request waited for the in-flight requests to
+	// the destination to complete, when its maximum concurrency was reached.
+	QueueTime time.Duration
 }
 
 // NewMessageDispatcherFromConfig creates a new Message dispatcher based on config.
@@ -83,12 +101,27 @@ func NewMessageDispatcher(logger *zap.Logger) *MessageDispatcherImpl {
 // NewMessageDispatcherFromConfig creates a new event dispatcher.
 func NewMessageDispatcherFromSender(logger *zap.Logger, sender *kncloudevents.HTTPMessageSender) *MessageDispatcherImpl {
 	return &MessageDispatcherImpl{
-		sender:           sender,
-		supportedSchemes: sets.NewString("http", "https"),
-		logger:           logger,
+		sender:              sender,
+		supportedSchemes:    sets.NewString("http", "https"),
+		concurrencyLimiters: kncloudevents.NewConcurrencyLimiters(kncloudevents.DefaultConcurrencyWaitTimeout),
+		logger:              logger,
 	}
 }
 
+// EnableWebhookValidation makes the dispatcher perform the abuse protection
+// handshake of the CloudEvents HTTP webhook specification with each
+// destination before sending it events, and honour the rate it allows.
+func (d *MessageDispatcherImpl) EnableWebhookValidation(validator *kncloudevents.WebhookValidator) {
+	d.webhookValidator = validator
+}
+
+// EnableCircuitBreakers makes the dispatcher stop sending events to the
+// destination hosts which keep failing for a while, failing them right away
+// so that they go to the dead letter sink if there is one.
+func (d *MessageDispatcherImpl) EnableCircuitBreakers(circuitBreakers *CircuitBreakers) {
+	d.circuitBreakers = circuitBreakers
+}
+
 func (d *MessageDispatcherImpl) DispatchMessage(ctx context.Context, message cloudevents.Message, additionalHeaders nethttp.Header, destination *url.URL, reply *url.URL, deadLetter *url.URL) (*DispatchExecutionInfo, error) {
 	return d.DispatchMessageWithRetries(ctx, message, additionalHeaders, destination, reply, deadLetter, nil)
 }
@@ -122,7 +155,7 @@ func (d *MessageDispatcherImpl) DispatchMessageWithRetries(ctx context.Context,
 		if err != nil {
 			// If DeadLetter is configured, then send original message with knative error extensions
 			if deadLetter != nil {
-				transformers := d.dispatchExecutionInfoTransformers(dispatchExecutionInfo)
+				transformers := d.dispatchExecutionInfoTransformers(destination, attributes.KnativeErrorStageSubscriber, dispatchExecutionInfo)
 				_, deadLetterResponse, _, dispatchExecutionInfo, deadLetterErr := d.executeRequest(ctx, deadLetter, message, additionalHeaders, retriesConfig, transformers...)
 				if deadLetterErr != nil {
 					return dispatchExecutionInfo, fmt.Errorf("unable to complete request to either %s (%v) or %s (%v)", destination, err, deadLetter, deadLetterErr)
@@ -158,7 +191,7 @@ func (d *MessageDispatcherImpl) DispatchMessageWithRetries(ctx context.Context,
 	if err != nil {
 		// If DeadLetter is configured, then send original message with knative error extensions
 		if deadLetter != nil {
-			transformers := d.dispatchExecutionInfoTransformers(dispatchExecutionInfo)
+			transformers := d.dispatchExecutionInfoTransformers(reply, attributes.KnativeErrorStageReply, dispatchExecutionInfo)
 			_, deadLetterResponse, _, dispatchExecutionInfo, deadLetterErr := d.executeRequest(ctx, deadLetter, message, responseAdditionalHeaders, retriesConfig, transformers...)
 			if deadLetterErr != nil {
 				return dispatchExecutionInfo, fmt.Errorf("failed to forward reply to %s (%v) and failed to send it to the dead letter sink %s (%v)", reply, err, deadLetter, deadLetterErr)
@@ -195,6 +228,12 @@ func (d *MessageDispatcherImpl) executeRequest(ctx context.Context,
 	ctx, span := trace.StartSpan(ctx, "knative.dev", trace.WithSpanKind(trace.SpanKindClient))
 	defer span.End()
 
+	if d.webhookValidator != nil {
+		if err := d.webhookValidator.Allow(ctx, url.String()); err != nil {
+			return ctx, nil, nil, &execInfo, err
+		}
+	}
+
 	req, err := d.sender.NewCloudEventRequestWithTarget(ctx, url.String())
 	if err != nil {
 		return ctx, nil, nil, &execInfo, err
@@ -209,9 +248,43 @@ func (d *MessageDispatcherImpl) executeRequest(ctx context.Context,
 		return ctx, nil, nil, &execInfo, err
 	}
 
+	// The circuit breaker is checked first, so that the requests to an open
+	// circuit fail right away instead of waiting for a slot.
+	var generation uint64
+	if d.circuitBreakers != nil {
+		if generation, err = d.circuitBreakers.Allow(url.Host); err != nil {
+			d.logger.Debug("Circuit breaker is open, failing the request", zap.String("url", url.String()))
+			execInfo.ResponseCode = nethttp.StatusServiceUnavailable
+			execInfo.ResponseBody = []byte(fmt.Sprintf("dispatch error: %s", err.Error()))
+			return ctx, nil, nil, &execInfo, fmt.Errorf("%s: %w", url.Host, err)
+		}
+	}
+
+	if configs != nil && configs.MaxConcurrency > 0 {
+		release, queueTime, err := d.concurrencyLimiters.Acquire(ctx, url.String(), configs.MaxConcurrency)
+		execInfo.QueueTime = queueTime
+		if err != nil {
+			if d.circuitBreakers != nil {
+				d.circuitBreakers.Cancel(url.Host, generation)
+			}
+			d.logger.Debug("Too many in-flight requests, failing the request", zap.String("url", url.String()))
+			execInfo.ResponseCode = nethttp.StatusServiceUnavailable
+			execInfo.ResponseBody = []byte(fmt.Sprintf("dispatch error: %s", err.Error()))
+			return ctx, nil, nil, &execInfo, fmt.Errorf("%s: %w", url.Host, err)
+		}
+		defer release()
+	}
+
+	var attempts kncloudevents.Attempts
 	start := time.Now()
-	response, err := d.sender.SendWithRetries(req, configs)
+	response, err := d.sender.SendWithRetriesAndAttempts(req, configs, &attempts)
 	dispatchTime := time.Since(start)
+	execInfo.Attempts = attempts.Count
+	execInfo.FirstAttemptTime = attempts.First
+	execInfo.LastAttemptTime = attempts.Last
+	if d.circuitBreakers != nil {
+		d.circuitBreakers.Done(url.Host, generation, err == nil && !isUnavailable(response.StatusCode))
+	}
 	if err != nil {
 		execInfo.Time = dispatchTime
 		execInfo.ResponseCode = nethttp.StatusInternalServerError
@@ -263,8 +336,15 @@ func (d *MessageDispatcherImpl) sanitizeURL(u *url.URL) *url.URL {
 }
 
 // dispatchExecutionTransformer returns Transformers based on the specified DispatchExecutionInfo
-func (d *MessageDispatcherImpl) dispatchExecutionInfoTransformers(dispatchExecutionInfo *DispatchExecutionInfo) binding.Transformers {
-	return attributes.KnativeErrorTransformers(dispatchExecutionInfo.ResponseCode, string(dispatchExecutionInfo.ResponseBody))
+// of the request to destination which failed at the given stage.
+func (d *MessageDispatcherImpl) dispatchExecutionInfoTransformers(destination *url.URL, stage string, dispatchExecutionInfo *DispatchExecutionInfo) binding.Transformers {
+	transformers := attributes.KnativeErrorTransformers(dispatchExecutionInfo.ResponseCode, string(dispatchExecutionInfo.ResponseBody))
+	return append(transformers, attributes.KnativeErrorAttemptsTransformers(
+		destination.String(),
+		stage,
+		dispatchExecutionInfo.Attempts,
+		dispatchExecutionInfo.FirstAttemptTime,
+		dispatchExecutionInfo.LastAttemptTime)...)
 }
 
 // isFailure returns true if the status code is not a successful HTTP status.
@@ -272,3 +352,11 @@ func isFailure(statusCode int) bool {
 	return statusCode < nethttp.StatusOK /* 200 */ ||
 		statusCode >= nethttp.StatusMultipleChoices /* 300 */
 }
+
+// isUnavailable returns true if the status code signals that the destination
+// is unable to handle requests, which counts as a failure for its circuit
+// breaker, unlike the responses rejecting a given event.
+func isUnavailable(statusCode int) bool {
+	return statusCode >= nethttp.StatusInternalServerError /* 500 */ ||
+		statusCode == nethttp.StatusTooManyRequests /* 429 */
+}
@@ -26,17 +26,23 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/cloudevents/sdk-go/v2/types"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"knative.dev/eventing/pkg/channel/attributes"
+	"knative.dev/eventing/pkg/kncloudevents"
 	"knative.dev/eventing/pkg/utils"
 )
 
@@ -59,6 +65,11 @@ var (
 		// CloudEvents headers, they will have random values, so don't bother checking them.
 		"ce-id",
 		"ce-time",
+		// The destination URIs are those of the test servers and the attempts
+		// happen at random times.
+		"ce-knativeerrordest",
+		"ce-knativeerrorfirstattempt",
+		"ce-knativeerrorlastattempt",
 		"ce-traceparent",
 	)
 )
@@ -382,18 +393,23 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDeadLetterRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id":        {"id123"},
-					"knative-1":           {"knative-1-value"},
-					"knative-2":           {"knative-2-value"},
-					"traceparent":         {"ignored-value-header"},
-					"ce-abc":              {`"ce-abc-value"`},
-					"ce-knativeerrorcode": {strconv.Itoa(http.StatusBadRequest)},
-					"ce-knativeerrordata": {"destination-response"},
-					"ce-id":               {"ignored-value-header"},
-					"ce-time":             {"2002-10-02T15:00:00Z"},
-					"ce-source":           {testCeSource},
-					"ce-type":             {testCeType},
-					"ce-specversion":      {cloudevents.VersionV1},
+					"x-request-id":                {"id123"},
+					"knative-1":                   {"knative-1-value"},
+					"knative-2":                   {"knative-2-value"},
+					"traceparent":                 {"ignored-value-header"},
+					"ce-abc":                      {`"ce-abc-value"`},
+					"ce-knativeerrorcode":         {strconv.Itoa(http.StatusBadRequest)},
+					"ce-knativeerrordata":         {"destination-response"},
+					"ce-knativeerrordest":         {"ignored-value-header"},
+					"ce-knativeerrorstage":        {"subscriber"},
+					"ce-knativeerrorattempts":     {"1"},
+					"ce-knativeerrorfirstattempt": {"ignored-value-header"},
+					"ce-knativeerrorlastattempt":  {"ignored-value-header"},
+					"ce-id":                       {"ignored-value-header"},
+					"ce-time":                     {"2002-10-02T15:00:00Z"},
+					"ce-source":                   {testCeSource},
+					"ce-type":                     {testCeType},
+					"ce-specversion":              {cloudevents.VersionV1},
 				},
 				Body: `"destination"`,
 			},
@@ -449,18 +465,23 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDeadLetterRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id":        {"id123"},
-					"knative-1":           {"knative-1-value"},
-					"knative-2":           {"knative-2-value"},
-					"traceparent":         {"ignored-value-header"},
-					"ce-abc":              {`"ce-abc-value"`},
-					"ce-id":               {"ignored-value-header"},
-					"ce-knativeerrorcode": {strconv.Itoa(http.StatusBadRequest)},
-					"ce-knativeerrordata": {"destination-response"},
-					"ce-time":             {"2002-10-02T15:00:00Z"},
-					"ce-source":           {testCeSource},
-					"ce-type":             {testCeType},
-					"ce-specversion":      {cloudevents.VersionV1},
+					"x-request-id":                {"id123"},
+					"knative-1":                   {"knative-1-value"},
+					"knative-2":                   {"knative-2-value"},
+					"traceparent":                 {"ignored-value-header"},
+					"ce-abc":                      {`"ce-abc-value"`},
+					"ce-id":                       {"ignored-value-header"},
+					"ce-knativeerrorcode":         {strconv.Itoa(http.StatusBadRequest)},
+					"ce-knativeerrordata":         {"destination-response"},
+					"ce-knativeerrordest":         {"ignored-value-header"},
+					"ce-knativeerrorstage":        {"subscriber"},
+					"ce-knativeerrorattempts":     {"1"},
+					"ce-knativeerrorfirstattempt": {"ignored-value-header"},
+					"ce-knativeerrorlastattempt":  {"ignored-value-header"},
+					"ce-time":                     {"2002-10-02T15:00:00Z"},
+					"ce-source":                   {testCeSource},
+					"ce-type":                     {testCeType},
+					"ce-specversion":              {cloudevents.VersionV1},
 				},
 				Body: `"destination"`,
 			},
@@ -505,18 +526,23 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDeadLetterRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id":        {"id123"},
-					"knative-1":           {"knative-1-value"},
-					"knative-2":           {"knative-2-value"},
-					"traceparent":         {"ignored-value-header"},
-					"ce-abc":              {`"ce-abc-value"`},
-					"ce-id":               {"ignored-value-header"},
-					"ce-knativeerrorcode": {strconv.Itoa(http.StatusBadRequest)},
-					"ce-knativeerrordata": {"destination-response"},
-					"ce-time":             {"2002-10-02T15:00:00Z"},
-					"ce-source":           {testCeSource},
-					"ce-type":             {testCeType},
-					"ce-specversion":      {cloudevents.VersionV1},
+					"x-request-id":                {"id123"},
+					"knative-1":                   {"knative-1-value"},
+					"knative-2":                   {"knative-2-value"},
+					"traceparent":                 {"ignored-value-header"},
+					"ce-abc":                      {`"ce-abc-value"`},
+					"ce-id":                       {"ignored-value-header"},
+					"ce-knativeerrorcode":         {strconv.Itoa(http.StatusBadRequest)},
+					"ce-knativeerrordata":         {"destination-response"},
+					"ce-knativeerrordest":         {"ignored-value-header"},
+					"ce-knativeerrorstage":        {"reply"},
+					"ce-knativeerrorattempts":     {"1"},
+					"ce-knativeerrorfirstattempt": {"ignored-value-header"},
+					"ce-knativeerrorlastattempt":  {"ignored-value-header"},
+					"ce-time":                     {"2002-10-02T15:00:00Z"},
+					"ce-source":                   {testCeSource},
+					"ce-type":                     {testCeType},
+					"ce-specversion":              {cloudevents.VersionV1},
 				},
 				Body: `"destination"`,
 			},
@@ -576,17 +602,22 @@ func TestDispatchMessage(t *testing.T) {
 			},
 			expectedDeadLetterRequest: &requestValidation{
 				Headers: map[string][]string{
-					"x-request-id":        {"altered-id"},
-					"knative-1":           {"new-knative-1-value"},
-					"traceparent":         {"ignored-value-header"},
-					"ce-abc":              {`"ce-abc-value"`},
-					"ce-id":               {"ignored-value-header"},
-					"ce-knativeerrorcode": {strconv.Itoa(http.StatusBadRequest)},
-					"ce-knativeerrordata": {"reply-response"},
-					"ce-time":             {"2002-10-02T15:00:00Z"},
-					"ce-source":           {testCeSource},
-					"ce-type":             {testCeType},
-					"ce-specversion":      {cloudevents.VersionV1},
+					"x-request-id":                {"altered-id"},
+					"knative-1":                   {"new-knative-1-value"},
+					"traceparent":                 {"ignored-value-header"},
+					"ce-abc":                      {`"ce-abc-value"`},
+					"ce-id":                       {"ignored-value-header"},
+					"ce-knativeerrorcode":         {strconv.Itoa(http.StatusBadRequest)},
+					"ce-knativeerrordata":         {"reply-response"},
+					"ce-knativeerrordest":         {"ignored-value-header"},
+					"ce-knativeerrorstage":        {"reply"},
+					"ce-knativeerrorattempts":     {"1"},
+					"ce-knativeerrorfirstattempt": {"ignored-value-header"},
+					"ce-knativeerrorlastattempt":  {"ignored-value-header"},
+					"ce-time":                     {"2002-10-02T15:00:00Z"},
+					"ce-source":                   {testCeSource},
+					"ce-type":                     {testCeType},
+					"ce-specversion":              {cloudevents.VersionV1},
 				},
 				Body: `"destination"`,
 			},
@@ -748,6 +779,244 @@ func TestDispatchMessage(t *testing.T) {
 	}
 }
 
+func TestDispatchMessage_WebhookValidation(t *testing.T) {
+	tests := map[string]struct {
+		allowedOrigin string
+		wantErr       bool
+		wantEvents    int
+	}{
+		"allowed": {
+			allowedOrigin: "dispatcher",
+			wantEvents:    2,
+		},
+		"not allowed": {
+			allowedOrigin: "other",
+			wantErr:       true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var handshakes, events int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodOptions {
+					handshakes++
+					w.Header().Set(kncloudevents.WebhookAllowedOriginHeader, tc.allowedOrigin)
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+				events++
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer server.Close()
+
+			md := NewMessageDispatcher(zaptest.NewLogger(t))
+			md.EnableWebhookValidation(kncloudevents.NewWebhookValidator(server.Client(), "dispatcher"))
+
+			destination, _ := url.Parse(server.URL)
+			for i := 0; i < 2; i++ {
+				event := cloudevents.NewEvent(cloudevents.VersionV1)
+				event.SetID(uuid.New().String())
+				event.SetType(testCeType)
+				event.SetSource(testCeSource)
+
+				_, err := md.DispatchMessage(context.Background(), binding.ToMessage(&event), nil, destination, nil, nil)
+				if (err != nil) != tc.wantErr {
+					t.Errorf("DispatchMessage() = %v, wantErr %v", err, tc.wantErr)
+				}
+			}
+			if handshakes != 1 {
+				t.Errorf("Expected a single handshake, got %d", handshakes)
+			}
+			if events != tc.wantEvents {
+				t.Errorf("Expected %d events, got %d", tc.wantEvents, events)
+			}
+		})
+	}
+}
+
+func TestDispatchMessage_CircuitBreaker(t *testing.T) {
+	var destinationRequests, deadLetterRequests int
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		destinationRequests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer destination.Close()
+	deadLetter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadLetterRequests++
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetter.Close()
+
+	logger := zaptest.NewLogger(t)
+	md := NewMessageDispatcher(logger)
+	md.EnableCircuitBreakers(NewCircuitBreakers(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Hour,
+	}, nil, logger))
+
+	destinationURL, _ := url.Parse(destination.URL)
+	deadLetterURL, _ := url.Parse(deadLetter.URL)
+	for i := 0; i < 4; i++ {
+		event := cloudevents.NewEvent(cloudevents.VersionV1)
+		event.SetID(uuid.New().String())
+		event.SetType(testCeType)
+		event.SetSource(testCeSource)
+
+		info, err := md.DispatchMessage(context.Background(), binding.ToMessage(&event), nil, destinationURL, nil, deadLetterURL)
+		if err != nil {
+			t.Fatal("DispatchMessage() =", err)
+		}
+		if info.ResponseCode != http.StatusAccepted {
+			t.Errorf("Expected the event to be sent to the dead letter sink, got %d", info.ResponseCode)
+		}
+	}
+	// The circuit opens after two failures, the other events go straight
+	// to the dead letter sink.
+	if destinationRequests != 2 {
+		t.Errorf("Expected 2 destination requests, got %d", destinationRequests)
+	}
+	if deadLetterRequests != 4 {
+		t.Errorf("Expected 4 dead letter sink requests, got %d", deadLetterRequests)
+	}
+
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	event.SetID(uuid.New().String())
+	event.SetType(testCeType)
+	event.SetSource(testCeSource)
+	if _, err := md.DispatchMessage(context.Background(), binding.ToMessage(&event), nil, destinationURL, nil, nil); err == nil || !strings.Contains(err.Error(), ErrCircuitOpen.Error()) {
+		t.Errorf("DispatchMessage() = %v, want %v", err, ErrCircuitOpen)
+	}
+}
+
+func TestDispatchMessage_DeadLetterAttempts(t *testing.T) {
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer destination.Close()
+	var deadLettered *cloudevents.Event
+	deadLetter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadLettered, _ = binding.ToEvent(context.Background(), cehttp.NewMessageFromHttpRequest(r))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetter.Close()
+
+	md := NewMessageDispatcher(zaptest.NewLogger(t))
+	destinationURL, _ := url.Parse(destination.URL)
+	deadLetterURL, _ := url.Parse(deadLetter.URL)
+	retryConfig := &kncloudevents.RetryConfig{
+		RetryMax: 2,
+		CheckRetry: func(_ context.Context, resp *http.Response, err error) (bool, error) {
+			return resp == nil || resp.StatusCode >= http.StatusInternalServerError, err
+		},
+		Backoff: func(attemptNum int, resp *http.Response) time.Duration {
+			return time.Millisecond
+		},
+	}
+
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	event.SetID(uuid.New().String())
+	event.SetType(testCeType)
+	event.SetSource(testCeSource)
+	if _, err := md.DispatchMessageWithRetries(context.Background(), binding.ToMessage(&event), nil, destinationURL, nil, deadLetterURL, retryConfig); err != nil {
+		t.Fatal("DispatchMessageWithRetries() =", err)
+	}
+	if deadLettered == nil {
+		t.Fatal("Expected the event to be sent to the dead letter sink")
+	}
+
+	extensions := deadLettered.Extensions()
+	if got := extensions[attributes.KnativeErrorDestExtensionKey]; got != destinationURL.String() {
+		t.Errorf("Unexpected %s %v, want %s", attributes.KnativeErrorDestExtensionKey, got, destinationURL)
+	}
+	if got := extensions[attributes.KnativeErrorStageExtensionKey]; got != attributes.KnativeErrorStageSubscriber {
+		t.Errorf("Unexpected %s %v, want %s", attributes.KnativeErrorStageExtensionKey, got, attributes.KnativeErrorStageSubscriber)
+	}
+	if got, err := types.ToInteger(extensions[attributes.KnativeErrorAttemptsExtensionKey]); err != nil || got != 3 {
+		t.Errorf("Unexpected %s %v, want 3", attributes.KnativeErrorAttemptsExtensionKey, extensions[attributes.KnativeErrorAttemptsExtensionKey])
+	}
+	firstAttempt, err := types.ToTime(extensions[attributes.KnativeErrorFirstAttemptExtensionKey])
+	if err != nil {
+		t.Fatalf("Invalid %s: %v", attributes.KnativeErrorFirstAttemptExtensionKey, err)
+	}
+	lastAttempt, err := types.ToTime(extensions[attributes.KnativeErrorLastAttemptExtensionKey])
+	if err != nil {
+		t.Fatalf("Invalid %s: %v", attributes.KnativeErrorLastAttemptExtensionKey, err)
+	}
+	if !lastAttempt.After(firstAttempt) {
+		t.Errorf("Expected the last attempt %v to be after the first attempt %v", lastAttempt, firstAttempt)
+	}
+}
+
+func TestDispatchMessage_MaxConcurrency(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-unblock
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer destination.Close()
+	var deadLetterRequests int32
+	deadLetter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&deadLetterRequests, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetter.Close()
+
+	md := NewMessageDispatcher(zaptest.NewLogger(t))
+	md.concurrencyLimiters = kncloudevents.NewConcurrencyLimiters(50 * time.Millisecond)
+	destinationURL, _ := url.Parse(destination.URL)
+	deadLetterURL, _ := url.Parse(deadLetter.URL)
+	retryConfig := kncloudevents.NoRetries()
+	retryConfig.MaxConcurrency = 1
+
+	dispatch := func() (*DispatchExecutionInfo, error) {
+		event := cloudevents.NewEvent(cloudevents.VersionV1)
+		event.SetID(uuid.New().String())
+		event.SetType(testCeType)
+		event.SetSource(testCeSource)
+		return md.DispatchMessageWithRetries(context.Background(), binding.ToMessage(&event), nil, destinationURL, nil, deadLetterURL, &retryConfig)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := dispatch()
+		done <- err
+	}()
+	<-started
+
+	// The destination is busy with the first event, the second one waits
+	// for too long and goes to the dead letter sink.
+	if _, err := dispatch(); err != nil {
+		t.Fatal("DispatchMessageWithRetries() =", err)
+	}
+	if got := atomic.LoadInt32(&deadLetterRequests); got != 1 {
+		t.Errorf("Expected 1 dead letter sink request, got %d", got)
+	}
+
+	// The third event waits for the first one to complete.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(unblock)
+	}()
+	go func() {
+		<-started
+	}()
+	info, err := dispatch()
+	if err != nil {
+		t.Fatal("DispatchMessageWithRetries() =", err)
+	}
+	if info.QueueTime == 0 {
+		t.Error("Expected the event to wait for the in-flight request")
+	}
+	if err := <-done; err != nil {
+		t.Fatal("DispatchMessageWithRetries() =", err)
+	}
+	if got := atomic.LoadInt32(&deadLetterRequests); got != 1 {
+		t.Errorf("Expected 1 dead letter sink request, got %d", got)
+	}
+}
+
 func getOnlyDomainURL(t *testing.T, shouldSend bool, serverURL string) *url.URL {
 	if shouldSend {
 		server, err := url.Parse(serverURL)
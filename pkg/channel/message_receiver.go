@@ -136,6 +136,10 @@ func (r *MessageReceiver) Start(ctx context.Context) error {
 }
 
 func (r *MessageReceiver) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
+	if kncloudevents.IsWebhookValidationRequest(request) {
+		kncloudevents.WriteWebhookValidationResponse(response, request)
+		return
+	}
 	if request.Method != nethttp.MethodPost {
 		response.WriteHeader(nethttp.StatusMethodNotAllowed)
 		return
@@ -171,6 +175,11 @@ func (r *MessageReceiver) ServeHTTP(response nethttp.ResponseWriter, request *ne
 
 	args.Ns = channel.Namespace
 
+	if kncloudevents.IsBatchRequest(request) {
+		r.serveBatch(response, request, channel, &args)
+		return
+	}
+
 	message := http.NewMessageFromHttpRequest(request)
 	if message.ReadEncoding() == binding.EncodingUnknown {
 		r.logger.Info("Cannot determine the cloudevent message encoding")
@@ -178,17 +187,59 @@ func (r *MessageReceiver) ServeHTTP(response nethttp.ResponseWriter, request *ne
 		r.reporter.ReportEventCount(&args, nethttp.StatusBadRequest)
 		return
 	}
-	err = r.receiverFunc(request.Context(), channel, message, []binding.Transformer{}, utils.PassThroughHeaders(request.Header))
+	statusCode, _ := r.receive(request.Context(), channel, message, utils.PassThroughHeaders(request.Header))
+	response.WriteHeader(statusCode)
+}
+
+// receive passes the message to the receiver function, which finishes it and
+// reports its dispatch, and returns the status code it is answered with.
+func (r *MessageReceiver) receive(ctx context.Context, channel ChannelReference, message binding.Message, headers nethttp.Header) (int, error) {
+	err := r.receiverFunc(ctx, channel, message, []binding.Transformer{}, headers)
 	if err != nil {
 		if _, ok := err.(*UnknownChannelError); ok {
-			response.WriteHeader(nethttp.StatusNotFound)
-		} else {
-			r.logger.Info("Error in receiver", zap.Error(err))
-			response.WriteHeader(nethttp.StatusInternalServerError)
+			return nethttp.StatusNotFound, err
 		}
+		r.logger.Info("Error in receiver", zap.Error(err))
+		return nethttp.StatusInternalServerError, err
+	}
+	return nethttp.StatusAccepted, nil
+}
+
+// serveBatch passes each event of a batch to the receiver function, and
+// responds with the outcome of each of them. Each event is received, and
+// reported, as if it had been sent on its own.
+func (r *MessageReceiver) serveBatch(response nethttp.ResponseWriter, request *nethttp.Request, channel ChannelReference, args *ReportArgs) {
+	events, errs, err := kncloudevents.ReadBatch(response, request)
+	if err != nil {
+		r.logger.Info("Cannot read the batch of cloudevents", zap.Error(err))
+		statusCode := nethttp.StatusBadRequest
+		if err == kncloudevents.ErrBatchTooLarge {
+			statusCode = nethttp.StatusRequestEntityTooLarge
+		}
+		response.WriteHeader(statusCode)
+		r.reporter.ReportEventCount(args, statusCode)
 		return
 	}
-	response.WriteHeader(nethttp.StatusAccepted)
+
+	headers := utils.PassThroughHeaders(request.Header)
+	results := make([]kncloudevents.BatchResult, len(events))
+	for i, event := range events {
+		if event == nil {
+			results[i] = kncloudevents.BatchResult{Status: nethttp.StatusBadRequest, Error: errs[i].Error()}
+			r.reporter.ReportEventCount(args, nethttp.StatusBadRequest)
+			continue
+		}
+		message := binding.ToMessage(event)
+		results[i] = kncloudevents.BatchResult{ID: event.ID()}
+		results[i].Status, err = r.receive(request.Context(), channel, message, headers)
+		if err != nil {
+			results[i].Error = err.Error()
+		}
+		// Finishing an event message is idempotent, so this is safe even if
+		// the receiver function already did.
+		_ = message.Finish(err)
+	}
+	kncloudevents.WriteBatchResponse(response, results)
 }
 
 func ReportEventCountMetricsForDispatchError(err error, reporter StatsReporter, args *ReportArgs) {
@@ -19,6 +19,7 @@ package channel
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	nethttp "net/http"
@@ -302,3 +303,81 @@ func TestMessageReceiver_UnknownHost(t *testing.T) {
 		t.Fatal("Unexpected status code. Expected 404. Actual", res.Code)
 	}
 }
+
+func TestMessageReceiver_Batch(t *testing.T) {
+	host := "http://test-channel.test-namespace.svc." + network.GetClusterDomainName() + "/"
+	reporter := NewStatsReporter("testcontainer", "testpod")
+
+	var received []string
+	f := func(ctx context.Context, _ ChannelReference, m binding.Message, _ []binding.Transformer, _ nethttp.Header) error {
+		e, err := binding.ToEvent(ctx, m)
+		if err != nil {
+			return err
+		}
+		received = append(received, e.ID())
+		if e.ID() == "2" {
+			return errors.New("test induced receiver function error")
+		}
+		return nil
+	}
+	r, err := NewMessageReceiver(f, zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller())), reporter)
+	if err != nil {
+		t.Fatalf("Error creating new event receiver. Error:%s", err)
+	}
+
+	body := `[
+		{"specversion": "1.0", "id": "1", "type": "type", "source": "/source"},
+		{"specversion": "1.0", "id": "2", "type": "type", "source": "/source"},
+		{"specversion": "1.0", "id": "3", "source": "/source"}
+	]`
+	req := httptest.NewRequest(nethttp.MethodPost, "http://localhost:8080/", bytes.NewReader([]byte(body)))
+	req.Host = host
+	req.Header.Set("content-type", cloudevents.ApplicationCloudEventsBatchJSON)
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	if res.Code != nethttp.StatusMultiStatus {
+		t.Fatal("Unexpected status code. Expected 207. Actual", res.Code)
+	}
+	if diff := cmp.Diff([]string{"1", "2"}, received); diff != "" {
+		t.Error("Unexpected received events (-want, +got):", diff)
+	}
+
+	var response kncloudevents.BatchResponse
+	if err := json.Unmarshal(res.Body.Bytes(), &response); err != nil {
+		t.Fatal("Failed to decode the response:", err)
+	}
+	statuses := make([]int, 0, len(response.Results))
+	for _, result := range response.Results {
+		statuses = append(statuses, result.Status)
+	}
+	if diff := cmp.Diff([]int{nethttp.StatusAccepted, nethttp.StatusInternalServerError, nethttp.StatusBadRequest}, statuses); diff != "" {
+		t.Error("Unexpected statuses (-want, +got):", diff)
+	}
+}
+
+func TestMessageReceiver_WebhookValidation(t *testing.T) {
+	reporter := NewStatsReporter("testcontainer", "testpod")
+	host := "http://test-channel.test-namespace.svc." + network.GetClusterDomainName() + "/"
+
+	f := func(_ context.Context, _ ChannelReference, _ binding.Message, _ []binding.Transformer, _ nethttp.Header) error {
+		t.Error("Unexpected call to the receiver function")
+		return nil
+	}
+	r, err := NewMessageReceiver(f, zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller())), reporter)
+	if err != nil {
+		t.Fatalf("Error creating new event receiver. Error:%s", err)
+	}
+
+	req := httptest.NewRequest(nethttp.MethodOptions, host, nil)
+	req.Header.Set(kncloudevents.WebhookRequestOriginHeader, "eventemitter.example.com")
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	if res.Code != nethttp.StatusOK {
+		t.Fatal("Unexpected status code. Expected 200. Actual", res.Code)
+	}
+	if got := res.Header().Get(kncloudevents.WebhookAllowedOriginHeader); got != "eventemitter.example.com" {
+		t.Errorf("Unexpected %s %q", kncloudevents.WebhookAllowedOriginHeader, got)
+	}
+}
@@ -24,9 +24,14 @@ const (
 
 	// LabelContainerName is the label for the immutable name of the container.
 	LabelContainerName = "container_name"
+
+	// LabelDestinationHost is the label for the host events are dispatched to.
+	LabelDestinationHost = "destination_host"
 )
 
 var (
 	ContainerTagKey = tag.MustNewKey(LabelContainerName)
 	UniqueTagKey    = tag.MustNewKey(LabelUniqueName)
+
+	DestinationHostTagKey = tag.MustNewKey(LabelDestinationHost)
 )
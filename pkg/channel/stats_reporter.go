@@ -46,6 +46,22 @@ var (
 		stats.UnitMilliseconds,
 	)
 
+	// queueTimeInMsecM records the time spent by an event waiting for the
+	// in-flight requests to its destination to complete, in milliseconds.
+	queueTimeInMsecM = stats.Float64(
+		"event_queue_latencies",
+		"The time spent by an event waiting for the in-flight requests to its destination to complete",
+		stats.UnitMilliseconds,
+	)
+
+	// circuitBreakerStateM records the state of the circuit breaker of a
+	// destination host: 0 when closed, 1 when open and 2 when half-open.
+	circuitBreakerStateM = stats.Int64(
+		"circuit_breaker_state",
+		"The state of the circuit breaker of a destination: 0 closed, 1 open, 2 half-open",
+		stats.UnitDimensionless,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -69,6 +85,8 @@ func init() {
 type StatsReporter interface {
 	ReportEventCount(args *ReportArgs, responseCode int) error
 	ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error
+	ReportEventQueueTime(args *ReportArgs, d time.Duration) error
+	ReportCircuitBreakerState(host string, state CircuitState) error
 }
 
 var _ StatsReporter = (*reporter)(nil)
@@ -112,6 +130,18 @@ func register() {
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 500, 1000, 5000, 10000
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Description: queueTimeInMsecM.Description(),
+			Measure:     queueTimeInMsecM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 500, 1000, 5000, 10000
+			TagKeys:     []tag.Key{namespaceKey, eventTypeKey, UniqueTagKey, ContainerTagKey},
+		},
+		&view.View{
+			Description: circuitBreakerStateM.Description(),
+			Measure:     circuitBreakerStateM,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{DestinationHostTagKey, UniqueTagKey, ContainerTagKey},
+		},
 	)
 	if err != nil {
 		log.Print("failed to register opencensus views, " + err.Error())
@@ -139,6 +169,37 @@ func (r *reporter) ReportEventDispatchTime(args *ReportArgs, responseCode int, d
 	return nil
 }
 
+// ReportEventQueueTime captures the time spent waiting for the in-flight
+// requests to the destination to complete.
+func (r *reporter) ReportEventQueueTime(args *ReportArgs, d time.Duration) error {
+	ctx, err := tag.New(
+		emptyContext,
+		tag.Insert(namespaceKey, args.Ns),
+		tag.Insert(eventTypeKey, args.EventType),
+		tag.Insert(ContainerTagKey, r.container),
+		tag.Insert(UniqueTagKey, r.uniqueName))
+	if err != nil {
+		return err
+	}
+	// convert Time.Duration in nanoseconds to milliseconds.
+	metrics.Record(ctx, queueTimeInMsecM.M(float64(d/time.Millisecond)))
+	return nil
+}
+
+// ReportCircuitBreakerState captures the state of the circuit breaker of host.
+func (r *reporter) ReportCircuitBreakerState(host string, state CircuitState) error {
+	ctx, err := tag.New(
+		emptyContext,
+		tag.Insert(DestinationHostTagKey, host),
+		tag.Insert(ContainerTagKey, r.container),
+		tag.Insert(UniqueTagKey, r.uniqueName))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, circuitBreakerStateM.M(int64(state)))
+	return nil
+}
+
 func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
 	return tag.New(
 		emptyContext,
@@ -61,6 +61,30 @@ func TestStatsReporter(t *testing.T) {
 		return r.ReportEventDispatchTime(args, http.StatusAccepted, 9100*time.Millisecond)
 	})
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
+
+	// test ReportEventQueueTime
+	expectSuccess(t, func() error {
+		return r.ReportEventQueueTime(args, 20*time.Millisecond)
+	})
+	expectSuccess(t, func() error {
+		return r.ReportEventQueueTime(args, 500*time.Millisecond)
+	})
+	metricstest.CheckDistributionData(t, "event_queue_latencies", map[string]string{
+		metricskey.LabelNamespaceName: "testns",
+		metricskey.LabelEventType:     "testeventtype",
+		LabelUniqueName:               "testpod",
+		LabelContainerName:            "testcontainer",
+	}, 2, 20.0, 500.0)
+
+	// test ReportCircuitBreakerState
+	expectSuccess(t, func() error {
+		return r.ReportCircuitBreakerState("subscriber.example.com", CircuitOpen)
+	})
+	metricstest.CheckLastValueData(t, "circuit_breaker_state", map[string]string{
+		LabelDestinationHost: "subscriber.example.com",
+		LabelUniqueName:      "testpod",
+		LabelContainerName:   "testcontainer",
+	}, 1)
 }
 
 func expectSuccess(t *testing.T, f func() error) {
@@ -78,6 +102,8 @@ func resetMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
 	metricstest.Unregister(
 		"event_count",
-		"event_dispatch_latencies")
+		"event_dispatch_latencies",
+		"event_queue_latencies",
+		"circuit_breaker_state")
 	register()
 }
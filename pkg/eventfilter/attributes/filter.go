@@ -18,8 +18,10 @@ package attributes
 
 import (
 	"context"
+	"fmt"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
 	"go.uber.org/zap"
 	"knative.dev/pkg/logging"
 
@@ -35,6 +37,117 @@ func NewAttributesFilter(attrs map[string]string) eventfilter.Filter {
 }
 
 func (attrs attributesFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
+	if m, ok := attrs.match(event); !ok {
+		m.log(ctx)
+		return eventfilter.FailFilter
+	}
+	return eventfilter.PassFilter
+}
+
+func (attrs attributesFilter) Explain(ctx context.Context, event cloudevents.Event) eventfilter.Explanation {
+	if m, ok := attrs.match(event); !ok {
+		m.log(ctx)
+		return m.explain()
+	}
+	return eventfilter.Explanation{Result: eventfilter.PassFilter}
+}
+
+func (attrs attributesFilter) match(event cloudevents.Event) (mismatch, bool) {
+	ce := contextAttributes(event)
+
+	for k, v := range attrs {
+		var value interface{}
+		value, ok := ce[k]
+		// If the attribute does not exist in the event, return false.
+		if !ok {
+			return mismatch{attribute: k}, false
+		}
+		// If the attribute is not set to any and is different than the one from the event, return false.
+		if v != eventingv1beta1.TriggerAnyFilter && v != value {
+			return mismatch{attribute: k, found: true, value: value, dialect: "exact", expected: v}, false
+		}
+	}
+	return mismatch{}, true
+}
+
+var _ eventfilter.Filter = attributesFilter{}
+var _ eventfilter.Explainer = attributesFilter{}
+
+// mismatch describes the attribute which made an attributes filter fail.
+type mismatch struct {
+	attribute string
+	// found is false if the event doesn't have the attribute.
+	found bool
+	value interface{}
+	// dialect is the kind of match, e.g. exact or prefix.
+	dialect  string
+	expected string
+}
+
+func (m mismatch) log(ctx context.Context) {
+	if !m.found {
+		logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", m.attribute))
+		return
+	}
+	logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", m.attribute), zap.String("filter", m.expected), zap.Any("received", m.value))
+}
+
+func (m mismatch) explain() eventfilter.Explanation {
+	reason := "attribute not found"
+	if m.found {
+		reason = fmt.Sprintf("value %q does not match the %s filter %q", fmt.Sprint(m.value), m.dialect, m.expected)
+	}
+	return eventfilter.Explanation{
+		Result:    eventfilter.FailFilter,
+		Attribute: m.attribute,
+		Reason:    reason,
+	}
+}
+
+// matchAttributes passes if, for every attribute in attrs, the event has the attribute
+// and match returns true for its string value and the expected value. The dialect
+// names the kind of match in the returned mismatch.
+func matchAttributes(event cloudevents.Event, attrs map[string]string, dialect string, match func(value, expected string) bool) (eventfilter.FilterResult, mismatch) {
+	if len(attrs) == 0 {
+		return eventfilter.NoFilter, mismatch{}
+	}
+	ce := contextAttributes(event)
+
+	for k, v := range attrs {
+		value, ok := ce[k]
+		if !ok {
+			return eventfilter.FailFilter, mismatch{attribute: k}
+		}
+		s, err := types.Format(value)
+		if err != nil || !match(s, v) {
+			return eventfilter.FailFilter, mismatch{attribute: k, found: true, value: value, dialect: dialect, expected: v}
+		}
+	}
+	return eventfilter.PassFilter, mismatch{}
+}
+
+// filterMatch runs matchAttributes and logs the mismatch, if any.
+func filterMatch(ctx context.Context, event cloudevents.Event, attrs map[string]string, dialect string, match func(value, expected string) bool) eventfilter.FilterResult {
+	res, m := matchAttributes(event, attrs, dialect, match)
+	if res == eventfilter.FailFilter {
+		m.log(ctx)
+	}
+	return res
+}
+
+// explainMatch runs matchAttributes and explains the result.
+func explainMatch(ctx context.Context, event cloudevents.Event, attrs map[string]string, dialect string, match func(value, expected string) bool) eventfilter.Explanation {
+	res, m := matchAttributes(event, attrs, dialect, match)
+	if res == eventfilter.FailFilter {
+		m.log(ctx)
+		return m.explain()
+	}
+	return eventfilter.Explanation{Result: res}
+}
+
+// contextAttributes returns the context attributes and the extensions of the
+// event, keyed by their name.
+func contextAttributes(event cloudevents.Event) map[string]interface{} {
 	// Set standard context attributes. The attributes available may not be
 	// exactly the same as the attributes defined in the current version of the
 	// CloudEvents spec.
@@ -55,22 +168,5 @@ func (attrs attributesFilter) Filter(ctx context.Context, event cloudevents.Even
 	for k, v := range ext {
 		ce[k] = v
 	}
-
-	for k, v := range attrs {
-		var value interface{}
-		value, ok := ce[k]
-		// If the attribute does not exist in the event, return false.
-		if !ok {
-			logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", k))
-			return eventfilter.FailFilter
-		}
-		// If the attribute is not set to any and is different than the one from the event, return false.
-		if v != eventingv1beta1.TriggerAnyFilter && v != value {
-			logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", k), zap.String("filter", v), zap.Any("received", value))
-			return eventfilter.FailFilter
-		}
-	}
-	return eventfilter.PassFilter
+	return ce
 }
-
-var _ eventfilter.Filter = attributesFilter{}
@@ -97,6 +97,41 @@ func TestAttributesFilter_Filter(t *testing.T) {
 	}
 }
 
+func TestAttributesFilter_Explain(t *testing.T) {
+	tests := map[string]struct {
+		filter map[string]string
+		want   eventfilter.Explanation
+	}{
+		"Specific": {
+			filter: attributes(eventType, eventSource),
+			want:   eventfilter.Explanation{Result: eventfilter.PassFilter},
+		},
+		"Wrong type": {
+			filter: attributes("some-other-type", ""),
+			want: eventfilter.Explanation{
+				Result:    eventfilter.FailFilter,
+				Attribute: "type",
+				Reason:    `value "com.example.someevent" does not match the exact filter "some-other-type"`,
+			},
+		},
+		"Missing extension": {
+			filter: attributesWithExtension("", "", extensionValue),
+			want: eventfilter.Explanation{
+				Result:    eventfilter.FailFilter,
+				Attribute: extensionName,
+				Reason:    "attribute not found",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := eventfilter.Explain(context.TODO(), NewAttributesFilter(tt.filter), *makeEvent()); got != tt.want {
+				t.Errorf("Explain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func makeEvent() *cloudevents.Event {
 	e := cloudevents.NewEvent()
 	e.SetType(eventType)
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+func TestPrefixFilter_Filter(t *testing.T) {
+	tests := map[string]struct {
+		filter map[string]string
+		event  *cloudevents.Event
+		want   eventfilter.FilterResult
+	}{
+		"Empty": {
+			filter: map[string]string{},
+			want:   eventfilter.NoFilter,
+		},
+		"Matching type prefix": {
+			filter: map[string]string{"type": "com.example."},
+			want:   eventfilter.PassFilter,
+		},
+		"Exact type": {
+			filter: map[string]string{"type": eventType},
+			want:   eventfilter.PassFilter,
+		},
+		"Wrong type prefix": {
+			filter: map[string]string{"type": "org.example."},
+			want:   eventfilter.FailFilter,
+		},
+		"Matching type and source prefixes": {
+			filter: map[string]string{"type": "com.example.", "source": "/my"},
+			want:   eventfilter.PassFilter,
+		},
+		"Wrong source prefix": {
+			filter: map[string]string{"type": "com.example.", "source": "/other"},
+			want:   eventfilter.FailFilter,
+		},
+		"Matching extension prefix": {
+			filter: map[string]string{extensionName: "my-"},
+			event:  makeEventWithExtension(extensionName, extensionValue),
+			want:   eventfilter.PassFilter,
+		},
+		"Missing extension": {
+			filter: map[string]string{extensionName: "my-"},
+			want:   eventfilter.FailFilter,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := tt.event
+			if e == nil {
+				e = makeEvent()
+			}
+
+			if got := NewPrefixFilter(tt.filter).Filter(context.TODO(), *e); got != tt.want {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixFilter_Explain(t *testing.T) {
+	tests := map[string]struct {
+		filter map[string]string
+		want   eventfilter.Explanation
+	}{
+		"Empty": {
+			filter: map[string]string{},
+			want:   eventfilter.Explanation{Result: eventfilter.NoFilter},
+		},
+		"Matching type prefix": {
+			filter: map[string]string{"type": "com.example."},
+			want:   eventfilter.Explanation{Result: eventfilter.PassFilter},
+		},
+		"Wrong type prefix": {
+			filter: map[string]string{"type": "org.example."},
+			want: eventfilter.Explanation{
+				Result:    eventfilter.FailFilter,
+				Attribute: "type",
+				Reason:    `value "com.example.someevent" does not match the prefix filter "org.example."`,
+			},
+		},
+		"Missing extension": {
+			filter: map[string]string{extensionName: "my-"},
+			want: eventfilter.Explanation{
+				Result:    eventfilter.FailFilter,
+				Attribute: extensionName,
+				Reason:    "attribute not found",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := eventfilter.Explain(context.TODO(), NewPrefixFilter(tt.filter), *makeEvent()); got != tt.want {
+				t.Errorf("Explain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+type suffixFilter map[string]string
+
+// NewSuffixFilter returns an event filter which passes if the value of each attribute ends with the given suffix
+func NewSuffixFilter(attrs map[string]string) eventfilter.Filter {
+	return suffixFilter(attrs)
+}
+
+func (attrs suffixFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
+	return filterMatch(ctx, event, attrs, "suffix", strings.HasSuffix)
+}
+
+func (attrs suffixFilter) Explain(ctx context.Context, event cloudevents.Event) eventfilter.Explanation {
+	return explainMatch(ctx, event, attrs, "suffix", strings.HasSuffix)
+}
+
+var _ eventfilter.Filter = suffixFilter{}
+var _ eventfilter.Explainer = suffixFilter{}
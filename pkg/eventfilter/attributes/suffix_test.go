@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attributes
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+func TestSuffixFilter_Filter(t *testing.T) {
+	tests := map[string]struct {
+		filter map[string]string
+		event  *cloudevents.Event
+		want   eventfilter.FilterResult
+	}{
+		"Empty": {
+			filter: map[string]string{},
+			want:   eventfilter.NoFilter,
+		},
+		"Matching type suffix": {
+			filter: map[string]string{"type": ".someevent"},
+			want:   eventfilter.PassFilter,
+		},
+		"Exact type": {
+			filter: map[string]string{"type": eventType},
+			want:   eventfilter.PassFilter,
+		},
+		"Wrong type suffix": {
+			filter: map[string]string{"type": ".otherevent"},
+			want:   eventfilter.FailFilter,
+		},
+		"Matching type and source suffixes": {
+			filter: map[string]string{"type": ".someevent", "source": "context"},
+			want:   eventfilter.PassFilter,
+		},
+		"Wrong source suffix": {
+			filter: map[string]string{"type": ".someevent", "source": "other"},
+			want:   eventfilter.FailFilter,
+		},
+		"Matching extension suffix": {
+			filter: map[string]string{extensionName: "-value"},
+			event:  makeEventWithExtension(extensionName, extensionValue),
+			want:   eventfilter.PassFilter,
+		},
+		"Missing extension": {
+			filter: map[string]string{extensionName: "-value"},
+			want:   eventfilter.FailFilter,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := tt.event
+			if e == nil {
+				e = makeEvent()
+			}
+
+			if got := NewSuffixFilter(tt.filter).Filter(context.TODO(), *e); got != tt.want {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
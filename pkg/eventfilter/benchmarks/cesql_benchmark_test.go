@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmarks
+
+import (
+	"testing"
+
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/cesql"
+)
+
+func BenchmarkSQLFilter(b *testing.B) {
+	event := cetest.FullEvent()
+
+	RunFilterBenchmarks(b,
+		func(i interface{}) eventfilter.Filter {
+			f, err := cesql.NewSQLFilter(i.(string))
+			if err != nil {
+				b.Fatal(err)
+			}
+			return f
+		},
+		FilterBenchmark{
+			name:  "Pass with exact match of id",
+			arg:   "id = '" + event.ID() + "'",
+			event: event,
+		},
+		FilterBenchmark{
+			name:  "Pass with exact match of type and prefix match of source",
+			arg:   "type = '" + event.Type() + "' AND source LIKE '" + event.Source() + "%'",
+			event: event,
+		},
+		FilterBenchmark{
+			name:  "No pass with exact match of id and source",
+			arg:   "id = 'qwertyuiopasdfghjklzxcvbnm' AND source = 'qwertyuiopasdfghjklzxcvbnm'",
+			event: event,
+		},
+	)
+}
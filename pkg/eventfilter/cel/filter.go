@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cel implements event filters written in the Common Expression
+// Language (CEL). See https://github.com/google/cel-spec
+//
+// The context attributes and the extensions of the event are available under
+// the "ce" variable, e.g. ce.type or ce.myextension, and the event data parsed
+// as JSON under the "data" variable, e.g. data.customer.tier.
+package cel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+	celgo "github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"go.uber.org/zap"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/data"
+)
+
+const (
+	// attributesVar is the name of the variable holding the context attributes
+	// and the extensions of the event.
+	attributesVar = "ce"
+	// dataVar is the name of the variable holding the event data.
+	dataVar = "data"
+	// maxCost is the maximum estimated cost of the evaluation of an expression. The
+	// estimation is static, so the comprehensions over the attributes or the data of
+	// the event, whose size isn't known, exceed it.
+	maxCost = 1000
+)
+
+type celFilter struct {
+	program celgo.Program
+	// usesData is true if the expression references the event data, which is
+	// only parsed in that case.
+	usesData bool
+}
+
+// Check parses and type-checks the CEL expression. The expression must
+// evaluate to a boolean and its estimated cost must not exceed maxCost.
+func Check(expression string) error {
+	_, _, err := compile(expression)
+	return err
+}
+
+// NewCELFilter compiles the CEL expression and returns an event filter which
+// passes if the expression evaluates to true.
+func NewCELFilter(expression string) (eventfilter.Filter, error) {
+	ast, program, err := compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	checked, err := celgo.AstToCheckedExpr(ast)
+	if err != nil {
+		return nil, err
+	}
+	usesData := false
+	for _, ref := range checked.GetReferenceMap() {
+		if ref.GetName() == dataVar {
+			usesData = true
+		}
+	}
+	return &celFilter{program: program, usesData: usesData}, nil
+}
+
+func (f *celFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
+	pass, err := f.evaluate(ctx, event)
+	if err != nil || !pass {
+		return eventfilter.FailFilter
+	}
+	return eventfilter.PassFilter
+}
+
+func (f *celFilter) Explain(ctx context.Context, event cloudevents.Event) eventfilter.Explanation {
+	pass, err := f.evaluate(ctx, event)
+	if err != nil {
+		return eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: err.Error()}
+	}
+	if !pass {
+		return eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: "the CEL expression evaluated to false"}
+	}
+	return eventfilter.Explanation{Result: eventfilter.PassFilter}
+}
+
+func (f *celFilter) evaluate(ctx context.Context, event cloudevents.Event) (bool, error) {
+	vars := map[string]interface{}{
+		attributesVar: attributes(event),
+	}
+	if f.usesData {
+		// Like the data filters, events whose data is not JSON never pass.
+		if !data.IsJSON(event.DataMediaType()) || len(event.Data()) == 0 {
+			logging.FromContext(ctx).Debug("Event data is not JSON", zap.String("datacontenttype", event.DataContentType()))
+			return false, errors.New("the event data is not JSON")
+		}
+		var document interface{}
+		if err := json.Unmarshal(event.Data(), &document); err != nil {
+			logging.FromContext(ctx).Debug("Failed to parse the event data", zap.Error(err))
+			data.ReportParseError(ctx, err)
+			return false, errors.New("the event data is not valid JSON")
+		}
+		vars[dataVar] = document
+	}
+
+	v, _, err := f.program.Eval(vars)
+	if err != nil {
+		logging.FromContext(ctx).Debug("Failed to evaluate the CEL expression", zap.Error(err))
+		return false, fmt.Errorf("failed to evaluate the CEL expression: %w", err)
+	}
+	pass, ok := v.Value().(bool)
+	if !ok {
+		logging.FromContext(ctx).Debug("CEL expression didn't evaluate to a boolean", zap.Any("value", v.Value()))
+		return false, fmt.Errorf("the CEL expression didn't evaluate to a boolean: %v", v.Value())
+	}
+	return pass, nil
+}
+
+var _ eventfilter.Filter = &celFilter{}
+var _ eventfilter.Explainer = &celFilter{}
+
+func newEnv() (*celgo.Env, error) {
+	return celgo.NewEnv(
+		celgo.Declarations(
+			decls.NewVar(attributesVar, decls.NewMapType(decls.String, decls.Dyn)),
+			decls.NewVar(dataVar, decls.Dyn),
+		),
+	)
+}
+
+// compile parses and type-checks the expression, and returns the checked AST
+// along with the program evaluating it.
+func compile(expression string) (*celgo.Ast, celgo.Program, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, nil, issues.Err()
+	}
+	if !isBoolOrDyn(ast.ResultType()) {
+		return nil, nil, fmt.Errorf("expected a boolean expression, got %s", celgo.FormatType(ast.ResultType()))
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, cost := celgo.EstimateCost(program); cost > maxCost {
+		return nil, nil, fmt.Errorf("the estimated cost of the expression exceeds the limit of %d, the comprehensions over the event are not supported", maxCost)
+	}
+	return ast, program, nil
+}
+
+func isBoolOrDyn(t *exprpb.Type) bool {
+	return t.GetPrimitive() == exprpb.Type_BOOL || t.GetDyn() != nil
+}
+
+// attributes returns the context attributes and the extensions of the event,
+// keyed by their name. Optional attributes are only set if present.
+func attributes(event cloudevents.Event) map[string]interface{} {
+	ce := map[string]interface{}{
+		"specversion": event.SpecVersion(),
+		"type":        event.Type(),
+		"source":      event.Source(),
+		"id":          event.ID(),
+	}
+	if v := event.Subject(); v != "" {
+		ce["subject"] = v
+	}
+	if v := event.Time(); !v.IsZero() {
+		ce["time"] = v.UTC().Format(time.RFC3339Nano)
+	}
+	if v := event.DataSchema(); v != "" {
+		ce["dataschema"] = v
+	}
+	if v := event.DataContentType(); v != "" {
+		ce["datacontenttype"] = v
+	}
+	for k, v := range event.Extensions() {
+		switch v.(type) {
+		case string, bool, int32:
+			ce[k] = v
+		default:
+			// Other extension types, e.g. URIs and timestamps, are exposed
+			// with their canonical string representation.
+			s, err := types.Format(v)
+			if err != nil {
+				continue
+			}
+			ce[k] = s
+		}
+	}
+	return ce
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/data"
+)
+
+func TestCELFilter_Filter(t *testing.T) {
+	tests := map[string]struct {
+		expression      string
+		contentType     string
+		data            []byte
+		want            eventfilter.FilterResult
+		wantParseErrors int
+	}{
+		"Attribute": {
+			expression: `ce.type == "dev.knative.example" && ce.source.startsWith("/apis")`,
+			want:       eventfilter.PassFilter,
+		},
+		"Attribute not matching": {
+			expression: `ce.type == "other"`,
+			want:       eventfilter.FailFilter,
+		},
+		"Optional attribute": {
+			expression: `ce.subject == "subject" && ce.time == "2021-04-01T10:00:00Z"`,
+			want:       eventfilter.PassFilter,
+		},
+		"Missing attribute": {
+			expression: `ce.dataschema == "schema"`,
+			want:       eventfilter.FailFilter,
+		},
+		"Presence test": {
+			expression: `!has(ce.dataschema) && has(ce.subject)`,
+			want:       eventfilter.PassFilter,
+		},
+		"Extensions": {
+			expression: `ce.tenant == "acme" && ce.priority > 5 && ce.urgent`,
+			want:       eventfilter.PassFilter,
+		},
+		"Data": {
+			expression:  `data.customer.tier == "gold" && data.amount >= 100.0`,
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"customer": {"tier": "gold"}, "amount": 120}`),
+			want:        eventfilter.PassFilter,
+		},
+		"Data not matching": {
+			expression:  `data.customer.tier == "gold"`,
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"customer": {"tier": "silver"}}`),
+			want:        eventfilter.FailFilter,
+		},
+		"Data not JSON": {
+			expression:  `data.customer.tier == "gold"`,
+			contentType: "text/plain",
+			data:        []byte(`{"customer": {"tier": "gold"}}`),
+			want:        eventfilter.FailFilter,
+		},
+		"Data not used": {
+			expression:  `ce.type == "dev.knative.example"`,
+			contentType: "text/plain",
+			data:        []byte(`hello`),
+			want:        eventfilter.PassFilter,
+		},
+		"No data": {
+			expression: `data.customer.tier == "gold"`,
+			want:       eventfilter.FailFilter,
+		},
+		"Malformed JSON": {
+			expression:      `data.customer.tier == "gold"`,
+			contentType:     cloudevents.ApplicationJSON,
+			data:            []byte(`{"customer": `),
+			want:            eventfilter.FailFilter,
+			wantParseErrors: 1,
+		},
+		"Missing data field": {
+			expression:  `data.customer.tier == "gold"`,
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"amount": 120}`),
+			want:        eventfilter.FailFilter,
+		},
+		"Not a boolean": {
+			expression:  `data.customer`,
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"customer": "gold"}`),
+			want:        eventfilter.FailFilter,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewCELFilter(tt.expression)
+			if err != nil {
+				t.Fatal("NewCELFilter() =", err)
+			}
+			e := makeEvent()
+			e.DataEncoded = tt.data
+			if tt.contentType != "" {
+				e.SetDataContentType(tt.contentType)
+			}
+
+			parseErrors := 0
+			ctx := data.WithParseErrorReporter(context.TODO(), func(error) {
+				parseErrors++
+			})
+			if got := f.Filter(ctx, e); got != tt.want {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+			if parseErrors != tt.wantParseErrors {
+				t.Errorf("Reported %d parse errors, want %d", parseErrors, tt.wantParseErrors)
+			}
+		})
+	}
+}
+
+func TestCELFilter_Explain(t *testing.T) {
+	tests := map[string]struct {
+		expression string
+		want       eventfilter.Explanation
+	}{
+		"Pass": {
+			expression: `ce.type == "dev.knative.example"`,
+			want:       eventfilter.Explanation{Result: eventfilter.PassFilter},
+		},
+		"False": {
+			expression: `ce.type == "other"`,
+			want:       eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: "the CEL expression evaluated to false"},
+		},
+		"Data not JSON": {
+			expression: `data.customer.tier == "gold"`,
+			want:       eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: "the event data is not JSON"},
+		},
+		"Missing attribute": {
+			expression: `ce.dataschema == "schema"`,
+			want:       eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: "failed to evaluate the CEL expression: no such key: dataschema"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewCELFilter(tt.expression)
+			if err != nil {
+				t.Fatal("NewCELFilter() =", err)
+			}
+			if got := eventfilter.Explain(context.TODO(), f, makeEvent()); got != tt.want {
+				t.Errorf("Explain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := map[string]struct {
+		expression string
+		wantErr    bool
+	}{
+		"Valid": {
+			expression: `ce.type == "dev.knative.example" || data.amount > 100.0`,
+		},
+		"Dynamic": {
+			expression: `data.urgent`,
+		},
+		"Syntax error": {
+			expression: `ce.type ==`,
+			wantErr:    true,
+		},
+		"Unknown variable": {
+			expression: `event.type == "dev.knative.example"`,
+			wantErr:    true,
+		},
+		"Not a boolean": {
+			expression: `ce.type + "suffix"`,
+			wantErr:    true,
+		},
+		"Comprehension over a literal": {
+			expression: `["a", "b"].exists(s, ce.type == s)`,
+		},
+		"Comprehension over the data": {
+			expression: `data.items.exists(i, i.amount > 100.0)`,
+			wantErr:    true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := Check(tt.expression); (err != nil) != tt.wantErr {
+				t.Errorf("Check() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func makeEvent() cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID("1234")
+	e.SetType("dev.knative.example")
+	e.SetSource("/apis/v1/namespaces/default/pingsources/ping")
+	e.SetSubject("subject")
+	e.SetTime(time.Date(2021, 4, 1, 10, 0, 0, 0, time.UTC))
+	e.SetExtension("tenant", "acme")
+	e.SetExtension("priority", 7)
+	e.SetExtension("urgent", true)
+	return e
+}
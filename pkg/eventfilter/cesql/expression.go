@@ -0,0 +1,310 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"regexp"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// Expression is a parsed CESQL expression.
+type Expression interface {
+	// Evaluate evaluates the expression on the provided event. The returned value
+	// is a bool, an int32 or a string.
+	Evaluate(event cloudevents.Event) (interface{}, error)
+}
+
+type literalExpression struct {
+	value interface{}
+}
+
+func (e literalExpression) Evaluate(cloudevents.Event) (interface{}, error) {
+	return e.value, nil
+}
+
+type attributeExpression struct {
+	name string
+}
+
+func (e attributeExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	v, ok := attribute(event, e.name)
+	if !ok {
+		return nil, fmt.Errorf("missing attribute %q", e.name)
+	}
+	return v, nil
+}
+
+type existsExpression struct {
+	name string
+}
+
+func (e existsExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	_, ok := attribute(event, e.name)
+	return ok, nil
+}
+
+type notExpression struct {
+	operand Expression
+}
+
+func (e notExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	b, err := evaluateBool(event, e.operand)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type negateExpression struct {
+	operand Expression
+}
+
+func (e negateExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	i, err := evaluateInteger(event, e.operand)
+	if err != nil {
+		return nil, err
+	}
+	return -i, nil
+}
+
+type logicExpression struct {
+	operator    string
+	left, right Expression
+}
+
+func (e logicExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	left, err := evaluateBool(event, e.left)
+	if err != nil {
+		return nil, err
+	}
+	// AND and OR short circuit, so that expressions like
+	// "NOT EXISTS ext OR ext = 'value'" don't fail on missing attributes.
+	switch {
+	case e.operator == "AND" && !left:
+		return false, nil
+	case e.operator == "OR" && left:
+		return true, nil
+	}
+	right, err := evaluateBool(event, e.right)
+	if err != nil {
+		return nil, err
+	}
+	if e.operator == "XOR" {
+		return left != right, nil
+	}
+	return right, nil
+}
+
+type comparisonExpression struct {
+	operator    string
+	left, right Expression
+}
+
+func (e comparisonExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	switch e.operator {
+	case "=", "!=", "<>":
+		left, err := e.left.Evaluate(event)
+		if err != nil {
+			return nil, err
+		}
+		right, err := e.right.Evaluate(event)
+		if err != nil {
+			return nil, err
+		}
+		equal, err := equals(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return equal == (e.operator == "="), nil
+	}
+	left, err := evaluateInteger(event, e.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := evaluateInteger(event, e.right)
+	if err != nil {
+		return nil, err
+	}
+	switch e.operator {
+	case "<":
+		return left < right, nil
+	case "<=":
+		return left <= right, nil
+	case ">":
+		return left > right, nil
+	default:
+		return left >= right, nil
+	}
+}
+
+// equals compares the two values, casting the right value to the type of the left one.
+func equals(left, right interface{}) (bool, error) {
+	right, err := castAs(right, left)
+	if err != nil {
+		return false, err
+	}
+	return left == right, nil
+}
+
+type arithmeticExpression struct {
+	operator    string
+	left, right Expression
+}
+
+func (e arithmeticExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	left, err := evaluateInteger(event, e.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := evaluateInteger(event, e.right)
+	if err != nil {
+		return nil, err
+	}
+	switch e.operator {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	}
+	if right == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	if e.operator == "/" {
+		return left / right, nil
+	}
+	return left % right, nil
+}
+
+type likeExpression struct {
+	operand Expression
+	pattern *regexp.Regexp
+	not     bool
+}
+
+func (e likeExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	v, err := e.operand.Evaluate(event)
+	if err != nil {
+		return nil, err
+	}
+	return e.pattern.MatchString(castToString(v)) != e.not, nil
+}
+
+type inExpression struct {
+	operand Expression
+	set     []Expression
+	not     bool
+}
+
+func (e inExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	v, err := e.operand.Evaluate(event)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range e.set {
+		iv, err := item.Evaluate(event)
+		if err != nil {
+			return nil, err
+		}
+		equal, err := equals(v, iv)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
+			return !e.not, nil
+		}
+	}
+	return e.not, nil
+}
+
+type functionExpression struct {
+	function function
+	args     []Expression
+}
+
+func (e functionExpression) Evaluate(event cloudevents.Event) (interface{}, error) {
+	args := make([]interface{}, 0, len(e.args))
+	for _, arg := range e.args {
+		v, err := arg.Evaluate(event)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	return e.function.call(args)
+}
+
+func evaluateBool(event cloudevents.Event, e Expression) (bool, error) {
+	v, err := e.Evaluate(event)
+	if err != nil {
+		return false, err
+	}
+	return castToBool(v)
+}
+
+func evaluateInteger(event cloudevents.Event, e Expression) (int32, error) {
+	v, err := e.Evaluate(event)
+	if err != nil {
+		return 0, err
+	}
+	return castToInteger(v)
+}
+
+// attribute returns the value of the context attribute or extension with the given name,
+// and whether the event has it.
+func attribute(event cloudevents.Event, name string) (interface{}, bool) {
+	var v string
+	switch name {
+	case "specversion":
+		v = event.SpecVersion()
+	case "id":
+		v = event.ID()
+	case "source":
+		v = event.Source()
+	case "type":
+		v = event.Type()
+	case "subject":
+		v = event.Subject()
+	case "time":
+		if t := event.Time(); !t.IsZero() {
+			v = types.FormatTime(t)
+		}
+	case "dataschema":
+		v = event.DataSchema()
+	case "datacontenttype":
+		v = event.DataContentType()
+	default:
+		ext, ok := event.Extensions()[name]
+		if !ok {
+			return nil, false
+		}
+		switch ext := ext.(type) {
+		case int32, bool:
+			return ext, true
+		}
+		s, err := types.Format(ext)
+		if err != nil {
+			return nil, false
+		}
+		return s, true
+	}
+	return v, v != ""
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cesql implements a parser and an evaluator of the CloudEvents SQL
+// expression language (CESQL), used to filter events.
+// See https://github.com/cloudevents/spec/blob/master/cesql/spec.md
+package cesql
+
+import (
+	"context"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+type sqlFilter struct {
+	expression Expression
+}
+
+// NewSQLFilter parses the CESQL expression and returns an event filter which passes
+// if the expression evaluates to true.
+func NewSQLFilter(expression string) (eventfilter.Filter, error) {
+	e, err := Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlFilter{expression: e}, nil
+}
+
+func (f *sqlFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
+	pass, err := f.evaluate(ctx, event)
+	if err != nil || !pass {
+		return eventfilter.FailFilter
+	}
+	return eventfilter.PassFilter
+}
+
+func (f *sqlFilter) Explain(ctx context.Context, event cloudevents.Event) eventfilter.Explanation {
+	pass, err := f.evaluate(ctx, event)
+	if err != nil {
+		return eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: err.Error()}
+	}
+	if !pass {
+		return eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: "the SQL expression evaluated to false"}
+	}
+	return eventfilter.Explanation{Result: eventfilter.PassFilter}
+}
+
+func (f *sqlFilter) evaluate(ctx context.Context, event cloudevents.Event) (bool, error) {
+	v, err := f.expression.Evaluate(event)
+	if err != nil {
+		logging.FromContext(ctx).Debug("Failed to evaluate the SQL expression", zap.Error(err))
+		return false, fmt.Errorf("failed to evaluate the SQL expression: %w", err)
+	}
+	pass, err := castToBool(v)
+	if err != nil {
+		logging.FromContext(ctx).Debug("SQL expression didn't evaluate to a boolean", zap.Error(err))
+		return false, fmt.Errorf("the SQL expression didn't evaluate to a boolean: %w", err)
+	}
+	return pass, nil
+}
+
+var _ eventfilter.Filter = &sqlFilter{}
+var _ eventfilter.Explainer = &sqlFilter{}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+func TestSQLFilter_Filter(t *testing.T) {
+	tests := map[string]struct {
+		expression string
+		want       eventfilter.FilterResult
+	}{
+		"True":                          {expression: "TRUE", want: eventfilter.PassFilter},
+		"False":                         {expression: "FALSE", want: eventfilter.FailFilter},
+		"Type equals":                   {expression: "type = 'order.created'", want: eventfilter.PassFilter},
+		"Type not equals":               {expression: "type != 'order.created'", want: eventfilter.FailFilter},
+		"Type not equals with <>":       {expression: "type <> 'order.deleted'", want: eventfilter.PassFilter},
+		"Integer extension":             {expression: "amount > 100", want: eventfilter.PassFilter},
+		"Integer extension not matched": {expression: "amount <= 100", want: eventfilter.FailFilter},
+		"String extension cast":         {expression: "quantity = 3 AND quantity + 1 = 4", want: eventfilter.PassFilter},
+		"Boolean extension":             {expression: "priority", want: eventfilter.PassFilter},
+		"And/Or":                        {expression: "type = 'order.created' AND amount > 100 OR source LIKE 'billing/%'", want: eventfilter.PassFilter},
+		"Or with failing left":          {expression: "type = 'other' OR source LIKE 'shop/%'", want: eventfilter.PassFilter},
+		"And binds tighter than Or":     {expression: "TRUE OR FALSE AND FALSE", want: eventfilter.PassFilter},
+		"Xor":                           {expression: "TRUE XOR TRUE", want: eventfilter.FailFilter},
+		"Not":                           {expression: "NOT type = 'order.created'", want: eventfilter.FailFilter},
+		"Like":                          {expression: "source LIKE 'shop/_rders'", want: eventfilter.PassFilter},
+		"Like with escape":              {expression: `id LIKE '100\%'`, want: eventfilter.FailFilter},
+		"Not like":                      {expression: "source NOT LIKE 'billing/%'", want: eventfilter.PassFilter},
+		"In":                            {expression: "type IN ('order.updated', 'order.created')", want: eventfilter.PassFilter},
+		"Not in":                        {expression: "type NOT IN ('order.updated', 'order.created')", want: eventfilter.FailFilter},
+		"Exists":                        {expression: "EXISTS amount", want: eventfilter.PassFilter},
+		"Exists optional attribute":     {expression: "EXISTS subject", want: eventfilter.FailFilter},
+		"Missing attribute":             {expression: "missing = 'a'", want: eventfilter.FailFilter},
+		"Guarded missing attribute":     {expression: "NOT EXISTS missing OR missing = 'a'", want: eventfilter.PassFilter},
+		"Arithmetic":                    {expression: "(amount + 50) * 2 / 3 % 7 = 0", want: eventfilter.PassFilter},
+		"Negation":                      {expression: "-amount < 0", want: eventfilter.PassFilter},
+		"Division by zero":              {expression: "amount / 0 = 1", want: eventfilter.FailFilter},
+		"Invalid cast":                  {expression: "type > 1", want: eventfilter.FailFilter},
+		"Non boolean result":            {expression: "amount", want: eventfilter.FailFilter},
+		"Length":                        {expression: "LENGTH(type) = 13", want: eventfilter.PassFilter},
+		"Concat":                        {expression: "CONCAT(source, '/', id) = 'shop/orders/123'", want: eventfilter.PassFilter},
+		"Concat with separator":         {expression: "CONCAT_WS(':', type, id) = 'order.created:123'", want: eventfilter.PassFilter},
+		"Lower and upper":               {expression: "UPPER(type) = 'ORDER.CREATED' AND LOWER('ABC') = 'abc'", want: eventfilter.PassFilter},
+		"Trim":                          {expression: "TRIM('  a  ') = 'a'", want: eventfilter.PassFilter},
+		"Left and right":                {expression: "LEFT(type, 5) = 'order' AND RIGHT(type, 7) = 'created'", want: eventfilter.PassFilter},
+		"Substring":                     {expression: "SUBSTRING(type, 7) = 'created' AND SUBSTRING(type, -7, 3) = 'cre'", want: eventfilter.PassFilter},
+		"Substring out of range":        {expression: "SUBSTRING(type, 20) = ''", want: eventfilter.FailFilter},
+		"Abs":                           {expression: "ABS(-3) = 3", want: eventfilter.PassFilter},
+		"Casting functions":             {expression: "INT('12') = 12 AND BOOL('true') AND STRING(12) = '12'", want: eventfilter.PassFilter},
+		"Type checking functions":       {expression: "IS_INT(quantity) AND NOT IS_INT(type) AND IS_BOOL('false')", want: eventfilter.PassFilter},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewSQLFilter(tt.expression)
+			if err != nil {
+				t.Fatalf("NewSQLFilter(%q) = %v", tt.expression, err)
+			}
+			if got := f.Filter(context.TODO(), makeEvent()); got != tt.want {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLFilter_Explain(t *testing.T) {
+	tests := map[string]struct {
+		expression string
+		want       eventfilter.Explanation
+	}{
+		"True": {
+			expression: "TRUE",
+			want:       eventfilter.Explanation{Result: eventfilter.PassFilter},
+		},
+		"False": {
+			expression: "type = 'other'",
+			want:       eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: "the SQL expression evaluated to false"},
+		},
+		"Missing attribute": {
+			expression: "missing = 'a'",
+			want:       eventfilter.Explanation{Result: eventfilter.FailFilter, Reason: `failed to evaluate the SQL expression: missing attribute "missing"`},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewSQLFilter(tt.expression)
+			if err != nil {
+				t.Fatalf("NewSQLFilter(%q) = %v", tt.expression, err)
+			}
+			if got := eventfilter.Explain(context.TODO(), f, makeEvent()); got != tt.want {
+				t.Errorf("Explain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func makeEvent() cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetType("order.created")
+	e.SetSource("shop/orders")
+	e.SetID("123")
+	e.SetExtension("amount", 150)
+	e.SetExtension("quantity", "3")
+	e.SetExtension("priority", true)
+	return e
+}
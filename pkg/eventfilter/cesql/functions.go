@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// function is a CESQL built-in function. Arguments are evaluated before the call.
+type function struct {
+	// minArgs and maxArgs bound the number of arguments, maxArgs is -1 for variadic functions.
+	minArgs, maxArgs int
+	call             func(args []interface{}) (interface{}, error)
+}
+
+var functions = map[string]function{
+	"LENGTH": {1, 1, func(args []interface{}) (interface{}, error) {
+		return int32(len([]rune(castToString(args[0])))), nil
+	}},
+	"CONCAT": {0, -1, func(args []interface{}) (interface{}, error) {
+		return strings.Join(stringArgs(args), ""), nil
+	}},
+	"CONCAT_WS": {1, -1, func(args []interface{}) (interface{}, error) {
+		s := stringArgs(args)
+		return strings.Join(s[1:], s[0]), nil
+	}},
+	"LOWER": {1, 1, func(args []interface{}) (interface{}, error) {
+		return strings.ToLower(castToString(args[0])), nil
+	}},
+	"UPPER": {1, 1, func(args []interface{}) (interface{}, error) {
+		return strings.ToUpper(castToString(args[0])), nil
+	}},
+	"TRIM": {1, 1, func(args []interface{}) (interface{}, error) {
+		return strings.TrimSpace(castToString(args[0])), nil
+	}},
+	"LEFT": {2, 2, func(args []interface{}) (interface{}, error) {
+		s := []rune(castToString(args[0]))
+		n, err := castToInteger(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("LEFT: negative length %d", n)
+		}
+		if int(n) > len(s) {
+			return string(s), nil
+		}
+		return string(s[:n]), nil
+	}},
+	"RIGHT": {2, 2, func(args []interface{}) (interface{}, error) {
+		s := []rune(castToString(args[0]))
+		n, err := castToInteger(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("RIGHT: negative length %d", n)
+		}
+		if int(n) > len(s) {
+			return string(s), nil
+		}
+		return string(s[len(s)-int(n):]), nil
+	}},
+	"SUBSTRING": {2, 3, substring},
+	"ABS": {1, 1, func(args []interface{}) (interface{}, error) {
+		i, err := castToInteger(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			return -i, nil
+		}
+		return i, nil
+	}},
+	"INT": {1, 1, func(args []interface{}) (interface{}, error) {
+		return castToInteger(args[0])
+	}},
+	"BOOL": {1, 1, func(args []interface{}) (interface{}, error) {
+		return castToBool(args[0])
+	}},
+	"STRING": {1, 1, func(args []interface{}) (interface{}, error) {
+		return castToString(args[0]), nil
+	}},
+	"IS_INT": {1, 1, func(args []interface{}) (interface{}, error) {
+		_, err := castToInteger(args[0])
+		return err == nil, nil
+	}},
+	"IS_BOOL": {1, 1, func(args []interface{}) (interface{}, error) {
+		_, err := castToBool(args[0])
+		return err == nil, nil
+	}},
+}
+
+// substring implements SUBSTRING(string, position [, length]). Positions start at 1,
+// negative positions count from the end of the string.
+func substring(args []interface{}) (interface{}, error) {
+	s := []rune(castToString(args[0]))
+	pos, err := castToInteger(args[1])
+	if err != nil {
+		return nil, err
+	}
+	var start int
+	switch {
+	case pos > 0 && int(pos) <= len(s):
+		start = int(pos) - 1
+	case pos < 0 && -int(pos) <= len(s):
+		start = len(s) + int(pos)
+	default:
+		return nil, fmt.Errorf("SUBSTRING: position %d out of range", pos)
+	}
+	end := len(s)
+	if len(args) == 3 {
+		n, err := castToInteger(args[2])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("SUBSTRING: negative length %d", n)
+		}
+		if start+int(n) < end {
+			end = start + int(n)
+		}
+	}
+	return string(s[start:end]), nil
+}
+
+func stringArgs(args []interface{}) []string {
+	s := make([]string, 0, len(args))
+	for _, arg := range args {
+		s = append(s, castToString(arg))
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenInteger
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+// token is a lexical token of an expression. Keywords are returned as identifiers.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// keyword returns the upper case text of the token if it is a keyword, or "" otherwise.
+func (t token) keyword() string {
+	if t.kind != tokenIdentifier {
+		return ""
+	}
+	if k := strings.ToUpper(t.text); keywords[k] {
+		return k
+	}
+	return ""
+}
+
+var keywords = map[string]bool{
+	"AND":    true,
+	"OR":     true,
+	"XOR":    true,
+	"NOT":    true,
+	"LIKE":   true,
+	"IN":     true,
+	"EXISTS": true,
+	"TRUE":   true,
+	"FALSE":  true,
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isLetter(c):
+			start := i
+			for i < len(input) && (isLetter(input[i]) || isDigit(input[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: input[start:i], pos: start})
+		case isDigit(c):
+			start := i
+			for i < len(input) && isDigit(input[i]) {
+				i++
+			}
+			if i < len(input) && isLetter(input[i]) {
+				return nil, fmt.Errorf("unexpected character %q at position %d", input[i], i)
+			}
+			tokens = append(tokens, token{kind: tokenInteger, text: input[start:i], pos: start})
+		case c == '\'':
+			s, n, err := readString(input[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at position %d", err, i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s, pos: i})
+			i += n
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		default:
+			op := readOperator(input[i:])
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+var operators = []string{"!=", "<>", "<=", ">=", "=", "<", ">", "+", "-", "*", "/", "%"}
+
+func readOperator(input string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(input, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// readString reads a single-quoted string literal, returning its unescaped
+// value and the number of bytes consumed. The quote character can be escaped
+// with a backslash.
+func readString(input string) (string, int, error) {
+	quote := input[0]
+	var sb strings.Builder
+	for i := 1; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '\\' && i+1 < len(input) && (input[i+1] == quote || input[i+1] == '\\'):
+			sb.WriteByte(input[i+1])
+			i++
+		case c == quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string literal")
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
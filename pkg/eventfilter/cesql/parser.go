@@ -0,0 +1,351 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var validAttributeName = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// Parse parses a CESQL expression.
+//
+// Operators have the following precedence, from the highest to the lowest:
+// unary -, * / %, + -, comparisons (= != <> < <= > >= LIKE IN), NOT, AND, XOR, OR.
+func Parse(expression string) (Expression, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, unexpected(t)
+	}
+	return e, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// acceptKeyword consumes the next token if it is the given keyword.
+func (p *parser) acceptKeyword(keyword string) bool {
+	if p.peek().keyword() == keyword {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, unexpected(t)
+	}
+	return t, nil
+}
+
+func unexpected(t token) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	return p.parseLogic("OR", p.parseXor)
+}
+
+func (p *parser) parseXor() (Expression, error) {
+	return p.parseLogic("XOR", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	return p.parseLogic("AND", p.parseNot)
+}
+
+func (p *parser) parseLogic(operator string, operand func() (Expression, error)) (Expression, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword(operator) {
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = logicExpression{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expression, error) {
+	if p.acceptKeyword("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expression, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind == tokenOperator {
+		switch t.text {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return comparisonExpression{operator: t.text, left: left, right: right}, nil
+		}
+	}
+	not := p.acceptKeyword("NOT")
+	switch {
+	case p.acceptKeyword("LIKE"):
+		return p.parseLike(left, not)
+	case p.acceptKeyword("IN"):
+		return p.parseIn(left, not)
+	case not:
+		return nil, unexpected(p.peek())
+	}
+	return left, nil
+}
+
+func (p *parser) parseLike(operand Expression, not bool) (Expression, error) {
+	t, err := p.expect(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return likeExpression{operand: operand, pattern: likePattern(t.text), not: not}, nil
+}
+
+// likePattern converts a LIKE pattern to a regular expression. '%' matches any
+// sequence of characters and '_' matches a single character, unless escaped
+// with a backslash.
+func likePattern(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("(?s)^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\\' && i+1 < len(runes) && (runes[i+1] == '%' || runes[i+1] == '_'):
+			sb.WriteString(regexp.QuoteMeta(string(runes[i+1])))
+			i++
+		case r == '%':
+			sb.WriteString(".*")
+		case r == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+func (p *parser) parseIn(operand Expression, not bool) (Expression, error) {
+	if _, err := p.expect(tokenLeftParen); err != nil {
+		return nil, err
+	}
+	set, err := p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("empty set in IN expression")
+	}
+	return inExpression{operand: operand, set: set, not: not}, nil
+}
+
+func (p *parser) parseAdditive() (Expression, error) {
+	return p.parseArithmetic(p.parseMultiplicative, "+", "-")
+}
+
+func (p *parser) parseMultiplicative() (Expression, error) {
+	return p.parseArithmetic(p.parseUnary, "*", "/", "%")
+}
+
+func (p *parser) parseArithmetic(operand func() (Expression, error), operators ...string) (Expression, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOperator || !contains(operators, t.text) {
+			return left, nil
+		}
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticExpression{operator: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if t := p.peek(); t.kind == tokenOperator && t.text == "-" {
+		p.next()
+		// Parse negative integer literals directly, so that the minimum integer value can be expressed.
+		if p.peek().kind == tokenInteger {
+			return parseInteger("-" + p.next().text)
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateExpression{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenInteger:
+		return parseInteger(t.text)
+	case tokenString:
+		return literalExpression{value: t.text}, nil
+	case tokenLeftParen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRightParen); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case tokenIdentifier:
+		switch t.keyword() {
+		case "TRUE":
+			return literalExpression{value: true}, nil
+		case "FALSE":
+			return literalExpression{value: false}, nil
+		case "EXISTS":
+			name, err := p.expect(tokenIdentifier)
+			if err != nil {
+				return nil, err
+			}
+			if err := validateAttributeName(name); err != nil {
+				return nil, err
+			}
+			return existsExpression{name: name.text}, nil
+		case "":
+			if p.peek().kind == tokenLeftParen {
+				return p.parseFunction(t)
+			}
+			if err := validateAttributeName(t); err != nil {
+				return nil, err
+			}
+			return attributeExpression{name: t.text}, nil
+		}
+	}
+	return nil, unexpected(t)
+}
+
+func (p *parser) parseFunction(name token) (Expression, error) {
+	p.next()
+	f, ok := functions[strings.ToUpper(name.text)]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+	args, err := p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < f.minArgs || (f.maxArgs >= 0 && len(args) > f.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments for function %q at position %d: %d", name.text, name.pos, len(args))
+	}
+	return functionExpression{function: f, args: args}, nil
+}
+
+// parseArguments parses a comma separated list of expressions, up to and including the closing parenthesis.
+func (p *parser) parseArguments() ([]Expression, error) {
+	var args []Expression
+	if p.peek().kind == tokenRightParen {
+		p.next()
+		return args, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		t := p.next()
+		switch t.kind {
+		case tokenComma:
+		case tokenRightParen:
+			return args, nil
+		default:
+			return nil, unexpected(t)
+		}
+	}
+}
+
+func parseInteger(text string) (Expression, error) {
+	i, err := strconv.ParseInt(text, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer literal %s", text)
+	}
+	return literalExpression{value: int32(i)}, nil
+}
+
+func validateAttributeName(t token) error {
+	if !validAttributeName.MatchString(t.text) {
+		return fmt.Errorf("invalid attribute name %q at position %d", t.text, t.pos)
+	}
+	return nil
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
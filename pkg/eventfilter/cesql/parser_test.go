@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		expression string
+		wantErr    bool
+	}{
+		"Attribute":                  {expression: "type"},
+		"Comparison":                 {expression: "type = 'order.created'"},
+		"Escaped quote":              {expression: `subject = 'it\'s'`},
+		"Logic operators":            {expression: "type = 'a' AND amount > 100 OR source LIKE 'billing/%'"},
+		"Lower case keywords":        {expression: "type = 'a' and not exists subject"},
+		"Xor":                        {expression: "TRUE XOR FALSE"},
+		"Not like":                   {expression: "source NOT LIKE 'billing/%'"},
+		"In":                         {expression: "type IN ('a', 'b', 'c')"},
+		"Not in":                     {expression: "type NOT IN ('a', 'b')"},
+		"Arithmetic":                 {expression: "(amount + 2) * 3 % 4 / 5 - -1 > 0"},
+		"Minimum integer":            {expression: "-2147483648"},
+		"Function":                   {expression: "LOWER(type) = 'a'"},
+		"Variadic function":          {expression: "CONCAT_WS('/', source, type, id) = 'a'"},
+		"Empty":                      {expression: "", wantErr: true},
+		"Unterminated string":        {expression: "type = 'abc", wantErr: true},
+		"Missing operand":            {expression: "type =", wantErr: true},
+		"Trailing tokens":            {expression: "type = 'a' 'b'", wantErr: true},
+		"Unbalanced parenthesis":     {expression: "(type = 'a'", wantErr: true},
+		"Invalid attribute name":     {expression: "Type = 'a'", wantErr: true},
+		"Unknown function":           {expression: "FOO(type)", wantErr: true},
+		"Wrong number of arguments":  {expression: "LOWER(type, id)", wantErr: true},
+		"Like without string":        {expression: "type LIKE source", wantErr: true},
+		"Empty in set":               {expression: "type IN ()", wantErr: true},
+		"Integer overflow":           {expression: "2147483648", wantErr: true},
+		"Unexpected character":       {expression: "type == 'a'", wantErr: true},
+		"Not without like or in":     {expression: "type NOT 'a'", wantErr: true},
+		"Exists without attribute":   {expression: "EXISTS 'a'", wantErr: true},
+		"Identifier starting with 0": {expression: "0abc", wantErr: true},
+		"Double quoted string":       {expression: `type = "order.created"`, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tt.expression)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("Parse(%q) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Values handled by the evaluator are always one of the CESQL types: Boolean
+// (bool), Integer (int32) or String (string).
+
+func castToInteger(v interface{}) (int32, error) {
+	switch v := v.(type) {
+	case int32:
+		return v, nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("cannot cast %q to integer", v)
+		}
+		return int32(i), nil
+	case bool:
+		return 0, fmt.Errorf("cannot cast boolean to integer")
+	}
+	return 0, fmt.Errorf("unsupported value type %T", v)
+}
+
+func castToBool(v interface{}) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot cast %q to boolean", v)
+	case int32:
+		return false, fmt.Errorf("cannot cast integer to boolean")
+	}
+	return false, fmt.Errorf("unsupported value type %T", v)
+}
+
+func castToString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	}
+	return fmt.Sprint(v)
+}
+
+// castAs casts v to the type of target.
+func castAs(v interface{}, target interface{}) (interface{}, error) {
+	switch target.(type) {
+	case int32:
+		return castToInteger(v)
+	case bool:
+		return castToBool(v)
+	}
+	return castToString(v), nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventfilter
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// NewAllFilter returns an event filter which passes if all the provided filters pass
+func NewAllFilter(filters ...Filter) Filter {
+	return Filters(filters)
+}
+
+type anyFilter []Filter
+
+// NewAnyFilter returns an event filter which passes if at least one of the provided filters passes
+func NewAnyFilter(filters ...Filter) Filter {
+	return anyFilter(filters)
+}
+
+func (filters anyFilter) Filter(ctx context.Context, event cloudevents.Event) FilterResult {
+	res := NoFilter
+	for _, f := range filters {
+		res = res.Or(f.Filter(ctx, event))
+		// Short circuit to optimize it
+		if res == PassFilter {
+			return PassFilter
+		}
+	}
+	return res
+}
+
+func (filters anyFilter) Explain(ctx context.Context, event cloudevents.Event) Explanation {
+	res := Explanation{Result: NoFilter}
+	var reasons []string
+	for _, f := range filters {
+		e := Explain(ctx, f, event)
+		if e.Result == PassFilter {
+			return e
+		}
+		if e.Result == FailFilter {
+			reasons = append(reasons, explanationReason(e))
+		}
+		res.Result = res.Result.Or(e.Result)
+	}
+	if res.Result == FailFilter {
+		res.Reason = "none of the filters passed: " + strings.Join(reasons, "; ")
+	}
+	return res
+}
+
+var _ Filter = anyFilter{}
+var _ Explainer = anyFilter{}
+
+type notFilter struct {
+	filter Filter
+}
+
+// NewNotFilter returns an event filter which passes if the provided filter fails.
+// If the provided filter is empty, the result is NoFilter.
+func NewNotFilter(f Filter) Filter {
+	return notFilter{filter: f}
+}
+
+func (f notFilter) Filter(ctx context.Context, event cloudevents.Event) FilterResult {
+	return f.filter.Filter(ctx, event).Not()
+}
+
+func (f notFilter) Explain(ctx context.Context, event cloudevents.Event) Explanation {
+	e := Explain(ctx, f.filter, event)
+	switch e.Result {
+	case PassFilter:
+		return Explanation{Result: FailFilter, Reason: "the negated filter passed"}
+	case FailFilter:
+		return Explanation{Result: PassFilter}
+	}
+	return e
+}
+
+var _ Filter = notFilter{}
+var _ Explainer = notFilter{}
+
+// explanationReason returns the reason of the explanation, prefixed by its attribute if any.
+func explanationReason(e Explanation) string {
+	if e.Attribute == "" {
+		return e.Reason
+	}
+	return e.Attribute + ": " + e.Reason
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventfilter
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnyFilter(t *testing.T) {
+	tests := []struct {
+		have []FilterResult
+		want FilterResult
+	}{{
+		have: []FilterResult{},
+		want: NoFilter,
+	}, {
+		have: []FilterResult{NoFilter},
+		want: NoFilter,
+	}, {
+		have: []FilterResult{PassFilter},
+		want: PassFilter,
+	}, {
+		have: []FilterResult{FailFilter},
+		want: FailFilter,
+	}, {
+		have: []FilterResult{FailFilter, PassFilter},
+		want: PassFilter,
+	}, {
+		have: []FilterResult{NoFilter, FailFilter},
+		want: FailFilter,
+	}, {
+		have: []FilterResult{FailFilter, FailFilter},
+		want: FailFilter,
+	}}
+	for _, tt := range tests {
+		t.Run(testName(tt.have, tt.want), func(t *testing.T) {
+			var filters []Filter
+			for _, fr := range tt.have {
+				filters = append(filters, mockFilter(fr))
+			}
+			require.Equal(t, tt.want, NewAnyFilter(filters...).Filter(context.TODO(), cloudevents.Event{}))
+		})
+	}
+}
+
+func TestAllFilter(t *testing.T) {
+	require.Equal(t, PassFilter, NewAllFilter(mockFilter(PassFilter), mockFilter(NoFilter)).Filter(context.TODO(), cloudevents.Event{}))
+	require.Equal(t, FailFilter, NewAllFilter(mockFilter(PassFilter), mockFilter(FailFilter)).Filter(context.TODO(), cloudevents.Event{}))
+	require.Equal(t, NoFilter, NewAllFilter().Filter(context.TODO(), cloudevents.Event{}))
+}
+
+func TestNotFilter(t *testing.T) {
+	tests := map[FilterResult]FilterResult{
+		PassFilter: FailFilter,
+		FailFilter: PassFilter,
+		NoFilter:   NoFilter,
+	}
+	for have, want := range tests {
+		t.Run(string(have), func(t *testing.T) {
+			require.Equal(t, want, NewNotFilter(mockFilter(have)).Filter(context.TODO(), cloudevents.Event{}))
+		})
+	}
+}
+
+func TestNestedFilters(t *testing.T) {
+	// (pass or fail) and not (fail and pass)
+	f := NewAllFilter(
+		NewAnyFilter(mockFilter(PassFilter), mockFilter(FailFilter)),
+		NewNotFilter(NewAllFilter(mockFilter(FailFilter), mockFilter(PassFilter))),
+	)
+	require.Equal(t, PassFilter, f.Filter(context.TODO(), cloudevents.Event{}))
+}
+
+type mockExplainer Explanation
+
+func (e mockExplainer) Filter(ctx context.Context, event cloudevents.Event) FilterResult {
+	return e.Result
+}
+
+func (e mockExplainer) Explain(ctx context.Context, event cloudevents.Event) Explanation {
+	return Explanation(e)
+}
+
+func TestExplain(t *testing.T) {
+	typeFailure := mockExplainer{Result: FailFilter, Attribute: "type", Reason: "wrong type"}
+	sourceFailure := mockExplainer{Result: FailFilter, Attribute: "source", Reason: "wrong source"}
+	tests := map[string]struct {
+		filter Filter
+		want   Explanation
+	}{
+		"Not an Explainer": {
+			filter: mockFilter(FailFilter),
+			want:   Explanation{Result: FailFilter},
+		},
+		"All": {
+			filter: NewAllFilter(mockFilter(PassFilter), typeFailure, sourceFailure),
+			want:   Explanation(typeFailure),
+		},
+		"All passing": {
+			filter: NewAllFilter(mockFilter(PassFilter), mockFilter(NoFilter)),
+			want:   Explanation{Result: PassFilter},
+		},
+		"Any": {
+			filter: NewAnyFilter(typeFailure, sourceFailure),
+			want:   Explanation{Result: FailFilter, Reason: "none of the filters passed: type: wrong type; source: wrong source"},
+		},
+		"Any passing": {
+			filter: NewAnyFilter(typeFailure, mockFilter(PassFilter)),
+			want:   Explanation{Result: PassFilter},
+		},
+		"Not": {
+			filter: NewNotFilter(mockFilter(PassFilter)),
+			want:   Explanation{Result: FailFilter, Reason: "the negated filter passed"},
+		},
+		"Not failing": {
+			filter: NewNotFilter(typeFailure),
+			want:   Explanation{Result: PassFilter},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, Explain(context.TODO(), tt.filter, cloudevents.Event{}))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package data implements event filters on the event data.
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+type parseErrorReporterKey struct{}
+
+// WithParseErrorReporter returns a copy of the context in which the data filters call report
+// when the data of the filtered event can't be parsed.
+func WithParseErrorReporter(ctx context.Context, report func(err error)) context.Context {
+	return context.WithValue(ctx, parseErrorReporterKey{}, report)
+}
+
+// ReportParseError reports that the data of the filtered event can't be parsed
+// to the reporter of the context, if any.
+func ReportParseError(ctx context.Context, err error) {
+	if report, ok := ctx.Value(parseErrorReporterKey{}).(func(error)); ok {
+		report(err)
+	}
+}
+
+type dataFilter struct {
+	predicate *Predicate
+}
+
+// NewDataFilter parses the JSONPath predicate and returns an event filter which passes if
+// the predicate holds on the event data. Events whose data is not JSON never pass.
+func NewDataFilter(expression string) (eventfilter.Filter, error) {
+	p, err := ParsePredicate(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &dataFilter{predicate: p}, nil
+}
+
+func (f *dataFilter) Filter(ctx context.Context, event cloudevents.Event) eventfilter.FilterResult {
+	res, _ := f.evaluate(ctx, event)
+	return res
+}
+
+func (f *dataFilter) Explain(ctx context.Context, event cloudevents.Event) eventfilter.Explanation {
+	res, reason := f.evaluate(ctx, event)
+	if res == eventfilter.FailFilter {
+		return eventfilter.Explanation{Result: res, Attribute: "data", Reason: reason}
+	}
+	return eventfilter.Explanation{Result: res}
+}
+
+// evaluate returns the result of the filter and, if it fails, the reason.
+func (f *dataFilter) evaluate(ctx context.Context, event cloudevents.Event) (eventfilter.FilterResult, string) {
+	if !IsJSON(event.DataMediaType()) {
+		logging.FromContext(ctx).Debug("Event data is not JSON", zap.String("datacontenttype", event.DataContentType()))
+		return eventfilter.FailFilter, "the event data is not JSON"
+	}
+	if len(event.Data()) == 0 {
+		logging.FromContext(ctx).Debug("Event has no data")
+		return eventfilter.FailFilter, "the event has no data"
+	}
+	var document interface{}
+	if err := json.Unmarshal(event.Data(), &document); err != nil {
+		logging.FromContext(ctx).Debug("Failed to parse the event data", zap.Error(err))
+		ReportParseError(ctx, err)
+		return eventfilter.FailFilter, "the event data is not valid JSON"
+	}
+	if f.predicate.Evaluate(document) {
+		return eventfilter.PassFilter, ""
+	}
+	return eventfilter.FailFilter, "the JSONPath predicate doesn't hold"
+}
+
+var _ eventfilter.Filter = &dataFilter{}
+var _ eventfilter.Explainer = &dataFilter{}
+
+// IsJSON returns true for JSON media types. Events without a content type
+// are assumed to carry JSON data, as in the CloudEvents JSON event format.
+func IsJSON(mediaType string) bool {
+	switch mediaType {
+	case "", cloudevents.ApplicationJSON, "text/json":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package data
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+func TestDataFilter_Filter(t *testing.T) {
+	tests := map[string]struct {
+		contentType     string
+		data            []byte
+		want            eventfilter.FilterResult
+		wantParseErrors int
+	}{
+		"JSON": {
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"customer": {"tier": "gold"}}`),
+			want:        eventfilter.PassFilter,
+		},
+		"JSON not matching": {
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"customer": {"tier": "silver"}}`),
+			want:        eventfilter.FailFilter,
+		},
+		"JSON with charset": {
+			contentType: "application/json; charset=utf-8",
+			data:        []byte(`{"customer": {"tier": "gold"}}`),
+			want:        eventfilter.PassFilter,
+		},
+		"JSON suffix": {
+			contentType: "application/vnd.acme+json",
+			data:        []byte(`{"customer": {"tier": "gold"}}`),
+			want:        eventfilter.PassFilter,
+		},
+		"No content type": {
+			data: []byte(`{"customer": {"tier": "gold"}}`),
+			want: eventfilter.PassFilter,
+		},
+		"Not JSON": {
+			contentType: "text/plain",
+			data:        []byte(`{"customer": {"tier": "gold"}}`),
+			want:        eventfilter.FailFilter,
+		},
+		"No data": {
+			contentType: cloudevents.ApplicationJSON,
+			want:        eventfilter.FailFilter,
+		},
+		"Malformed JSON": {
+			contentType:     cloudevents.ApplicationJSON,
+			data:            []byte(`{"customer": `),
+			want:            eventfilter.FailFilter,
+			wantParseErrors: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewDataFilter(`$.customer.tier == "gold"`)
+			if err != nil {
+				t.Fatal("NewDataFilter() =", err)
+			}
+			e := cloudevents.NewEvent()
+			e.SetID("1234")
+			e.SetType("type")
+			e.SetSource("source")
+			e.DataEncoded = tt.data
+			if tt.contentType != "" {
+				e.SetDataContentType(tt.contentType)
+			}
+
+			parseErrors := 0
+			ctx := WithParseErrorReporter(context.TODO(), func(error) {
+				parseErrors++
+			})
+			if got := f.Filter(ctx, e); got != tt.want {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+			if parseErrors != tt.wantParseErrors {
+				t.Errorf("Reported %d parse errors, want %d", parseErrors, tt.wantParseErrors)
+			}
+		})
+	}
+}
+
+func TestDataFilter_Explain(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		data        []byte
+		want        eventfilter.Explanation
+	}{
+		"JSON": {
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"customer": {"tier": "gold"}}`),
+			want:        eventfilter.Explanation{Result: eventfilter.PassFilter},
+		},
+		"JSON not matching": {
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"customer": {"tier": "silver"}}`),
+			want:        eventfilter.Explanation{Result: eventfilter.FailFilter, Attribute: "data", Reason: "the JSONPath predicate doesn't hold"},
+		},
+		"Not JSON": {
+			contentType: "text/plain",
+			data:        []byte(`{"customer": {"tier": "gold"}}`),
+			want:        eventfilter.Explanation{Result: eventfilter.FailFilter, Attribute: "data", Reason: "the event data is not JSON"},
+		},
+		"Malformed JSON": {
+			contentType: cloudevents.ApplicationJSON,
+			data:        []byte(`{"customer": `),
+			want:        eventfilter.Explanation{Result: eventfilter.FailFilter, Attribute: "data", Reason: "the event data is not valid JSON"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewDataFilter(`$.customer.tier == "gold"`)
+			if err != nil {
+				t.Fatal("NewDataFilter() =", err)
+			}
+			e := cloudevents.NewEvent()
+			e.SetID("1234")
+			e.SetType("type")
+			e.SetSource("source")
+			e.DataEncoded = tt.data
+			e.SetDataContentType(tt.contentType)
+
+			if got := eventfilter.Explain(context.TODO(), f, e); got != tt.want {
+				t.Errorf("Explain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDataFilter_Invalid(t *testing.T) {
+	if _, err := NewDataFilter("customer.tier"); err == nil {
+		t.Error("Expected NewDataFilter() to fail")
+	}
+}
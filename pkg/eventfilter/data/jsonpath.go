@@ -0,0 +1,247 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Predicate is a parsed JSONPath predicate.
+//
+// The supported syntax is a JSONPath selecting values from the root object, optionally
+// followed by a comparison operator (==, !=, <, <=, >, >=) and a JSON literal. Strings can
+// also be quoted with single quotes. For example:
+//
+//	$.customer.tier == "gold"
+//	$.items[*].quantity > 10
+//	$['customer']['id']
+//
+// The path supports child members (.name or ['name']), array indexes ([0], [-1]) and
+// wildcards (.* or [*]). A predicate holds if any of the selected values satisfies the
+// comparison. A predicate without comparison holds if any of the selected values is
+// neither null nor false.
+type Predicate struct {
+	path     []segment
+	operator string
+	value    interface{}
+}
+
+// segment is a step of a path. Exactly one of the fields is set.
+type segment struct {
+	name     *string
+	index    *int
+	wildcard bool
+}
+
+var comparisonOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// ParsePredicate parses a JSONPath predicate.
+func ParsePredicate(expression string) (*Predicate, error) {
+	s := strings.TrimSpace(expression)
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("path must start with '$'")
+	}
+	p := &Predicate{}
+	i := 1
+	for i < len(s) {
+		switch s[i] {
+		case '.':
+			i++
+			if i < len(s) && s[i] == '*' {
+				p.path = append(p.path, segment{wildcard: true})
+				i++
+				continue
+			}
+			start := i
+			for i < len(s) && isNameChar(s[i]) {
+				i++
+			}
+			if start == i {
+				return nil, fmt.Errorf("missing member name at position %d", start)
+			}
+			name := s[start:i]
+			p.path = append(p.path, segment{name: &name})
+		case '[':
+			seg, n, err := parseBracket(s[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at position %d", err, i)
+			}
+			p.path = append(p.path, seg)
+			i += n
+		default:
+			return p, p.parseComparison(s[i:], i)
+		}
+	}
+	return p, nil
+}
+
+func (p *Predicate) parseComparison(s string, pos int) error {
+	rest := strings.TrimLeft(s, " \t")
+	pos += len(s) - len(rest)
+	for _, op := range comparisonOperators {
+		if strings.HasPrefix(rest, op) {
+			p.operator = op
+			value, err := parseLiteral(strings.TrimSpace(rest[len(op):]))
+			if err != nil {
+				return fmt.Errorf("invalid value at position %d: %v", pos+len(op), err)
+			}
+			p.value = value
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected %q at position %d", rest, pos)
+}
+
+// parseBracket parses a bracket segment ([0], [*] or ['name']), returning the number of bytes consumed.
+func parseBracket(s string) (segment, int, error) {
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return segment{}, 0, fmt.Errorf("unterminated bracket")
+	}
+	content := strings.TrimSpace(s[1:end])
+	if content == "*" {
+		return segment{wildcard: true}, end + 1, nil
+	}
+	if len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0] {
+		name := content[1 : len(content)-1]
+		return segment{name: &name}, end + 1, nil
+	}
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return segment{}, 0, fmt.Errorf("invalid index %q", content)
+	}
+	return segment{index: &index}, end + 1, nil
+}
+
+func parseLiteral(s string) (interface{}, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func isNameChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-'
+}
+
+// Evaluate evaluates the predicate on a document decoded by encoding/json.
+func (p *Predicate) Evaluate(document interface{}) bool {
+	for _, v := range p.selectValues(document) {
+		if p.holds(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Predicate) selectValues(document interface{}) []interface{} {
+	values := []interface{}{document}
+	for _, seg := range p.path {
+		var next []interface{}
+		for _, v := range values {
+			next = append(next, seg.apply(v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (seg segment) apply(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if seg.wildcard {
+			values := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				values = append(values, child)
+			}
+			return values
+		}
+		if seg.name != nil {
+			if child, ok := v[*seg.name]; ok {
+				return []interface{}{child}
+			}
+		}
+	case []interface{}:
+		if seg.wildcard {
+			return v
+		}
+		if seg.index != nil {
+			i := *seg.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i >= 0 && i < len(v) {
+				return []interface{}{v[i]}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Predicate) holds(v interface{}) bool {
+	switch p.operator {
+	case "":
+		return v != nil && v != false
+	case "==":
+		return reflect.DeepEqual(v, p.value)
+	case "!=":
+		return !reflect.DeepEqual(v, p.value)
+	}
+	cmp, ok := compare(v, p.value)
+	if !ok {
+		return false
+	}
+	switch p.operator {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// compare orders two numbers or two strings.
+func compare(x, y interface{}) (int, bool) {
+	switch x := x.(type) {
+	case float64:
+		if y, ok := y.(float64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	case string:
+		if y, ok := y.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package data
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const document = `{
+	"customer": {"id": "c-1", "tier": "gold", "vip": true, "nickname": null},
+	"items": [{"sku": "a", "quantity": 5}, {"sku": "b", "quantity": 15}],
+	"total": 120.5,
+	"odd-key": "x"
+}`
+
+func TestParsePredicate(t *testing.T) {
+	tests := map[string]bool{
+		"$":                                false,
+		"$.customer.tier":                  false,
+		`$.customer.tier == "gold"`:        false,
+		"$.customer.tier == 'gold'":        false,
+		"$['customer'][\"tier\"] != null":  false,
+		"$.items[0].quantity >= 5":         false,
+		"$.items[*].quantity<10":           false,
+		"$.*":                              false,
+		"":                                 true,
+		"customer.tier":                    true,
+		"$.":                               true,
+		"$.customer.tier = 'gold'":         true,
+		"$.customer.tier == gold":          true,
+		"$.items[a]":                       true,
+		"$.items[0":                        true,
+		`$.customer.tier == "gold" extra`:  true,
+		"$.customer.tier ==":               true,
+		"$.customer tier":                  true,
+		"$.items[0].quantity > 5 && false": true,
+	}
+	for expression, wantErr := range tests {
+		t.Run(expression, func(t *testing.T) {
+			_, err := ParsePredicate(expression)
+			if wantErr != (err != nil) {
+				t.Errorf("ParsePredicate(%q) error = %v, wantErr %v", expression, err, wantErr)
+			}
+		})
+	}
+}
+
+func TestPredicate_Evaluate(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(document), &doc); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]bool{
+		"$":                                         true,
+		"$.customer.tier":                           true,
+		"$.customer.missing":                        false,
+		"$.customer.nickname":                       false,
+		"$.customer.vip":                            true,
+		`$.customer.tier == "gold"`:                 true,
+		"$.customer.tier == 'gold'":                 true,
+		`$.customer.tier == "silver"`:               false,
+		`$.customer.tier != "silver"`:               true,
+		"$.customer.nickname == null":               true,
+		"$.customer.vip == true":                    true,
+		"$['customer']['id'] == 'c-1'":              true,
+		"$['odd-key'] == 'x'":                       true,
+		"$.odd-key == 'x'":                          true,
+		"$.total > 100":                             true,
+		"$.total <= 100":                            false,
+		"$.total > '100'":                           false,
+		"$.customer.id < 'c-2'":                     true,
+		"$.items[0].sku == 'a'":                     true,
+		"$.items[-1].sku == 'b'":                    true,
+		"$.items[2].sku == 'c'":                     false,
+		"$.items[*].quantity > 10":                  true,
+		"$.items[*].quantity > 20":                  false,
+		"$.items.*.sku == 'b'":                      true,
+		`$.items[0] == {"sku": "a", "quantity": 5}`: true,
+		"$.customer[0]":                             false,
+		"$.items.sku":                               false,
+	}
+	for expression, want := range tests {
+		t.Run(expression, func(t *testing.T) {
+			p, err := ParsePredicate(expression)
+			if err != nil {
+				t.Fatalf("ParsePredicate(%q) = %v", expression, err)
+			}
+			if got := p.Evaluate(doc); got != want {
+				t.Errorf("Evaluate() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -44,12 +44,59 @@ func (x FilterResult) And(y FilterResult) FilterResult {
 	return FailFilter
 }
 
+func (x FilterResult) Or(y FilterResult) FilterResult {
+	if x == NoFilter {
+		return y
+	}
+	if y == NoFilter {
+		return x
+	}
+	if x == PassFilter || y == PassFilter {
+		return PassFilter
+	}
+	return FailFilter
+}
+
+func (x FilterResult) Not() FilterResult {
+	switch x {
+	case PassFilter:
+		return FailFilter
+	case FailFilter:
+		return PassFilter
+	}
+	return x
+}
+
 // Filter is an interface representing an event filter of the trigger filter
 type Filter interface {
 	// Filter compute the predicate on the provided event and returns the result of the matching
 	Filter(ctx context.Context, event cloudevents.Event) FilterResult
 }
 
+// Explanation has the result of the filtering operation along with the reason of a failure.
+type Explanation struct {
+	Result FilterResult `json:"result"`
+	// Attribute is the name of the event attribute which made the filter fail, if known.
+	Attribute string `json:"attribute,omitempty"`
+	// Reason describes why the filter failed.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Explainer is an interface implemented by the event filters which can explain their result
+type Explainer interface {
+	// Explain compute the predicate on the provided event like Filter and explains the result
+	Explain(ctx context.Context, event cloudevents.Event) Explanation
+}
+
+// Explain computes the filter on the provided event and explains the result.
+// Filters which don't implement Explainer only give their result.
+func Explain(ctx context.Context, f Filter, event cloudevents.Event) Explanation {
+	if e, ok := f.(Explainer); ok {
+		return e.Explain(ctx, event)
+	}
+	return Explanation{Result: f.Filter(ctx, event)}
+}
+
 // Filters is a wrapper that runs each filter and performs the and
 type Filters []Filter
 
@@ -65,4 +112,17 @@ func (filters Filters) Filter(ctx context.Context, event cloudevents.Event) Filt
 	return res
 }
 
+func (filters Filters) Explain(ctx context.Context, event cloudevents.Event) Explanation {
+	res := Explanation{Result: NoFilter}
+	for _, f := range filters {
+		e := Explain(ctx, f, event)
+		if e.Result == FailFilter {
+			return e
+		}
+		res.Result = res.Result.And(e.Result)
+	}
+	return res
+}
+
 var _ Filter = Filters{}
+var _ Explainer = Filters{}
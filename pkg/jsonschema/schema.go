@@ -0,0 +1,668 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema validates JSON documents against JSON Schemas.
+//
+// It implements a subset of JSON Schema draft-07. The keywords it enforces are
+// exactly:
+//
+//   - $ref, to a JSON pointer within the same schema ("#" or "#/..."). The
+//     other keywords of a schema with $ref are ignored, as in draft-07.
+//   - type (a name or an array of names), enum and const.
+//   - minimum, maximum, exclusiveMinimum and exclusiveMaximum, either numbers
+//     (draft-06 and later) or booleans modifying minimum and maximum (draft-04),
+//     and multipleOf.
+//   - minLength, maxLength and pattern. Patterns are Go regular expressions
+//     (RE2 syntax) rather than ECMA 262 ones, and lengths count code points.
+//   - items (a schema or an array of schemas), minItems and maxItems.
+//   - properties, patternProperties, additionalProperties, required,
+//     minProperties and maxProperties.
+//   - allOf, anyOf, oneOf and not.
+//
+// Compile rejects the schemas using the other validation keywords of draft-07,
+// additionalItems, contains, uniqueItems, propertyNames, dependencies, if, then
+// and else, rather than partially enforcing them. Any other keyword, e.g.
+// $schema, $id, definitions, title, description, default, examples or format,
+// is ignored. Remote references are not resolved.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxErrors is the maximum number of errors reported by Validate.
+const maxErrors = 10
+
+// unsupportedKeywords are the validation keywords of draft-07 which are not
+// implemented. Ignoring them would accept documents the schema rejects.
+var unsupportedKeywords = []string{
+	"additionalItems",
+	"contains",
+	"uniqueItems",
+	"propertyNames",
+	"dependencies",
+	"if",
+	"then",
+	"else",
+}
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	// always is set for the boolean schemas true and false.
+	always *bool
+
+	// ref is the schema referenced with $ref.
+	ref *Schema
+
+	types      []string
+	enum       []interface{}
+	hasConst   bool
+	constValue interface{}
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	items      *Schema
+	tupleItems []*Schema
+	minItems   *int
+	maxItems   *int
+
+	properties           map[string]*Schema
+	patternProperties    []patternProperty
+	required             []string
+	additionalProperties *Schema
+	minProperties        *int
+	maxProperties        *int
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+// patternProperty is the schema of the properties whose name matches the
+// pattern.
+type patternProperty struct {
+	pattern *regexp.Regexp
+	schema  *Schema
+}
+
+// Compile parses and compiles the JSON Schema.
+func Compile(schema []byte) (*Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, fmt.Errorf("the schema is not valid JSON: %w", err)
+	}
+	c := &compiler{root: doc, refs: make(map[string]*Schema)}
+	return c.compile(doc, "#")
+}
+
+type compiler struct {
+	root interface{}
+	// refs are the schemas referenced with $ref, keyed by reference.
+	refs map[string]*Schema
+}
+
+func (c *compiler) compile(doc interface{}, path string) (*Schema, error) {
+	if b, ok := doc.(bool); ok {
+		return &Schema{always: &b}, nil
+	}
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: a schema must be an object or a boolean", path)
+	}
+
+	s := &Schema{}
+	var err error
+	if ref, ok := m["$ref"]; ok {
+		r, ok := ref.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s/$ref: must be a string", path)
+		}
+		if s.ref, err = c.resolve(r); err != nil {
+			return nil, fmt.Errorf("%s/$ref: %w", path, err)
+		}
+		// Keywords next to $ref are ignored in draft-07.
+		return s, nil
+	}
+
+	p := func(keyword string) string { return path + "/" + keyword }
+
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := m[keyword]; ok {
+			return nil, fmt.Errorf("%s: keyword is not supported", p(keyword))
+		}
+	}
+
+	switch t := m["type"].(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			str, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a string or an array of strings", p("type"))
+			}
+			s.types = append(s.types, str)
+		}
+	default:
+		return nil, fmt.Errorf("%s: must be a string or an array of strings", p("type"))
+	}
+	for _, t := range s.types {
+		switch t {
+		case "null", "boolean", "object", "array", "number", "string", "integer":
+		default:
+			return nil, fmt.Errorf("%s: unknown type %q", p("type"), t)
+		}
+	}
+
+	if v, ok := m["enum"]; ok {
+		if s.enum, ok = v.([]interface{}); !ok {
+			return nil, fmt.Errorf("%s: must be an array", p("enum"))
+		}
+	}
+	if v, ok := m["const"]; ok {
+		s.hasConst = true
+		s.constValue = v
+	}
+
+	for keyword, dst := range map[string]**float64{
+		"minimum":    &s.minimum,
+		"maximum":    &s.maximum,
+		"multipleOf": &s.multipleOf,
+	} {
+		if *dst, err = number(m, keyword, path); err != nil {
+			return nil, err
+		}
+	}
+	// exclusiveMinimum and exclusiveMaximum are numbers since draft-06, and
+	// booleans modifying minimum and maximum in draft-04.
+	for keyword, bound := range map[string]struct{ exclusive, inclusive **float64 }{
+		"exclusiveMinimum": {&s.exclusiveMinimum, &s.minimum},
+		"exclusiveMaximum": {&s.exclusiveMaximum, &s.maximum},
+	} {
+		if b, ok := m[keyword].(bool); ok {
+			if b {
+				*bound.exclusive, *bound.inclusive = *bound.inclusive, nil
+			}
+			continue
+		}
+		if *bound.exclusive, err = number(m, keyword, path); err != nil {
+			return nil, err
+		}
+	}
+	if s.multipleOf != nil && *s.multipleOf <= 0 {
+		return nil, fmt.Errorf("%s: must be greater than 0", p("multipleOf"))
+	}
+
+	for keyword, dst := range map[string]**int{
+		"minLength":     &s.minLength,
+		"maxLength":     &s.maxLength,
+		"minItems":      &s.minItems,
+		"maxItems":      &s.maxItems,
+		"minProperties": &s.minProperties,
+		"maxProperties": &s.maxProperties,
+	} {
+		if *dst, err = count(m, keyword, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := m["pattern"]; ok {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: must be a string", p("pattern"))
+		}
+		if s.pattern, err = regexp.Compile(str); err != nil {
+			return nil, fmt.Errorf("%s: %w", p("pattern"), err)
+		}
+	}
+
+	switch items := m["items"].(type) {
+	case nil:
+	case []interface{}:
+		for i, item := range items {
+			is, err := c.compile(item, p("items")+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			s.tupleItems = append(s.tupleItems, is)
+		}
+	default:
+		if s.items, err = c.compile(items, p("items")); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := m["properties"]; ok {
+		props, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: must be an object", p("properties"))
+		}
+		s.properties = make(map[string]*Schema, len(props))
+		for name, prop := range props {
+			if s.properties[name], err = c.compile(prop, p("properties")+"/"+name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if v, ok := m["patternProperties"]; ok {
+		props, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: must be an object", p("patternProperties"))
+		}
+		// Compile in a stable order, so that the errors are deterministic.
+		patterns := make([]string, 0, len(props))
+		for pattern := range props {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", p("patternProperties"), pattern, err)
+			}
+			ps, err := c.compile(props[pattern], p("patternProperties")+"/"+pattern)
+			if err != nil {
+				return nil, err
+			}
+			s.patternProperties = append(s.patternProperties, patternProperty{pattern: re, schema: ps})
+		}
+	}
+	if v, ok := m["required"]; ok {
+		required, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: must be an array of strings", p("required"))
+		}
+		for _, r := range required {
+			str, ok := r.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array of strings", p("required"))
+			}
+			s.required = append(s.required, str)
+		}
+	}
+	if v, ok := m["additionalProperties"]; ok {
+		if s.additionalProperties, err = c.compile(v, p("additionalProperties")); err != nil {
+			return nil, err
+		}
+	}
+
+	for keyword, dst := range map[string]*[]*Schema{
+		"allOf": &s.allOf,
+		"anyOf": &s.anyOf,
+		"oneOf": &s.oneOf,
+	} {
+		v, ok := m[keyword]
+		if !ok {
+			continue
+		}
+		schemas, ok := v.([]interface{})
+		if !ok || len(schemas) == 0 {
+			return nil, fmt.Errorf("%s: must be a non-empty array", p(keyword))
+		}
+		for i, sub := range schemas {
+			cs, err := c.compile(sub, p(keyword)+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			*dst = append(*dst, cs)
+		}
+	}
+	if v, ok := m["not"]; ok {
+		if s.not, err = c.compile(v, p("not")); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// resolve compiles the schema the local reference points to, e.g.
+// #/definitions/address.
+func (c *compiler) resolve(ref string) (*Schema, error) {
+	if rs, ok := c.refs[ref]; ok {
+		// Already resolved, or being resolved for recursive schemas.
+		return rs, nil
+	}
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, errors.New("only references within the schema are supported")
+	}
+	// Register the schema before compiling it, so that recursive references
+	// point to it.
+	rs := &Schema{}
+	c.refs[ref] = rs
+
+	doc := c.root
+	if ref != "#" {
+		for _, token := range strings.Split(ref[2:], "/") {
+			token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+			m, ok := doc.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("reference %q not found", ref)
+			}
+			if doc, ok = m[token]; !ok {
+				return nil, fmt.Errorf("reference %q not found", ref)
+			}
+		}
+	}
+	s, err := c.compile(doc, ref)
+	if err != nil {
+		return nil, err
+	}
+	*rs = *s
+	return rs, nil
+}
+
+func number(m map[string]interface{}, keyword, path string) (*float64, error) {
+	v, ok := m[keyword]
+	if !ok {
+		return nil, nil
+	}
+	f, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: must be a number", path, keyword)
+	}
+	return &f, nil
+}
+
+func count(m map[string]interface{}, keyword, path string) (*int, error) {
+	v, ok := m[keyword]
+	if !ok {
+		return nil, nil
+	}
+	f, ok := v.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, fmt.Errorf("%s/%s: must be a non-negative integer", path, keyword)
+	}
+	i := int(f)
+	return &i, nil
+}
+
+// ValidationError describes why a JSON document doesn't conform to a schema.
+type ValidationError struct {
+	// Errors are the messages of the violations, prefixed with the JSON
+	// pointer of the invalid value.
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Errors, "; ")
+}
+
+// Validate validates the JSON document against the schema. It returns a
+// *ValidationError if the document doesn't conform to the schema.
+func (s *Schema) Validate(document []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return err
+	}
+	return s.ValidateValue(doc)
+}
+
+// ValidateValue validates the value, decoded by encoding/json, against the
+// schema. It returns a *ValidationError if the value doesn't conform to the
+// schema.
+func (s *Schema) ValidateValue(value interface{}) error {
+	v := &validation{}
+	s.validate(v, value, "")
+	if len(v.errors) == 0 {
+		return nil
+	}
+	if len(v.errors) > maxErrors {
+		v.errors = append(v.errors[:maxErrors], fmt.Sprintf("and %d more errors", len(v.errors)-maxErrors))
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+type validation struct {
+	errors []string
+}
+
+func (v *validation) addf(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "/"
+	}
+	v.errors = append(v.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+// valid reports whether the value conforms to the schema, without recording
+// the errors.
+func (s *Schema) valid(value interface{}) bool {
+	v := &validation{}
+	s.validate(v, value, "")
+	return len(v.errors) == 0
+}
+
+func (s *Schema) validate(v *validation, value interface{}, path string) {
+	if s.always != nil {
+		if !*s.always {
+			v.addf(path, "no value is allowed")
+		}
+		return
+	}
+	if s.ref != nil {
+		s.ref.validate(v, value, path)
+		return
+	}
+
+	if len(s.types) > 0 && !hasType(value, s.types) {
+		v.addf(path, "expected %s, got %s", strings.Join(s.types, " or "), typeOf(value))
+		// The other keywords don't apply to a value of the wrong type.
+		return
+	}
+	if s.enum != nil && !contains(s.enum, value) {
+		v.addf(path, "value must be one of %s", marshal(s.enum))
+	}
+	if s.hasConst && !reflect.DeepEqual(s.constValue, value) {
+		v.addf(path, "value must be %s", marshal(s.constValue))
+	}
+
+	switch val := value.(type) {
+	case float64:
+		s.validateNumber(v, val, path)
+	case string:
+		s.validateString(v, val, path)
+	case []interface{}:
+		s.validateArray(v, val, path)
+	case map[string]interface{}:
+		s.validateObject(v, val, path)
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(v, value, path)
+	}
+	if s.anyOf != nil {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.valid(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addf(path, "value doesn't match any of the anyOf schemas")
+		}
+	}
+	if s.oneOf != nil {
+		matches := 0
+		for _, sub := range s.oneOf {
+			if sub.valid(value) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			v.addf(path, "value must match exactly one of the oneOf schemas, matched %d", matches)
+		}
+	}
+	if s.not != nil && s.not.valid(value) {
+		v.addf(path, "value must not match the not schema")
+	}
+}
+
+func (s *Schema) validateNumber(v *validation, n float64, path string) {
+	if s.minimum != nil && n < *s.minimum {
+		v.addf(path, "value %v is less than the minimum %v", n, *s.minimum)
+	}
+	if s.maximum != nil && n > *s.maximum {
+		v.addf(path, "value %v is greater than the maximum %v", n, *s.maximum)
+	}
+	if s.exclusiveMinimum != nil && n <= *s.exclusiveMinimum {
+		v.addf(path, "value %v must be greater than %v", n, *s.exclusiveMinimum)
+	}
+	if s.exclusiveMaximum != nil && n >= *s.exclusiveMaximum {
+		v.addf(path, "value %v must be less than %v", n, *s.exclusiveMaximum)
+	}
+	if s.multipleOf != nil {
+		if q := n / *s.multipleOf; q != math.Trunc(q) {
+			v.addf(path, "value %v is not a multiple of %v", n, *s.multipleOf)
+		}
+	}
+}
+
+func (s *Schema) validateString(v *validation, str string, path string) {
+	length := utf8.RuneCountInString(str)
+	if s.minLength != nil && length < *s.minLength {
+		v.addf(path, "length %d is less than the minimum length %d", length, *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		v.addf(path, "length %d is greater than the maximum length %d", length, *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		v.addf(path, "value %q doesn't match the pattern %q", str, s.pattern.String())
+	}
+}
+
+func (s *Schema) validateArray(v *validation, arr []interface{}, path string) {
+	if s.minItems != nil && len(arr) < *s.minItems {
+		v.addf(path, "%d items are less than the minimum %d", len(arr), *s.minItems)
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		v.addf(path, "%d items are more than the maximum %d", len(arr), *s.maxItems)
+	}
+	for i, item := range arr {
+		switch {
+		case s.items != nil:
+			s.items.validate(v, item, path+"/"+strconv.Itoa(i))
+		case i < len(s.tupleItems):
+			s.tupleItems[i].validate(v, item, path+"/"+strconv.Itoa(i))
+		}
+	}
+}
+
+func (s *Schema) validateObject(v *validation, obj map[string]interface{}, path string) {
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		v.addf(path, "%d properties are less than the minimum %d", len(obj), *s.minProperties)
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		v.addf(path, "%d properties are more than the maximum %d", len(obj), *s.maxProperties)
+	}
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			v.addf(path, "missing required property %q", name)
+		}
+	}
+
+	// Iterate in a stable order, so that the errors are deterministic.
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propPath := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+		ps, matched := s.properties[name]
+		if matched {
+			ps.validate(v, obj[name], propPath)
+		}
+		for _, pp := range s.patternProperties {
+			if pp.pattern.MatchString(name) {
+				matched = true
+				pp.schema.validate(v, obj[name], propPath)
+			}
+		}
+		if !matched && s.additionalProperties != nil {
+			if s.additionalProperties.always != nil && !*s.additionalProperties.always {
+				v.addf(propPath, "additional property is not allowed")
+				continue
+			}
+			s.additionalProperties.validate(v, obj[name], propPath)
+		}
+	}
+}
+
+func hasType(value interface{}, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if f, ok := value.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		default:
+			if typeOf(value) == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func marshal(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const orderSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["id", "customer", "items"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "string", "pattern": "^o-[0-9]+$"},
+    "customer": {"$ref": "#/definitions/customer"},
+    "amount": {"type": "number", "minimum": 0, "exclusiveMaximum": 10000},
+    "quantity": {"type": "integer", "multipleOf": 2},
+    "items": {"type": "array", "minItems": 1, "items": {"type": "string", "minLength": 1}},
+    "coupon": {"oneOf": [{"type": "null"}, {"type": "string", "maxLength": 8}]},
+    "status": {"enum": ["new", "paid"]},
+    "version": {"const": 1}
+  },
+  "definitions": {
+    "customer": {
+      "type": "object",
+      "required": ["tier"],
+      "properties": {
+        "tier": {"type": "string", "enum": ["gold", "silver"]},
+        "referrer": {"$ref": "#/definitions/customer"}
+      }
+    }
+  }
+}`
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := Compile([]byte(orderSchema))
+	if err != nil {
+		t.Fatal("Compile() =", err)
+	}
+
+	tests := map[string]struct {
+		document string
+		want     []string
+	}{
+		"valid": {
+			document: `{"id": "o-1", "customer": {"tier": "gold", "referrer": {"tier": "silver"}}, "amount": 10.5, "quantity": 4, "items": ["a"], "coupon": null, "status": "new", "version": 1}`,
+		},
+		"wrong type": {
+			document: `[]`,
+			want:     []string{"/: expected object, got array"},
+		},
+		"missing properties": {
+			document: `{"id": "o-1"}`,
+			want: []string{
+				`/: missing required property "customer"`,
+				`/: missing required property "items"`,
+			},
+		},
+		"additional property": {
+			document: `{"id": "o-1", "customer": {"tier": "gold"}, "items": ["a"], "extra": true}`,
+			want:     []string{"/extra: additional property is not allowed"},
+		},
+		"referenced schema": {
+			document: `{"id": "o-1", "customer": {"tier": "gold", "referrer": {"tier": "bronze"}}, "items": ["a"]}`,
+			want:     []string{`/customer/referrer/tier: value must be one of ["gold","silver"]`},
+		},
+		"values": {
+			document: `{"id": "order", "customer": {"tier": "gold"}, "amount": 10000, "quantity": 3, "items": [], "coupon": "SUMMERSALE", "status": "lost", "version": 2}`,
+			want: []string{
+				"/amount: value 10000 must be less than 10000",
+				`/coupon: value must match exactly one of the oneOf schemas, matched 0`,
+				`/id: value "order" doesn't match the pattern "^o-[0-9]+$"`,
+				"/items: 0 items are less than the minimum 1",
+				"/quantity: value 3 is not a multiple of 2",
+				`/status: value must be one of ["new","paid"]`,
+				"/version: value must be 1",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := s.Validate([]byte(tc.document))
+			var got []string
+			if err != nil {
+				var ve *ValidationError
+				if !errors.As(err, &ve) {
+					t.Fatal("Validate() =", err)
+				}
+				got = ve.Errors
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("Unexpected errors (-want +got):", diff)
+			}
+		})
+	}
+}
+
+func TestSchema_Combinators(t *testing.T) {
+	s, err := Compile([]byte(`{"allOf": [{"type": "integer"}, {"minimum": 1}], "not": {"const": 13}, "anyOf": [{"maximum": 10}, {"multipleOf": 100}]}`))
+	if err != nil {
+		t.Fatal("Compile() =", err)
+	}
+	for _, valid := range []string{"1", "10", "200"} {
+		if err := s.Validate([]byte(valid)); err != nil {
+			t.Errorf("Validate(%s) = %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"0", "13", "50", "1.5", `"1"`} {
+		if err := s.Validate([]byte(invalid)); err == nil {
+			t.Errorf("Expected Validate(%s) to fail", invalid)
+		}
+	}
+}
+
+func TestSchema_PatternProperties(t *testing.T) {
+	s, err := Compile([]byte(`{"properties": {"id": {"type": "string"}}, "patternProperties": {"^x-": {"type": "string"}, "count$": {"type": "integer"}}, "additionalProperties": false}`))
+	if err != nil {
+		t.Fatal("Compile() =", err)
+	}
+	if err := s.Validate([]byte(`{"id": "1", "x-origin": "eu", "count": 3, "retry_count": 2}`)); err != nil {
+		t.Error("Validate() =", err)
+	}
+	err = s.Validate([]byte(`{"id": "1", "x-origin": 2, "extra": true}`))
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatal("Validate() =", err)
+	}
+	want := []string{
+		"/extra: additional property is not allowed",
+		"/x-origin: expected string, got number",
+	}
+	if diff := cmp.Diff(want, ve.Errors); diff != "" {
+		t.Error("Unexpected errors (-want +got):", diff)
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for name, schema := range map[string]string{
+		"not JSON":                   `{"type": `,
+		"not a schema":               `42`,
+		"unknown type":               `{"type": "decimal"}`,
+		"invalid pattern":            `{"pattern": "("}`,
+		"invalid count":              `{"minItems": -1}`,
+		"remote reference":           `{"$ref": "http://example.com/schema.json"}`,
+		"missing reference":          `{"$ref": "#/definitions/missing"}`,
+		"invalid multipleOf":         `{"multipleOf": 0}`,
+		"invalid combinators":        `{"anyOf": []}`,
+		"invalid pattern property":   `{"patternProperties": {"(": {}}}`,
+		"unsupported keyword":        `{"uniqueItems": true}`,
+		"nested unsupported keyword": `{"properties": {"a": {"if": {"type": "string"}, "then": {"minLength": 1}}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Compile([]byte(schema)); err == nil {
+				t.Error("Expected Compile() to fail")
+			}
+		})
+	}
+}
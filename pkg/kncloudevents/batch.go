@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	nethttp "net/http"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+const (
+	// MaxBatchSize is the maximum number of events accepted in a batch.
+	MaxBatchSize = 1000
+
+	// MaxBatchBytes is the maximum size of the body of a batch.
+	MaxBatchBytes = 10 << 20
+)
+
+var (
+	// ErrEmptyBatch is returned when reading a batch without events.
+	ErrEmptyBatch = errors.New("the batch contains no events")
+
+	// ErrBatchTooLarge is returned when reading a batch of more than
+	// MaxBatchSize events, or of more than MaxBatchBytes bytes.
+	ErrBatchTooLarge = fmt.Errorf("the batch contains more than %d events or %d bytes", MaxBatchSize, MaxBatchBytes)
+)
+
+// IsBatchRequest returns whether the request carries a batch of CloudEvents,
+// encoded in the JSON batched content mode.
+func IsBatchRequest(request *nethttp.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return err == nil && mediaType == event.ApplicationCloudEventsBatchJSON
+}
+
+// ReadBatch reads the batch of CloudEvents, encoded in the JSON batched
+// content mode, sent in the body of the request. The events are decoded
+// independently, so that an invalid event doesn't fail the whole batch: if the
+// i-th event is invalid, events[i] is nil and errs[i] describes why.
+//
+// The body is read as a stream, and no more than MaxBatchBytes bytes and
+// MaxBatchSize events are read.
+func ReadBatch(writer nethttp.ResponseWriter, request *nethttp.Request) (events []*event.Event, errs []error, err error) {
+	body := &countingReader{r: nethttp.MaxBytesReader(writer, request.Body, MaxBatchBytes)}
+	decodeError := func(err error) error {
+		if body.n >= MaxBatchBytes {
+			return ErrBatchTooLarge
+		}
+		return fmt.Errorf("failed to decode the batch: %w", err)
+	}
+
+	decoder := json.NewDecoder(body)
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, decodeError(err)
+	}
+	if token != json.Delim('[') {
+		return nil, nil, decodeError(errors.New("the batch is not an array"))
+	}
+	for decoder.More() {
+		if len(events) == MaxBatchSize {
+			return nil, nil, ErrBatchTooLarge
+		}
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, nil, decodeError(err)
+		}
+		e, err := readBatchedEvent(raw)
+		events = append(events, e)
+		errs = append(errs, err)
+	}
+	// Read the end of the array.
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, decodeError(err)
+	}
+	if len(events) == 0 {
+		return nil, nil, ErrEmptyBatch
+	}
+	return events, errs, nil
+}
+
+func readBatchedEvent(raw json.RawMessage) (*event.Event, error) {
+	e := event.New()
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, err
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// BatchResult is the outcome of an event of a batch.
+type BatchResult struct {
+	// ID is the id of the event, unless it couldn't be decoded.
+	ID string `json:"id,omitempty"`
+	// Status is the status code the event would have been answered with,
+	// had it been sent on its own.
+	Status int `json:"status"`
+	// Error describes why the event was rejected.
+	Error string `json:"error,omitempty"`
+}
+
+// BatchResponse is the body of the response to a batch.
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
+
+// WriteBatchResponse writes the results of the events of a batch, in the
+// order of the batch. The response has the status code of the events if they
+// all share the same one, 207 Multi-Status otherwise.
+func WriteBatchResponse(writer nethttp.ResponseWriter, results []BatchResult) {
+	statusCode := nethttp.StatusMultiStatus
+	if len(results) > 0 {
+		statusCode = results[0].Status
+		for _, r := range results[1:] {
+			if r.Status != statusCode {
+				statusCode = nethttp.StatusMultiStatus
+				break
+			}
+		}
+	}
+
+	body, err := json.Marshal(BatchResponse{Results: results})
+	if err != nil {
+		writer.WriteHeader(nethttp.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	_, _ = writer.Write(body)
+}
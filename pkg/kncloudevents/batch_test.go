@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIsBatchRequest(t *testing.T) {
+	for contentType, want := range map[string]bool{
+		"application/cloudevents-batch+json":                true,
+		"application/cloudevents-batch+json; charset=utf-8": true,
+		"application/cloudevents+json":                      false,
+		"application/json":                                  false,
+		"":                                                  false,
+	} {
+		request := httptest.NewRequest(nethttp.MethodPost, "/", nil)
+		request.Header.Set("Content-Type", contentType)
+		if got := IsBatchRequest(request); got != want {
+			t.Errorf("IsBatchRequest(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
+
+func TestReadBatch(t *testing.T) {
+	events, errs, err := ReadBatch(newBatchRequest(`[
+		{"specversion": "1.0", "id": "1", "type": "type", "source": "/source", "data": {"a": 1}},
+		{"specversion": "1.0", "id": "2", "source": "/source"},
+		"not an event"
+	]`))
+	if err != nil {
+		t.Fatal("ReadBatch() =", err)
+	}
+	if len(events) != 3 || len(errs) != 3 {
+		t.Fatalf("Expected 3 events and errors, got %d and %d", len(events), len(errs))
+	}
+	if events[0] == nil || errs[0] != nil {
+		t.Fatalf("Expected the first event to be valid, got %v", errs[0])
+	}
+	if events[0].ID() != "1" || string(events[0].Data()) != `{"a": 1}` {
+		t.Errorf("Unexpected first event %v", events[0])
+	}
+	for i := 1; i < 3; i++ {
+		if events[i] != nil || errs[i] == nil {
+			t.Errorf("Expected the event %d to be invalid, got %v", i, events[i])
+		}
+	}
+}
+
+func TestReadBatchInvalid(t *testing.T) {
+	events := strings.Repeat(`{"specversion": "1.0", "id": "1", "type": "type", "source": "/source"},`, MaxBatchSize)
+	for name, test := range map[string]struct {
+		body string
+		err  error
+	}{
+		"not an array": {body: `{"specversion": "1.0", "id": "1", "type": "type", "source": "/source"}`},
+		"malformed":    {body: `[{`},
+		"unterminated": {body: `[{"specversion": "1.0", "id": "1", "type": "type", "source": "/source"}`},
+		"empty":        {body: `[]`, err: ErrEmptyBatch},
+		"too many events": {
+			body: "[" + events + `{"specversion": "1.0", "id": "1", "type": "type", "source": "/source"}]`,
+			err:  ErrBatchTooLarge,
+		},
+		"too many events, not read past the limit": {
+			body: "[" + events + "not an event",
+			err:  ErrBatchTooLarge,
+		},
+		"too many bytes": {
+			body: "[" + strings.Repeat(" ", MaxBatchBytes) + "]",
+			err:  ErrBatchTooLarge,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ReadBatch(newBatchRequest(test.body))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if test.err != nil && err != test.err {
+				t.Errorf("ReadBatch() = %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func newBatchRequest(body string) (nethttp.ResponseWriter, *nethttp.Request) {
+	return httptest.NewRecorder(), httptest.NewRequest(nethttp.MethodPost, "/", strings.NewReader(body))
+}
+
+func TestWriteBatchResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    []BatchResult
+		statusCode int
+	}{{
+		name:       "all accepted",
+		results:    []BatchResult{{ID: "1", Status: 202}, {ID: "2", Status: 202}},
+		statusCode: 202,
+	}, {
+		name:       "partial failure",
+		results:    []BatchResult{{ID: "1", Status: 202}, {ID: "2", Status: 500, Error: "boom"}},
+		statusCode: 207,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+			WriteBatchResponse(writer, tc.results)
+			if writer.Code != tc.statusCode {
+				t.Errorf("Expected status code %d, got %d", tc.statusCode, writer.Code)
+			}
+			if got := writer.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Unexpected content type %q", got)
+			}
+			var response BatchResponse
+			if err := json.Unmarshal(writer.Body.Bytes(), &response); err != nil {
+				t.Fatal("Failed to decode the response:", err)
+			}
+			if diff := cmp.Diff(tc.results, response.Results); diff != "" {
+				t.Error("Unexpected results (-want, +got):", diff)
+			}
+		})
+	}
+}
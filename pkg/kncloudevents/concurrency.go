@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultConcurrencyWaitTimeout is how long a request waits for a request to
+// the same destination to complete when the maximum concurrency of the
+// destination is reached, before failing.
+const DefaultConcurrencyWaitTimeout = 30 * time.Second
+
+// ErrConcurrencyWaitTimeout is returned when a request waited for too long
+// for a request to the same destination to complete.
+var ErrConcurrencyWaitTimeout = errors.New("timed out waiting for the in-flight requests to complete")
+
+// ConcurrencyLimiters limits the number of requests sent concurrently to each
+// destination, see RetryConfig.MaxConcurrency.
+type ConcurrencyLimiters struct {
+	waitTimeout time.Duration
+
+	mu sync.Mutex
+	// limiters holds the limiter of each destination with requests in
+	// flight or waiting.
+	limiters map[string]*concurrencyLimiter
+}
+
+// concurrencyLimiter limits the requests to a destination. The senders of a
+// destination setting different maximums, e.g. the Subscriptions of different
+// channels, share it: the latest maximum applies.
+type concurrencyLimiter struct {
+	max      int
+	inFlight int
+	// waiters holds the channel of each waiting request, in order. The
+	// channel is closed once the request is let through.
+	waiters *list.List
+}
+
+// NewConcurrencyLimiters creates the limiters, the requests waiting for
+// longer than waitTimeout fail with ErrConcurrencyWaitTimeout.
+// DefaultConcurrencyWaitTimeout is used if waitTimeout is 0.
+func NewConcurrencyLimiters(waitTimeout time.Duration) *ConcurrencyLimiters {
+	if waitTimeout <= 0 {
+		waitTimeout = DefaultConcurrencyWaitTimeout
+	}
+	return &ConcurrencyLimiters{
+		waitTimeout: waitTimeout,
+		limiters:    make(map[string]*concurrencyLimiter),
+	}
+}
+
+// Acquire waits until less than max requests to destination are in flight,
+// and returns how long it waited and the function to call once the request
+// completes. It fails if the wait times out or ctx is done meanwhile.
+func (l *ConcurrencyLimiters) Acquire(ctx context.Context, destination string, max int) (func(), time.Duration, error) {
+	l.mu.Lock()
+	limiter, ok := l.limiters[destination]
+	if !ok {
+		limiter = &concurrencyLimiter{waiters: list.New()}
+		l.limiters[destination] = limiter
+	}
+	limiter.max = max
+	// A larger maximum lets waiting requests through.
+	limiter.letWaitersThrough()
+	if limiter.inFlight < limiter.max && limiter.waiters.Len() == 0 {
+		limiter.inFlight++
+		l.mu.Unlock()
+		return l.releaseFunc(destination, limiter), 0, nil
+	}
+	ready := make(chan struct{})
+	waiter := limiter.waiters.PushBack(ready)
+	l.mu.Unlock()
+
+	start := time.Now()
+	timer := time.NewTimer(l.waitTimeout)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-ready:
+		return l.releaseFunc(destination, limiter), time.Since(start), nil
+	case <-timer.C:
+		err = ErrConcurrencyWaitTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-ready:
+		// The request was let through meanwhile, give the slot back.
+		limiter.inFlight--
+		limiter.letWaitersThrough()
+	default:
+		limiter.waiters.Remove(waiter)
+	}
+	l.evictIfIdle(destination, limiter)
+	return nil, time.Since(start), err
+}
+
+// releaseFunc returns the function releasing a slot of the limiter of
+// destination, which does nothing when called again.
+func (l *ConcurrencyLimiters) releaseFunc(destination string, limiter *concurrencyLimiter) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			limiter.inFlight--
+			limiter.letWaitersThrough()
+			l.evictIfIdle(destination, limiter)
+		})
+	}
+}
+
+// evictIfIdle forgets the limiter of destination if it has no request in
+// flight or waiting, l.mu must be held.
+func (l *ConcurrencyLimiters) evictIfIdle(destination string, limiter *concurrencyLimiter) {
+	if limiter.inFlight == 0 && limiter.waiters.Len() == 0 && l.limiters[destination] == limiter {
+		delete(l.limiters, destination)
+	}
+}
+
+// letWaitersThrough lets the waiting requests through, in order, while less
+// than max requests are in flight. The lock of the limiters must be held.
+func (c *concurrencyLimiter) letWaitersThrough() {
+	for c.inFlight < c.max && c.waiters.Len() > 0 {
+		close(c.waiters.Remove(c.waiters.Front()).(chan struct{}))
+		c.inFlight++
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimiters(t *testing.T) {
+	limiters := NewConcurrencyLimiters(50 * time.Millisecond)
+	ctx := context.Background()
+
+	release1, waited, err := limiters.Acquire(ctx, "http://a", 2)
+	if err != nil || waited != 0 {
+		t.Fatalf("Acquire() = %v, %v, want no wait", waited, err)
+	}
+	release2, _, err := limiters.Acquire(ctx, "http://a", 2)
+	if err != nil {
+		t.Fatal("Acquire() =", err)
+	}
+
+	// Other destinations have their own limit.
+	releaseB, _, err := limiters.Acquire(ctx, "http://b", 2)
+	if err != nil {
+		t.Fatal("Acquire() =", err)
+	}
+	releaseB()
+
+	// The limit of the destination is reached, the wait times out.
+	if _, waited, err := limiters.Acquire(ctx, "http://a", 2); !errors.Is(err, ErrConcurrencyWaitTimeout) {
+		t.Fatalf("Acquire() = %v, want %v", err, ErrConcurrencyWaitTimeout)
+	} else if waited < 50*time.Millisecond {
+		t.Errorf("Acquire() waited %v, want at least 50ms", waited)
+	}
+
+	// A request completing lets a waiting one through.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		release1()
+	}()
+	release3, waited, err := limiters.Acquire(ctx, "http://a", 2)
+	if err != nil {
+		t.Fatal("Acquire() =", err)
+	}
+	if waited == 0 {
+		t.Error("Acquire() didn't wait, want a wait")
+	}
+
+	// The wait ends when the context is done.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, err := limiters.Acquire(cancelled, "http://a", 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire() = %v, want %v", err, context.Canceled)
+	}
+
+	// The senders of a destination share its limit, the latest maximum
+	// applies.
+	if _, _, err := limiters.Acquire(ctx, "http://a", 1); !errors.Is(err, ErrConcurrencyWaitTimeout) {
+		t.Fatalf("Acquire() = %v, want %v", err, ErrConcurrencyWaitTimeout)
+	}
+	release4, _, err := limiters.Acquire(ctx, "http://a", 3)
+	if err != nil {
+		t.Fatal("Acquire() =", err)
+	}
+
+	// A larger maximum lets the waiting requests through.
+	waiting := make(chan error)
+	go func() {
+		release, _, err := limiters.Acquire(ctx, "http://a", 3)
+		if err == nil {
+			release()
+		}
+		waiting <- err
+	}()
+	waitForWaiters(t, limiters, "http://a", 1)
+	release5, _, err := limiters.Acquire(ctx, "http://a", 5)
+	if err != nil {
+		t.Fatal("Acquire() =", err)
+	}
+	if err := <-waiting; err != nil {
+		t.Fatal("Acquire() of the waiting request =", err)
+	}
+
+	// The limiters without requests in flight are forgotten.
+	release2()
+	release3()
+	release4()
+	release4()
+	release5()
+	if n := len(limiters.limiters); n != 0 {
+		t.Errorf("Expected the idle limiters to be evicted, got %d limiters", n)
+	}
+}
+
+// waitForWaiters waits until n requests to destination are waiting.
+func waitForWaiters(t *testing.T, limiters *ConcurrencyLimiters, destination string, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		limiters.mu.Lock()
+		waiters := limiters.limiters[destination].waiters.Len()
+		limiters.mu.Unlock()
+		if waiters == n {
+			return
+		}
+	}
+	t.Fatalf("Timed out waiting for %d requests to %s to wait", n, destination)
+}
@@ -20,7 +20,10 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	nethttp "net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -29,6 +32,11 @@ import (
 	duckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 )
 
+// DefaultRetryableStatusCodes are the status codes of the responses retried
+// when the DeliverySpec doesn't specify them: the server errors, and the
+// requests which timed out or were rate limited.
+var DefaultRetryableStatusCodes = []string{"5xx", "408", "429"}
+
 var noRetries = RetryConfig{
 	RetryMax: 0,
 	CheckRetry: func(ctx context.Context, resp *nethttp.Response, err error) (bool, error) {
@@ -87,20 +95,71 @@ type RetryConfig struct {
 	// These next two variables are just copied from the original DeliverySpec so
 	// we can detect if anything has changed. We can not do that with the CheckRetry
 	// Backoff (at least not easily).
-	BackoffDelay  *string
-	BackoffPolicy *duckv1.BackoffPolicyType
+	BackoffDelay    *string
+	BackoffMaxDelay *string
+	BackoffPolicy   *duckv1.BackoffPolicyType
+
+	// RetryableStatusCodes is copied from the original DeliverySpec too.
+	RetryableStatusCodes []string
+
+	// MaxConcurrency is the maximum number of requests sent concurrently to
+	// the destination, there is no limit if it is 0.
+	MaxConcurrency int
+
+	// RequestTimeout is the timeout of each attempt, including each retry.
+	// Attempts don't time out if it is 0.
+	RequestTimeout time.Duration
 
 	CheckRetry CheckRetry
 	Backoff    Backoff
 }
 
 func (s *HTTPMessageSender) SendWithRetries(req *nethttp.Request, config *RetryConfig) (*nethttp.Response, error) {
+	return s.SendWithRetriesAndAttempts(req, config, nil)
+}
+
+// Attempts records the attempts made to send a request.
+type Attempts struct {
+	// Count is the number of attempts, including the retries.
+	Count int
+	// First and Last are the times of the first and the last attempts.
+	First time.Time
+	Last  time.Time
+}
+
+func (a *Attempts) record() {
+	if a == nil {
+		return
+	}
+	now := time.Now()
+	if a.Count == 0 {
+		a.First = now
+	}
+	a.Count++
+	a.Last = now
+}
+
+// SendWithRetriesAndAttempts is like SendWithRetries, recording the attempts
+// made to send the request in attempts when it isn't nil.
+func (s *HTTPMessageSender) SendWithRetriesAndAttempts(req *nethttp.Request, config *RetryConfig, attempts *Attempts) (*nethttp.Response, error) {
 	if config == nil {
+		attempts.record()
 		return s.Send(req)
 	}
 
+	client := s.Client
+	if config.RequestTimeout != 0 {
+		// The timeout of the client applies to each attempt.
+		client = &nethttp.Client{
+			Transport:     client.Transport,
+			CheckRedirect: client.CheckRedirect,
+			Jar:           client.Jar,
+			Timeout:       config.RequestTimeout,
+		}
+	}
+
 	retryableClient := retryablehttp.Client{
-		HTTPClient:   s.Client,
+		HTTPClient:   client,
 		RetryWaitMin: defaultRetryWaitMin,
 		RetryWaitMax: defaultRetryWaitMax,
 		RetryMax:     config.RetryMax,
@@ -111,6 +170,9 @@ func (s *HTTPMessageSender) SendWithRetries(req *nethttp.Request, config *RetryC
 		ErrorHandler: func(resp *nethttp.Response, err error, numTries int) (*nethttp.Response, error) {
 			return resp, err
 		},
+		RequestLogHook: func(_ retryablehttp.Logger, _ *nethttp.Request, _ int) {
+			attempts.record()
+		},
 	}
 
 	retryableReq, err := retryablehttp.FromRequest(req)
@@ -129,14 +191,54 @@ func RetryConfigFromDeliverySpec(spec duckv1.DeliverySpec) (RetryConfig, error)
 
 	retryConfig := NoRetries()
 
-	retryConfig.CheckRetry = checkRetry
+	retryableStatusCodes := DefaultRetryableStatusCodes
+	if len(spec.RetryableStatusCodes) > 0 {
+		retryableStatusCodes = spec.RetryableStatusCodes
+	}
+	isRetryable, err := statusCodeMatcher(retryableStatusCodes)
+	if err != nil {
+		return retryConfig, fmt.Errorf("failed to parse Spec.RetryableStatusCodes: %w", err)
+	}
+	retryConfig.CheckRetry = func(_ context.Context, resp *nethttp.Response, err error) (bool, error) {
+		if resp == nil {
+			// The request failed without a response, e.g. it timed out.
+			return true, err
+		}
+		if resp.StatusCode >= nethttp.StatusOK && resp.StatusCode < nethttp.StatusMultipleChoices {
+			return false, err
+		}
+		return isRetryable(resp.StatusCode), err
+	}
+	retryConfig.RetryableStatusCodes = spec.RetryableStatusCodes
 
 	if spec.Retry != nil {
 		retryConfig.RetryMax = int(*spec.Retry)
 	}
+	if spec.MaxConcurrency != nil {
+		retryConfig.MaxConcurrency = int(*spec.MaxConcurrency)
+	}
 	retryConfig.BackoffPolicy = spec.BackoffPolicy
 	retryConfig.BackoffDelay = spec.BackoffDelay
+	retryConfig.BackoffMaxDelay = spec.BackoffMaxDelay
+
+	if spec.Timeout != nil {
+		timeout, err := period.Parse(*spec.Timeout)
+		if err != nil {
+			return retryConfig, fmt.Errorf("failed to parse Spec.Timeout: %w", err)
+		}
+		retryConfig.RequestTimeout, _ = timeout.Duration()
+	}
 
+	var maxDelay time.Duration
+	if spec.BackoffMaxDelay != nil {
+		d, err := period.Parse(*spec.BackoffMaxDelay)
+		if err != nil {
+			return retryConfig, fmt.Errorf("failed to parse Spec.BackoffMaxDelay: %w", err)
+		}
+		maxDelay, _ = d.Duration()
+	}
+
+	backoff := retryConfig.Backoff
 	if spec.BackoffPolicy != nil && spec.BackoffDelay != nil {
 
 		delay, err := period.Parse(*spec.BackoffDelay)
@@ -147,19 +249,99 @@ func RetryConfigFromDeliverySpec(spec duckv1.DeliverySpec) (RetryConfig, error)
 		delayDuration, _ := delay.Duration()
 		switch *spec.BackoffPolicy {
 		case duckv1.BackoffPolicyExponential:
-			retryConfig.Backoff = func(attemptNum int, resp *nethttp.Response) time.Duration {
+			backoff = func(attemptNum int, resp *nethttp.Response) time.Duration {
 				return delayDuration * time.Duration(math.Exp2(float64(attemptNum)))
 			}
+		case duckv1.BackoffPolicyExponentialJitter:
+			backoff = func(attemptNum int, resp *nethttp.Response) time.Duration {
+				// Randomize the delay between half and all of the capped
+				// exponential delay, so that senders retrying at the same
+				// time spread their retries.
+				d := capDelay(delayDuration*time.Duration(math.Exp2(float64(attemptNum))), maxDelay)
+				if d <= 0 {
+					return d
+				}
+				return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+			}
 		case duckv1.BackoffPolicyLinear:
-			retryConfig.Backoff = func(attemptNum int, resp *nethttp.Response) time.Duration {
+			backoff = func(attemptNum int, resp *nethttp.Response) time.Duration {
 				return delayDuration * time.Duration(attemptNum)
 			}
 		}
 	}
 
+	// Subscribers can't hold the sender longer than defaultRetryWaitMax with
+	// the Retry-After header, unless the maximum delay is set.
+	maxRetryAfter := maxDelay
+	if maxRetryAfter == 0 {
+		maxRetryAfter = defaultRetryWaitMax
+	}
+	retryConfig.Backoff = func(attemptNum int, resp *nethttp.Response) time.Duration {
+		if d, ok := retryAfter(resp, time.Now()); ok {
+			return capDelay(d, maxRetryAfter)
+		}
+		return capDelay(backoff(attemptNum, resp), maxDelay)
+	}
+
 	return retryConfig, nil
 }
 
-func checkRetry(_ context.Context, resp *nethttp.Response, err error) (bool, error) {
-	return !(resp != nil && resp.StatusCode < 300), err
+// statusCodeMatcher returns a function matching the status codes either
+// listed in codes, e.g. 429, or belonging to a class listed in codes, e.g. 5xx.
+func statusCodeMatcher(codes []string) (func(statusCode int) bool, error) {
+	classes := make(map[int]bool)
+	statusCodes := make(map[int]bool)
+	for _, code := range codes {
+		if len(code) == 3 && strings.HasSuffix(code, "xx") {
+			class, err := strconv.Atoi(code[:1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid status code class %q", code)
+			}
+			classes[class] = true
+			continue
+		}
+		statusCode, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", code)
+		}
+		statusCodes[statusCode] = true
+	}
+	return func(statusCode int) bool {
+		return statusCodes[statusCode] || classes[statusCode/100]
+	}, nil
+}
+
+// retryAfter returns the delay requested by the Retry-After header of a 429 or
+// 503 response, expressed either in seconds or as an HTTP date.
+func retryAfter(resp *nethttp.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != nethttp.StatusTooManyRequests && resp.StatusCode != nethttp.StatusServiceUnavailable) {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := nethttp.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := date.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// capDelay caps the delay to maxDelay, unless maxDelay is 0. Delays which
+// overflowed are capped too.
+func capDelay(delay, maxDelay time.Duration) time.Duration {
+	if maxDelay > 0 && (delay > maxDelay || delay < 0) {
+		return maxDelay
+	}
+	return delay
 }
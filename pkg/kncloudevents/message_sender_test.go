@@ -18,6 +18,7 @@ package kncloudevents
 
 import (
 	"context"
+	"errors"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -114,6 +115,85 @@ func TestRetryConfigFromDeliverySpec(t *testing.T) {
 	}
 }
 
+func TestRetryConfigFromDeliverySpecJitter(t *testing.T) {
+	policy := duckv1.BackoffPolicyExponentialJitter
+	retryConfig, err := RetryConfigFromDeliverySpec(duckv1.DeliverySpec{
+		Retry:           ptr.Int32(5),
+		BackoffPolicy:   &policy,
+		BackoffDelay:    pointer.StringPtr("PT1S"),
+		BackoffMaxDelay: pointer.StringPtr("PT10S"),
+	})
+	assert.Nil(t, err)
+
+	for i, max := range []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		for j := 0; j < 100; j++ {
+			if got := retryConfig.Backoff(i+1, nil); got < max/2 || got > max {
+				t.Fatalf("Backoff(%d) = %v, want between %v and %v", i+1, got, max/2, max)
+			}
+		}
+	}
+}
+
+func TestRetryConfigFromDeliverySpecRetryAfter(t *testing.T) {
+	linear := duckv1.BackoffPolicyLinear
+	retryConfig, err := RetryConfigFromDeliverySpec(duckv1.DeliverySpec{
+		Retry:           ptr.Int32(5),
+		BackoffPolicy:   &linear,
+		BackoffDelay:    pointer.StringPtr("PT1S"),
+		BackoffMaxDelay: pointer.StringPtr("PT1M"),
+	})
+	assert.Nil(t, err)
+
+	response := func(statusCode int, retryAfter string) *http.Response {
+		return &http.Response{StatusCode: statusCode, Header: http.Header{"Retry-After": []string{retryAfter}}}
+	}
+	tests := []struct {
+		name string
+		resp *http.Response
+		want time.Duration
+	}{{
+		name: "seconds",
+		resp: response(http.StatusTooManyRequests, "30"),
+		want: 30 * time.Second,
+	}, {
+		name: "seconds capped",
+		resp: response(http.StatusServiceUnavailable, "3600"),
+		want: time.Minute,
+	}, {
+		name: "past date",
+		resp: response(http.StatusServiceUnavailable, "Wed, 21 Oct 2015 07:28:00 GMT"),
+		want: 0,
+	}, {
+		name: "invalid",
+		resp: response(http.StatusTooManyRequests, "soon"),
+		want: 2 * time.Second,
+	}, {
+		name: "ignored for other status codes",
+		resp: response(http.StatusInternalServerError, "30"),
+		want: 2 * time.Second,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, retryConfig.Backoff(2, tc.resp))
+		})
+	}
+
+	date := time.Now().Add(20 * time.Second).UTC().Format(http.TimeFormat)
+	if got := retryConfig.Backoff(2, response(http.StatusServiceUnavailable, date)); got <= 15*time.Second || got > 20*time.Second {
+		t.Errorf("Backoff() = %v, want about 20s", got)
+	}
+
+	// Without a maximum delay, the delay is capped to defaultRetryWaitMax.
+	retryConfig, err = RetryConfigFromDeliverySpec(duckv1.DeliverySpec{
+		Retry:         ptr.Int32(5),
+		BackoffPolicy: &linear,
+		BackoffDelay:  pointer.StringPtr("PT1S"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultRetryWaitMax, retryConfig.Backoff(2, response(http.StatusTooManyRequests, "86400")))
+	assert.Equal(t, 2*time.Second, retryConfig.Backoff(2, response(http.StatusTooManyRequests, "2")))
+}
+
 func TestHTTPMessageSenderSendWithRetries(t *testing.T) {
 	t.Parallel()
 
@@ -181,6 +261,146 @@ func TestHTTPMessageSenderSendWithRetries(t *testing.T) {
 	}
 }
 
+func TestRetryConfigFromDeliverySpecTimeout(t *testing.T) {
+	retryConfig, err := RetryConfigFromDeliverySpec(duckv1.DeliverySpec{
+		Timeout: pointer.StringPtr("PT2.5S"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2500*time.Millisecond, retryConfig.RequestTimeout)
+
+	_, err = RetryConfigFromDeliverySpec(duckv1.DeliverySpec{
+		Timeout: pointer.StringPtr("FOO"),
+	})
+	assert.NotNil(t, err)
+}
+
+func TestRetryConfigFromDeliverySpecMaxConcurrency(t *testing.T) {
+	retryConfig, err := RetryConfigFromDeliverySpec(duckv1.DeliverySpec{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, retryConfig.MaxConcurrency)
+
+	retryConfig, err = RetryConfigFromDeliverySpec(duckv1.DeliverySpec{
+		MaxConcurrency: pointer.Int32Ptr(5),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, retryConfig.MaxConcurrency)
+}
+
+func TestRetryConfigFromDeliverySpecRetryableStatusCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		codes      []string
+		retried    []int
+		notRetried []int
+	}{{
+		name:       "default",
+		retried:    []int{http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable},
+		notRetried: []int{http.StatusOK, http.StatusAccepted, http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict},
+	}, {
+		name:       "custom",
+		codes:      []string{"4xx", "503"},
+		retried:    []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+		notRetried: []int{http.StatusOK, http.StatusInternalServerError, http.StatusBadGateway},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			retryConfig, err := RetryConfigFromDeliverySpec(duckv1.DeliverySpec{
+				Retry:                ptr.Int32(3),
+				RetryableStatusCodes: tc.codes,
+			})
+			assert.Nil(t, err)
+
+			for _, code := range tc.retried {
+				retry, _ := retryConfig.CheckRetry(context.Background(), &http.Response{StatusCode: code}, nil)
+				assert.True(t, retry, "status code %d must be retried", code)
+			}
+			for _, code := range tc.notRetried {
+				retry, _ := retryConfig.CheckRetry(context.Background(), &http.Response{StatusCode: code}, nil)
+				assert.False(t, retry, "status code %d must not be retried", code)
+			}
+			retry, _ := retryConfig.CheckRetry(context.Background(), nil, errors.New("timeout"))
+			assert.True(t, retry, "requests failed without a response must be retried")
+		})
+	}
+
+	_, err := RetryConfigFromDeliverySpec(duckv1.DeliverySpec{
+		RetryableStatusCodes: []string{"5yy"},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestHTTPMessageSenderSendWithRetriesTimeout(t *testing.T) {
+	var n int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// The first attempt times out.
+		if atomic.AddInt32(&n, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := &HTTPMessageSender{
+		Client: http.DefaultClient,
+	}
+	config := &RetryConfig{
+		RetryMax:       1,
+		RequestTimeout: 50 * time.Millisecond,
+		CheckRetry:     defaultCheckRetry(t),
+		Backoff: func(attemptNum int, resp *http.Response) time.Duration {
+			return time.Millisecond
+		},
+	}
+
+	request, err := http.NewRequest("POST", server.URL, nil)
+	assert.Nil(t, err)
+	got, err := sender.SendWithRetries(request, config)
+	if err != nil {
+		t.Fatalf("SendWithRetries() error = %v, wantErr nil", err)
+	}
+	if got.StatusCode != http.StatusAccepted {
+		t.Fatalf("SendWithRetries() got = %v, want %v", got.StatusCode, http.StatusAccepted)
+	}
+	if count := int(atomic.LoadInt32(&n)); count != 2 {
+		t.Fatalf("expected 2 attempts got %d", count)
+	}
+}
+
+func TestHTTPMessageSenderSendWithRetriesAndAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := &HTTPMessageSender{
+		Client: http.DefaultClient,
+	}
+	config := &RetryConfig{
+		RetryMax:   2,
+		CheckRetry: defaultCheckRetry(t),
+		Backoff: func(attemptNum int, resp *http.Response) time.Duration {
+			return time.Millisecond
+		},
+	}
+
+	request, err := http.NewRequest("POST", server.URL, nil)
+	assert.Nil(t, err)
+	var attempts Attempts
+	got, err := sender.SendWithRetriesAndAttempts(request, config, &attempts)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, got.StatusCode)
+	assert.Equal(t, 3, attempts.Count)
+	assert.True(t, attempts.Last.After(attempts.First))
+
+	request, err = http.NewRequest("POST", server.URL, nil)
+	assert.Nil(t, err)
+	attempts = Attempts{}
+	_, err = sender.SendWithRetriesAndAttempts(request, nil, &attempts)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempts.Count)
+	assert.Equal(t, attempts.First, attempts.Last)
+}
+
 func TestRetriesOnNetworkErrors(t *testing.T) {
 
 	n := int32(10)
@@ -365,3 +585,13 @@ func TestRetryConfigFromDeliverySpecCheckRetry(t *testing.T) {
 		})
 	}
 }
+
+// defaultCheckRetry returns the retry policy of a DeliverySpec which doesn't
+// list the retryable status codes.
+func defaultCheckRetry(t *testing.T) CheckRetry {
+	config, err := RetryConfigFromDeliverySpec(duckv1.DeliverySpec{})
+	if err != nil {
+		t.Fatal("RetryConfigFromDeliverySpec() =", err)
+	}
+	return config.CheckRetry
+}
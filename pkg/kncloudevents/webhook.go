@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Headers of the abuse protection handshake of the CloudEvents HTTP webhook
+// specification.
+// See https://github.com/cloudevents/spec/blob/v1.0/http-webhook.md#4-abuse-protection
+const (
+	WebhookRequestOriginHeader = "WebHook-Request-Origin"
+	WebhookRequestRateHeader   = "WebHook-Request-Rate"
+	WebhookAllowedOriginHeader = "WebHook-Allowed-Origin"
+	WebhookAllowedRateHeader   = "WebHook-Allowed-Rate"
+
+	// webhookAnyRate is the value of the rate headers allowing any rate.
+	webhookAnyRate = "*"
+)
+
+const (
+	// WebhookValidationTTL is how long a successful handshake with a
+	// destination is cached before being performed again.
+	WebhookValidationTTL = time.Hour
+
+	// WebhookValidationFailureTTL is how long a failed handshake with a
+	// destination is cached. Deliveries to the destination fail meanwhile.
+	WebhookValidationFailureTTL = time.Minute
+)
+
+// IsWebhookValidationRequest returns whether the request is the validation
+// request of the abuse protection handshake.
+func IsWebhookValidationRequest(request *nethttp.Request) bool {
+	return request.Method == nethttp.MethodOptions && request.Header.Get(WebhookRequestOriginHeader) != ""
+}
+
+// WriteWebhookValidationResponse grants the origin of the validation request
+// the permission to send events at any rate.
+func WriteWebhookValidationResponse(writer nethttp.ResponseWriter, request *nethttp.Request) {
+	writer.Header().Set("Allow", nethttp.MethodPost)
+	writer.Header().Set(WebhookAllowedOriginHeader, request.Header.Get(WebhookRequestOriginHeader))
+	writer.Header().Set(WebhookAllowedRateHeader, webhookAnyRate)
+	writer.WriteHeader(nethttp.StatusOK)
+}
+
+// WebhookValidator performs the abuse protection handshake with the
+// destinations before sending them events, and limits the rate of the events
+// sent to each destination to the rate it allows.
+type WebhookValidator struct {
+	client *nethttp.Client
+	origin string
+
+	mu          sync.Mutex
+	validations map[string]*webhookValidation
+	lastPrune   time.Time
+}
+
+// webhookValidation is the result of the handshake with a destination.
+type webhookValidation struct {
+	// done is closed once the handshake completed, the other fields must
+	// not be read before.
+	done chan struct{}
+
+	// limiter limits the rate of the events sent to the destination, nil if
+	// the rate is not limited.
+	limiter *rate.Limiter
+	err     error
+	expires time.Time
+	// cancelled is set if the context of the handshake was done, in which
+	// case its result is not cached.
+	cancelled bool
+}
+
+// completed reports whether the handshake completed, without waiting.
+func (w *webhookValidation) completed() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewWebhookValidator creates a WebhookValidator sending validation requests
+// with the client, on behalf of the origin, e.g. the host name of the sender.
+func NewWebhookValidator(client *nethttp.Client, origin string) *WebhookValidator {
+	return &WebhookValidator{
+		client:      client,
+		origin:      origin,
+		validations: make(map[string]*webhookValidation),
+	}
+}
+
+// Allow performs the handshake with the target if its result isn't cached
+// already, and returns an error if the target doesn't allow the origin to
+// send it events. It waits for the rate allowed by the target otherwise.
+func (v *WebhookValidator) Allow(ctx context.Context, target string) error {
+	validation, err := v.validation(ctx, target)
+	if err != nil {
+		return err
+	}
+	if validation.err != nil {
+		return validation.err
+	}
+	if validation.limiter != nil {
+		return validation.limiter.Wait(ctx)
+	}
+	return nil
+}
+
+// validation returns the completed handshake with the target. It performs the
+// handshake if its result isn't cached, or waits for the one in progress.
+func (v *WebhookValidator) validation(ctx context.Context, target string) (*webhookValidation, error) {
+	for {
+		now := time.Now()
+
+		v.mu.Lock()
+		v.prune(now)
+		validation, ok := v.validations[target]
+		if !ok || (validation.completed() && now.After(validation.expires)) {
+			validation = &webhookValidation{done: make(chan struct{})}
+			v.validations[target] = validation
+			v.mu.Unlock()
+
+			v.validate(ctx, target, validation, now)
+			return validation, nil
+		}
+		v.mu.Unlock()
+
+		if !validation.completed() {
+			select {
+			case <-validation.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if !validation.cancelled {
+			return validation, nil
+		}
+		// The context of the caller which performed the handshake was done,
+		// perform it again.
+	}
+}
+
+// validate performs the handshake and records its result in validation.
+func (v *WebhookValidator) validate(ctx context.Context, target string, validation *webhookValidation, now time.Time) {
+	defer close(validation.done)
+
+	limiter, err := v.handshake(ctx, target)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// The handshake was interrupted, it says nothing about the target.
+		validation.err = fmt.Errorf("webhook validation of %s failed: %w", target, ctx.Err())
+		validation.cancelled = true
+		v.mu.Lock()
+		if v.validations[target] == validation {
+			delete(v.validations, target)
+		}
+		v.mu.Unlock()
+	case err != nil:
+		validation.err = fmt.Errorf("webhook validation of %s failed: %w", target, err)
+		validation.expires = now.Add(WebhookValidationFailureTTL)
+	default:
+		validation.limiter = limiter
+		validation.expires = now.Add(WebhookValidationTTL)
+	}
+}
+
+// prune drops the expired validations, at most once per
+// WebhookValidationFailureTTL. It must be called with the lock held.
+func (v *WebhookValidator) prune(now time.Time) {
+	if now.Sub(v.lastPrune) < WebhookValidationFailureTTL {
+		return
+	}
+	v.lastPrune = now
+	for target, validation := range v.validations {
+		if validation.completed() && now.After(validation.expires) {
+			delete(v.validations, target)
+		}
+	}
+}
+
+func (v *WebhookValidator) handshake(ctx context.Context, target string) (*rate.Limiter, error) {
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodOptions, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(WebhookRequestOriginHeader, v.origin)
+	req.Header.Set(WebhookRequestRateHeader, webhookAnyRate)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < nethttp.StatusOK || resp.StatusCode >= nethttp.StatusMultipleChoices {
+		return nil, fmt.Errorf("unexpected HTTP response, expected 2xx, got %d", resp.StatusCode)
+	}
+	if allowed := resp.Header.Get(WebhookAllowedOriginHeader); allowed != v.origin && allowed != "*" {
+		return nil, fmt.Errorf("origin %q not allowed", v.origin)
+	}
+	return parseWebhookAllowedRate(resp.Header.Get(WebhookAllowedRateHeader))
+}
+
+// parseWebhookAllowedRate returns a limiter of the number of requests per
+// minute allowed by the WebHook-Allowed-Rate header, or nil if any rate is
+// allowed.
+func parseWebhookAllowedRate(value string) (*rate.Limiter, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == webhookAnyRate {
+		return nil, nil
+	}
+	perMinute, err := strconv.Atoi(value)
+	if err != nil || perMinute <= 0 {
+		return nil, fmt.Errorf("invalid %s %q", WebhookAllowedRateHeader, value)
+	}
+	return rate.NewLimiter(rate.Limit(float64(perMinute)/60), 1), nil
+}
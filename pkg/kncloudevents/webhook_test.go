@@ -0,0 +1,215 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kncloudevents
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWriteWebhookValidationResponse(t *testing.T) {
+	request := httptest.NewRequest(nethttp.MethodOptions, "/", nil)
+	if IsWebhookValidationRequest(request) {
+		t.Error("Expected an OPTIONS request without origin not to be a validation request")
+	}
+	request.Header.Set(WebhookRequestOriginHeader, "eventemitter.example.com")
+	if !IsWebhookValidationRequest(request) {
+		t.Error("Expected an OPTIONS request with an origin to be a validation request")
+	}
+
+	recorder := httptest.NewRecorder()
+	WriteWebhookValidationResponse(recorder, request)
+	result := recorder.Result()
+	if result.StatusCode != nethttp.StatusOK {
+		t.Errorf("Expected status code 200, got %d", result.StatusCode)
+	}
+	if got := result.Header.Get(WebhookAllowedOriginHeader); got != "eventemitter.example.com" {
+		t.Errorf("Unexpected %s %q", WebhookAllowedOriginHeader, got)
+	}
+	if got := result.Header.Get(WebhookAllowedRateHeader); got != "*" {
+		t.Errorf("Unexpected %s %q", WebhookAllowedRateHeader, got)
+	}
+}
+
+func TestWebhookValidator(t *testing.T) {
+	tests := map[string]struct {
+		statusCode    int
+		allowedOrigin string
+		allowedRate   string
+		wantErr       bool
+		wantLimited   bool
+	}{
+		"allowed": {
+			statusCode:    nethttp.StatusOK,
+			allowedOrigin: "origin",
+			allowedRate:   "*",
+		},
+		"any origin allowed": {
+			statusCode:    nethttp.StatusOK,
+			allowedOrigin: "*",
+		},
+		"rate limited": {
+			statusCode:    nethttp.StatusOK,
+			allowedOrigin: "origin",
+			allowedRate:   "60",
+			wantLimited:   true,
+		},
+		"invalid rate": {
+			statusCode:    nethttp.StatusOK,
+			allowedOrigin: "origin",
+			allowedRate:   "-1",
+			wantErr:       true,
+		},
+		"origin not allowed": {
+			statusCode:    nethttp.StatusOK,
+			allowedOrigin: "other",
+			wantErr:       true,
+		},
+		"handshake not supported": {
+			statusCode: nethttp.StatusMethodNotAllowed,
+			wantErr:    true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var handshakes int32
+			server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				if r.Method != nethttp.MethodOptions || r.Header.Get(WebhookRequestOriginHeader) != "origin" {
+					t.Errorf("Unexpected validation request %s %v", r.Method, r.Header)
+				}
+				atomic.AddInt32(&handshakes, 1)
+				if tc.allowedOrigin != "" {
+					w.Header().Set(WebhookAllowedOriginHeader, tc.allowedOrigin)
+				}
+				if tc.allowedRate != "" {
+					w.Header().Set(WebhookAllowedRateHeader, tc.allowedRate)
+				}
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			v := NewWebhookValidator(server.Client(), "origin")
+			err := v.Allow(context.Background(), server.URL)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Allow() = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			// The result of the handshake is cached. The context is
+			// cancelled so that waiting for the allowed rate fails.
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err = v.Allow(ctx, server.URL)
+			if tc.wantLimited {
+				if err == nil {
+					t.Error("Expected the second event to wait for the allowed rate")
+				}
+			} else if (err != nil) != tc.wantErr {
+				t.Errorf("Allow() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if n := atomic.LoadInt32(&handshakes); n != 1 {
+				t.Errorf("Expected a single handshake, got %d", n)
+			}
+		})
+	}
+}
+
+func TestWebhookValidator_ConcurrentHandshakes(t *testing.T) {
+	var handshakes int32
+	release := make(chan struct{})
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		atomic.AddInt32(&handshakes, 1)
+		<-release
+		w.Header().Set(WebhookAllowedOriginHeader, "origin")
+		w.WriteHeader(nethttp.StatusOK)
+	}))
+	defer server.Close()
+
+	v := NewWebhookValidator(server.Client(), "origin")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := v.Allow(context.Background(), server.URL); err != nil {
+				t.Error("Allow() =", err)
+			}
+		}()
+	}
+	// Let the callers pile up on the handshake in progress.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&handshakes); n != 1 {
+		t.Errorf("Expected the concurrent callers to share a single handshake, got %d", n)
+	}
+}
+
+func TestWebhookValidator_CancelledHandshake(t *testing.T) {
+	var handshakes int32
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if atomic.AddInt32(&handshakes, 1) == 1 {
+			// The first handshake outlives the context of the caller.
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set(WebhookAllowedOriginHeader, "origin")
+		w.WriteHeader(nethttp.StatusOK)
+	}))
+	defer server.Close()
+
+	v := NewWebhookValidator(server.Client(), "origin")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := v.Allow(ctx, server.URL); err == nil {
+		t.Fatal("Expected Allow() to fail once the context is done")
+	}
+
+	// The interrupted handshake isn't cached as a failure.
+	if err := v.Allow(context.Background(), server.URL); err != nil {
+		t.Error("Allow() =", err)
+	}
+	if n := atomic.LoadInt32(&handshakes); n != 2 {
+		t.Errorf("Expected the handshake to be performed again, got %d handshakes", n)
+	}
+}
+
+func TestWebhookValidator_Prune(t *testing.T) {
+	v := NewWebhookValidator(nethttp.DefaultClient, "origin")
+	now := time.Now()
+	done := make(chan struct{})
+	close(done)
+	v.validations["expired"] = &webhookValidation{done: done, expires: now.Add(-time.Second)}
+	v.validations["valid"] = &webhookValidation{done: done, expires: now.Add(time.Hour)}
+	v.validations["in progress"] = &webhookValidation{done: make(chan struct{})}
+
+	v.mu.Lock()
+	v.prune(now)
+	v.mu.Unlock()
+
+	if _, ok := v.validations["expired"]; ok {
+		t.Error("Expected the expired validation to be pruned")
+	}
+	if len(v.validations) != 2 {
+		t.Errorf("Expected the valid and in progress validations to be kept, got %d validations", len(v.validations))
+	}
+}
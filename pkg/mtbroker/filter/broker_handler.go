@@ -0,0 +1,266 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/data"
+	"knative.dev/eventing/pkg/kncloudevents"
+	broker "knative.dev/eventing/pkg/mtbroker"
+	"knative.dev/eventing/pkg/reconciler/sugar/trigger/path"
+	"knative.dev/eventing/pkg/tracing"
+	"knative.dev/eventing/pkg/utils"
+)
+
+// serveBroker handles an event sent to all the Triggers of a Broker at once, as done by the
+// single Subscription of a Broker in the broker dispatch mode.
+//
+// The event is extracted from the request once, the filter of every Trigger of the Broker is
+// applied to it and the event is sent to the subscribers of the matching Triggers in parallel.
+// As the channel only accepts a single reply, the replies of the subscribers are sent to the
+// Broker's ingress directly.
+//
+// If any of the dispatches fails, the request fails with the status of the first failure, so
+// that the channel retries it. The Triggers that got the event already are remembered, so that
+// the retry only sends it to the ones that failed.
+func (h *Handler) serveBroker(writer http.ResponseWriter, request *http.Request) {
+	brokerRef, err := path.ParseBroker(request.RequestURI)
+	if err != nil {
+		h.logger.Info("Unable to parse path as broker", zap.Error(err), zap.String("path", request.RequestURI))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := request.Context()
+
+	message := cehttp.NewMessageFromHttpRequest(request)
+	defer message.Finish(nil)
+
+	event, err := binding.ToEvent(ctx, message)
+	if err != nil {
+		h.logger.Warn("failed to extract event from request", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx, span := trace.StartSpan(ctx, tracing.BrokerMessagingDestination(brokerRef))
+	defer span.End()
+
+	if span.IsRecordingEvents() {
+		span.AddAttributes(
+			tracing.MessagingSystemAttribute,
+			tracing.MessagingProtocolHTTP,
+			tracing.BrokerMessagingDestinationAttribute(brokerRef),
+			tracing.MessagingMessageIDAttribute(event.ID()),
+		)
+		span.AddAttributes(client.EventTraceAttributes(event)...)
+	}
+
+	// Remove the TTL attribute that is used by the Broker.
+	ttl, err := broker.GetTTL(event.Context)
+	if err != nil {
+		// Only messages sent by the Broker should be here. If the attribute isn't here, then the
+		// event wasn't sent by the Broker, so we can drop it.
+		h.logger.Warn("No TTL seen, dropping", zap.Any("brokerRef", brokerRef), zap.Any("event", event))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := broker.DeleteTTL(event.Context); err != nil {
+		h.logger.Warn("Failed to delete TTL.", zap.Error(err))
+	}
+	hops := h.extractHops(event)
+
+	h.logger.Debug("Received message", zap.Any("brokerRef", brokerRef))
+
+	triggers, err := h.getBrokerTriggers(brokerRef)
+	if err != nil {
+		h.logger.Info("Unable to list the Triggers", zap.Error(err), zap.Any("brokerRef", brokerRef))
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ctx = logging.WithLogger(ctx, h.logger.Sugar())
+
+	statusCodes := make([]int, len(triggers))
+	var wg sync.WaitGroup
+	for i, t := range triggers {
+		wg.Add(1)
+		go func(i int, t *eventingv1beta1.Trigger) {
+			defer wg.Done()
+			key := deliveredKey{trigger: t.UID, source: event.Source(), id: event.ID()}
+			if h.delivered.delivered(key, time.Now()) {
+				return
+			}
+			statusCodes[i] = h.dispatch(ctx, request.Header, t, event, ttl, hops)
+			if isSuccess(statusCodes[i]) {
+				h.delivered.add(key, time.Now())
+			}
+		}(i, t)
+	}
+	wg.Wait()
+
+	writer.WriteHeader(brokerStatusCode(statusCodes))
+}
+
+// dispatch applies the filter of the Trigger 't' to the event and, if it passes, sends the event
+// to the Trigger's subscriber. It returns the status code of the dispatch, or 0 if the event
+// wasn't sent.
+func (h *Handler) dispatch(ctx context.Context, headers http.Header, t *eventingv1beta1.Trigger, event *cloudevents.Event, ttl int32, hops []string) int {
+	reportArgs := &ReportArgs{
+		ns:         t.Namespace,
+		trigger:    t.Name,
+		broker:     t.Spec.Broker,
+		filterType: triggerFilterAttribute(t.Spec.Filter, "type"),
+	}
+
+	subscriberURI := t.Status.SubscriberURI
+	if subscriberURI == nil {
+		// The Trigger isn't ready yet, which must not fail the delivery to the other Triggers.
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusBadRequest)
+		return 0
+	}
+
+	ctx = data.WithParseErrorReporter(ctx, func(error) {
+		_ = h.reporter.ReportDataParseFailure(reportArgs)
+	})
+	if h.filterEvent(ctx, t, *event, reportArgs) == eventfilter.FailFilter {
+		return 0
+	}
+
+	h.reportArrivalTime(event, reportArgs)
+
+	target := subscriberURI.String()
+	response, err := h.sendEvent(ctx, headers, target, t, event, reportArgs)
+	if err != nil {
+		h.logger.Error("failed to send event", zap.Error(err))
+		_ = h.reporter.ReportEventCount(reportArgs, http.StatusInternalServerError)
+		return http.StatusInternalServerError
+	}
+
+	h.logger.Debug("Successfully dispatched message", zap.Any("target", target))
+
+	statusCode, err := h.forwardResponse(ctx, headers, t, response, ttl, hops, target)
+	if err != nil {
+		h.logger.Error("failed to forward response", zap.Error(err))
+	}
+	_ = h.reporter.ReportEventCount(reportArgs, statusCode)
+	return statusCode
+}
+
+// forwardResponse sends the event in the subscriber's response, if any, to the ingress of the
+// Trigger's Broker.
+func (h *Handler) forwardResponse(ctx context.Context, headers http.Header, t *eventingv1beta1.Trigger, resp *http.Response, ttl int32, hops []string, target string) (int, error) {
+	response := cehttp.NewMessageFromHttpResponse(resp)
+	defer response.Finish(nil)
+
+	if response.ReadEncoding() == binding.EncodingUnknown {
+		// See writeResponse, a non-empty response that is not a CloudEvent is a delivery failure.
+		body := make([]byte, 1)
+		n, _ := response.BodyReader.Read(body)
+		response.BodyReader.Close()
+		if n != 0 {
+			return http.StatusBadGateway, errors.New("received a non-empty response not recognized as CloudEvent. The response MUST be or empty or a valid CloudEvent")
+		}
+		return resp.StatusCode, nil
+	}
+
+	event, err := binding.ToEvent(ctx, response)
+	if err != nil {
+		return http.StatusBadGateway, err
+	}
+
+	if err := h.prepareReply(event, t, ttl, hops); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	ingress := fmt.Sprintf("http://%s/%s/%s", h.brokerIngressHost, t.Namespace, t.Spec.Broker)
+	req, err := h.sender.NewCloudEventRequestWithTarget(ctx, ingress)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to create the reply request: %w", err)
+	}
+
+	message := binding.ToMessage(event)
+	defer message.Finish(nil)
+
+	if err := kncloudevents.WriteHTTPRequestWithAdditionalHeaders(ctx, message, req, utils.PassThroughHeaders(headers)); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to write reply request: %w", err)
+	}
+
+	ingressResp, err := h.sender.Send(req)
+	if err != nil {
+		return http.StatusBadGateway, fmt.Errorf("failed to send reply to %s: %w", ingress, err)
+	}
+	ingressResp.Body.Close()
+	if ingressResp.StatusCode < http.StatusOK || ingressResp.StatusCode >= http.StatusMultipleChoices {
+		return http.StatusBadGateway, fmt.Errorf("reply rejected by %s with status %d", ingress, ingressResp.StatusCode)
+	}
+
+	h.logger.Debug("Sent the reply to the Broker ingress", zap.Any("target", target))
+
+	return resp.StatusCode, nil
+}
+
+// getBrokerTriggers returns the Triggers of the referenced Broker. The Triggers that set their
+// own delivery are left out, as the Trigger reconciler rejects them in the broker dispatch mode.
+func (h *Handler) getBrokerTriggers(ref types.NamespacedName) ([]*eventingv1beta1.Trigger, error) {
+	selector := labels.SelectorFromSet(map[string]string{eventing.BrokerLabelKey: ref.Name})
+	triggers, err := h.triggerLister.Triggers(ref.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	brokerTriggers := make([]*eventingv1beta1.Trigger, 0, len(triggers))
+	for _, t := range triggers {
+		if t.Spec.Broker == ref.Name && t.Spec.Delivery == nil {
+			brokerTriggers = append(brokerTriggers, t)
+		}
+	}
+	return brokerTriggers, nil
+}
+
+// brokerStatusCode returns the status code of the first failed dispatch, if any.
+func brokerStatusCode(statusCodes []int) int {
+	for _, sc := range statusCodes {
+		if sc != 0 && !isSuccess(sc) {
+			return sc
+		}
+	}
+	return http.StatusAccepted
+}
+
+// isSuccess returns whether the status code is a 2xx.
+func isSuccess(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	_ "knative.dev/pkg/system/testing"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	broker "knative.dev/eventing/pkg/mtbroker"
+	reconcilertesting "knative.dev/eventing/pkg/reconciler/testing"
+)
+
+const (
+	brokerName = "test-broker"
+)
+
+var (
+	validBrokerPath = fmt.Sprintf("/brokers/%s/%s", testNS, brokerName)
+)
+
+func TestBrokerReceiver(t *testing.T) {
+	testCases := map[string]struct {
+		triggers           []*eventingv1beta1.Trigger
+		request            *http.Request
+		event              *cloudevents.Event
+		subscriberStatus   int
+		returnedEvent      *cloudevents.Event
+		ingressStatus      int
+		expectedStatus     int
+		expectedDispatches int32
+		expectedReplies    int32
+	}{
+		"Wrong path": {
+			request:        httptest.NewRequest(http.MethodPost, "/brokers/test-namespace/test-broker/extra", nil),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Bad request": {
+			request:        httptest.NewRequest(http.MethodPost, validBrokerPath, nil),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"No TTL": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeBrokerTrigger("t1", makeTriggerFilterWithAttributes("", "")),
+			},
+			event:          makeEventWithoutTTL(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"No Triggers": {
+			expectedStatus: http.StatusAccepted,
+		},
+		"Dispatch to the matching Triggers": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeBrokerTrigger("t1", makeTriggerFilterWithAttributes("", "")),
+				makeBrokerTrigger("t2", makeTriggerFilterWithAttributes(eventType, eventSource)),
+				makeBrokerTrigger("t3", makeTriggerFilterWithAttributes("some-other-type", "")),
+				makeBrokerTriggerWithoutSubscriberURI("t4"),
+				makeOtherBrokerTrigger("t5"),
+				makeUnlabelledBrokerTrigger("t6"),
+				makeBrokerTriggerWithDelivery("t7"),
+			},
+			expectedStatus:     http.StatusAccepted,
+			expectedDispatches: 2,
+		},
+		"Subscriber failure": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeBrokerTrigger("t1", makeTriggerFilterWithAttributes("", "")),
+				makeBrokerTrigger("t2", makeTriggerFilterWithAttributes("", "")),
+			},
+			subscriberStatus:   http.StatusServiceUnavailable,
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedDispatches: 2,
+		},
+		"Replies sent to the ingress": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeBrokerTrigger("t1", makeTriggerFilterWithAttributes("", "")),
+				makeBrokerTrigger("t2", makeTriggerFilterWithAttributes("", "")),
+			},
+			returnedEvent:      makeDifferentEvent(),
+			expectedStatus:     http.StatusAccepted,
+			expectedDispatches: 2,
+			expectedReplies:    2,
+		},
+		"Reply rejected by the ingress": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeBrokerTrigger("t1", makeTriggerFilterWithAttributes("", "")),
+			},
+			returnedEvent:      makeDifferentEvent(),
+			ingressStatus:      http.StatusInternalServerError,
+			expectedStatus:     http.StatusBadGateway,
+			expectedDispatches: 1,
+			expectedReplies:    1,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			dispatches := atomic.NewInt32(0)
+			subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				dispatches.Inc()
+				if tc.subscriberStatus != 0 {
+					w.WriteHeader(tc.subscriberStatus)
+					return
+				}
+				if tc.returnedEvent == nil {
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+				message := binding.ToMessage(tc.returnedEvent)
+				defer message.Finish(nil)
+				if err := cehttp.WriteResponseWriter(context.Background(), message, http.StatusOK, w); err != nil {
+					t.Error("Unable to write the response:", err)
+				}
+			}))
+			defer subscriber.Close()
+
+			replies := atomic.NewInt32(0)
+			ingress := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				replies.Inc()
+				if want := fmt.Sprintf("/%s/%s", testNS, brokerName); r.URL.Path != want {
+					t.Errorf("Unexpected ingress path. Expected %q. Actual %q.", want, r.URL.Path)
+				}
+				e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+				if err != nil {
+					t.Error("Unable to read the reply:", err)
+				} else if _, err := broker.GetTTL(e.Context); err != nil {
+					t.Error("The reply has no TTL:", err)
+				}
+				if tc.ingressStatus != 0 {
+					w.WriteHeader(tc.ingressStatus)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer ingress.Close()
+
+			subscriberURL, err := apis.ParseURL(subscriber.URL)
+			if err != nil {
+				t.Fatalf("Failed to parse URL %q : %s", subscriber.URL, err)
+			}
+			objs := make([]runtime.Object, 0, len(tc.triggers))
+			for _, trig := range tc.triggers {
+				if trig.Status.SubscriberURI != nil {
+					trig.Status.SubscriberURI = subscriberURL
+				}
+				objs = append(objs, trig)
+			}
+			listers := reconcilertesting.NewListers(objs)
+			r, err := NewHandler(
+				zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller())),
+				listers.GetV1Beta1TriggerLister(),
+				&mockReporter{},
+				8080)
+			if err != nil {
+				t.Fatal("Unable to create receiver:", err)
+			}
+			ingressURL, err := url.Parse(ingress.URL)
+			if err != nil {
+				t.Fatalf("Failed to parse URL %q : %s", ingress.URL, err)
+			}
+			r.brokerIngressHost = ingressURL.Host
+
+			if tc.request == nil {
+				e := tc.event
+				if e == nil {
+					e = makeEvent()
+				}
+				b, err := e.MarshalJSON()
+				if err != nil {
+					t.Fatal(err)
+				}
+				tc.request = httptest.NewRequest(http.MethodPost, validBrokerPath, bytes.NewBuffer(b))
+				tc.request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+			}
+			responseWriter := httptest.NewRecorder()
+			r.ServeHTTP(responseWriter, tc.request)
+
+			if got := responseWriter.Result().StatusCode; got != tc.expectedStatus {
+				t.Errorf("Unexpected status. Expected %v. Actual %v.", tc.expectedStatus, got)
+			}
+			if got := dispatches.Load(); got != tc.expectedDispatches {
+				t.Errorf("Unexpected dispatches. Expected %v. Actual %v.", tc.expectedDispatches, got)
+			}
+			if got := replies.Load(); got != tc.expectedReplies {
+				t.Errorf("Unexpected replies. Expected %v. Actual %v.", tc.expectedReplies, got)
+			}
+		})
+	}
+}
+
+func TestBrokerReceiverRetry(t *testing.T) {
+	failing := atomic.NewBool(true)
+	dispatches := map[string]*atomic.Int32{
+		"/t1": atomic.NewInt32(0),
+		"/t2": atomic.NewInt32(0),
+	}
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dispatches[r.URL.Path].Inc()
+		if r.URL.Path == "/t2" && failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer subscriber.Close()
+
+	objs := make([]runtime.Object, 0, 2)
+	for _, name := range []string{"t1", "t2"} {
+		trig := makeBrokerTrigger(name, makeTriggerFilterWithAttributes("", ""))
+		uri, err := apis.ParseURL(subscriber.URL + "/" + name)
+		if err != nil {
+			t.Fatalf("Failed to parse URL %q : %s", subscriber.URL, err)
+		}
+		trig.Status.SubscriberURI = uri
+		objs = append(objs, trig)
+	}
+	listers := reconcilertesting.NewListers(objs)
+	r, err := NewHandler(
+		zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller())),
+		listers.GetV1Beta1TriggerLister(),
+		&mockReporter{},
+		8080)
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+
+	send := func() int {
+		b, err := makeEvent().MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		request := httptest.NewRequest(http.MethodPost, validBrokerPath, bytes.NewBuffer(b))
+		request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		responseWriter := httptest.NewRecorder()
+		r.ServeHTTP(responseWriter, request)
+		return responseWriter.Result().StatusCode
+	}
+
+	if got := send(); got != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected status. Expected %v. Actual %v.", http.StatusServiceUnavailable, got)
+	}
+	failing.Store(false)
+	// The retry is only sent to the Trigger that failed.
+	if got := send(); got != http.StatusAccepted {
+		t.Errorf("Unexpected status. Expected %v. Actual %v.", http.StatusAccepted, got)
+	}
+	if got := dispatches["/t1"].Load(); got != 1 {
+		t.Errorf("Unexpected dispatches to t1. Expected 1. Actual %v.", got)
+	}
+	if got := dispatches["/t2"].Load(); got != 2 {
+		t.Errorf("Unexpected dispatches to t2. Expected 2. Actual %v.", got)
+	}
+}
+
+func makeBrokerTrigger(name string, filter *eventingv1beta1.TriggerFilter) *eventingv1beta1.Trigger {
+	t := makeTrigger(filter)
+	t.Name = name
+	t.UID = types.UID(name + "-uid")
+	t.Labels = map[string]string{eventing.BrokerLabelKey: brokerName}
+	t.Spec.Broker = brokerName
+	return t
+}
+
+func makeUnlabelledBrokerTrigger(name string) *eventingv1beta1.Trigger {
+	t := makeBrokerTrigger(name, nil)
+	t.Labels = nil
+	return t
+}
+
+func makeBrokerTriggerWithDelivery(name string) *eventingv1beta1.Trigger {
+	t := makeBrokerTrigger(name, nil)
+	t.Spec.Delivery = &eventingduckv1.DeliverySpec{}
+	return t
+}
+
+func makeBrokerTriggerWithoutSubscriberURI(name string) *eventingv1beta1.Trigger {
+	t := makeBrokerTrigger(name, nil)
+	t.Status = eventingv1beta1.TriggerStatus{}
+	return t
+}
+
+func makeOtherBrokerTrigger(name string) *eventingv1beta1.Trigger {
+	t := makeBrokerTrigger(name, nil)
+	t.Labels = map[string]string{eventing.BrokerLabelKey: "some-other-broker"}
+	t.Spec.Broker = "some-other-broker"
+	return t
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultDeliveredCapacity is the number of deliveries remembered by the deliveredCache.
+	defaultDeliveredCapacity = 10000
+	// defaultDeliveredTTL is how long the deliveredCache remembers a delivery, it should
+	// cover the retries of the Broker's delivery spec.
+	defaultDeliveredTTL = 10 * time.Minute
+)
+
+// deliveredCache remembers the Triggers to which an event was delivered in the broker
+// dispatch mode, so that the retries of the event, caused by the failure of other Triggers,
+// skip them. It is bounded both in size and in time, and it is local to the replica, so a
+// retry handled by another replica may still deliver the event again.
+type deliveredCache struct {
+	capacity int
+	ttl      time.Duration
+
+	mu sync.Mutex
+	// entries holds the *deliveredEntry, the most recent first.
+	entries  *list.List
+	elements map[deliveredKey]*list.Element
+}
+
+// deliveredKey identifies the delivery of an event to a Trigger.
+type deliveredKey struct {
+	trigger types.UID
+	source  string
+	id      string
+}
+
+type deliveredEntry struct {
+	key       deliveredKey
+	delivered time.Time
+}
+
+func newDeliveredCache(capacity int, ttl time.Duration) *deliveredCache {
+	return &deliveredCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  list.New(),
+		elements: make(map[deliveredKey]*list.Element),
+	}
+}
+
+// delivered returns whether the event was delivered to the Trigger in the last ttl.
+func (c *deliveredCache) delivered(key deliveredKey, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(now)
+	_, ok := c.elements[key]
+	return ok
+}
+
+// add records the delivery of the event to the Trigger.
+func (c *deliveredCache) add(key deliveredKey, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.elements[key]; ok {
+		c.remove(e)
+	}
+	c.elements[key] = c.entries.PushFront(&deliveredEntry{key: key, delivered: now})
+	if c.entries.Len() > c.capacity {
+		c.remove(c.entries.Back())
+	}
+}
+
+// expire removes the entries older than ttl. It must be called with mu held.
+func (c *deliveredCache) expire(now time.Time) {
+	for e := c.entries.Back(); e != nil && now.Sub(e.Value.(*deliveredEntry).delivered) >= c.ttl; e = c.entries.Back() {
+		c.remove(e)
+	}
+}
+
+func (c *deliveredCache) remove(e *list.Element) {
+	c.entries.Remove(e)
+	delete(c.elements, e.Value.(*deliveredEntry).key)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveredCache(t *testing.T) {
+	c := newDeliveredCache(2, time.Minute)
+	now := time.Now()
+	k1 := deliveredKey{trigger: "t1", source: "source", id: "1"}
+	k2 := deliveredKey{trigger: "t2", source: "source", id: "1"}
+	k3 := deliveredKey{trigger: "t1", source: "source", id: "2"}
+
+	if c.delivered(k1, now) {
+		t.Error("Expected k1 not to be delivered")
+	}
+	c.add(k1, now)
+	if !c.delivered(k1, now) {
+		t.Error("Expected k1 to be delivered")
+	}
+	if c.delivered(k2, now) {
+		t.Error("Expected k2 not to be delivered, it is another Trigger")
+	}
+
+	// The least recent delivery is evicted when the capacity is exceeded.
+	c.add(k2, now)
+	c.add(k3, now)
+	if c.delivered(k1, now) {
+		t.Error("Expected k1 to be evicted")
+	}
+	if !c.delivered(k3, now) {
+		t.Error("Expected k3 to be delivered")
+	}
+
+	// The deliveries expire after the TTL.
+	if c.delivered(k3, now.Add(time.Minute)) {
+		t.Error("Expected k3 to be expired")
+	}
+	if len(c.elements) != 0 || c.entries.Len() != 0 {
+		t.Errorf("Expected the cache to be empty, got %d elements", len(c.elements))
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+const (
+	// explainPrefix is the path prefix of the explain endpoint, followed by
+	// the namespace and the name of the Broker.
+	explainPrefix = "/explain/"
+)
+
+// ExplainResponse is the body of the response of the explain endpoint.
+type ExplainResponse struct {
+	Triggers []TriggerExplanation `json:"triggers"`
+}
+
+// TriggerExplanation explains the result of the filter of a Trigger on the event.
+type TriggerExplanation struct {
+	Name string `json:"name"`
+	eventfilter.Explanation
+}
+
+// EnableExplain enables the explain endpoint of the handler. The endpoint accepts HTTP POST
+// requests with a CloudEvent to the path /explain/namespace/broker, and replies with the
+// result of the filter of each Trigger of the Broker on the event, and the reason of the
+// failures. The event isn't sent to any subscriber.
+func (h *Handler) EnableExplain() {
+	h.explainEnabled = true
+}
+
+func (h *Handler) serveExplain(writer http.ResponseWriter, request *http.Request) {
+	brokerRef, err := parseExplainPath(request.RequestURI)
+	if err != nil {
+		h.logger.Info("Unable to parse path as broker", zap.Error(err), zap.String("path", request.RequestURI))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ctx := logging.WithLogger(request.Context(), h.logger.Sugar())
+
+	message := cehttp.NewMessageFromHttpRequest(request)
+	defer message.Finish(nil)
+
+	event, err := binding.ToEvent(ctx, message)
+	if err != nil {
+		h.logger.Info("failed to extract event from request", zap.Error(err))
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	triggers, err := h.getBrokerTriggers(brokerRef)
+	if err != nil {
+		h.logger.Info("Unable to list the Triggers", zap.Error(err), zap.Any("brokerRef", brokerRef))
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].Name < triggers[j].Name
+	})
+
+	response := ExplainResponse{Triggers: make([]TriggerExplanation, 0, len(triggers))}
+	for _, t := range triggers {
+		explanation := TriggerExplanation{Name: t.Name}
+		if f, err := h.filters.get(t); err != nil {
+			explanation.Result = eventfilter.FailFilter
+			explanation.Reason = fmt.Sprint("invalid filter: ", err)
+		} else {
+			explanation.Explanation = eventfilter.Explain(ctx, f, *event)
+		}
+		response.Triggers = append(response.Triggers, explanation)
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(writer).Encode(response); err != nil {
+		h.logger.Warn("Failed to write the explain response", zap.Error(err))
+	}
+}
+
+// parseExplainPath parses the path of the explain endpoint, in the form
+// "/explain/namespace/name", to determine which Broker the request corresponds to.
+func parseExplainPath(path string) (types.NamespacedName, error) {
+	if !strings.HasPrefix(path, explainPrefix) {
+		return types.NamespacedName{}, fmt.Errorf("incorrect prefix, expected '%s', actual '%s'", explainPrefix, path)
+	}
+	parts := strings.Split(strings.TrimPrefix(path, explainPrefix), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, fmt.Errorf("expected the namespace and the name of the broker, actual '%s'", path)
+	}
+	return types.NamespacedName{
+		Namespace: parts[0],
+		Name:      parts[1],
+	}, nil
+}
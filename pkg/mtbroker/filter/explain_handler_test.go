@@ -0,0 +1,138 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/eventing/pkg/eventfilter"
+	reconcilertesting "knative.dev/eventing/pkg/reconciler/testing"
+)
+
+func TestExplain(t *testing.T) {
+	triggers := []runtime.Object{
+		makeBrokerTrigger("t1", makeTriggerFilterWithAttributes("", "")),
+		makeBrokerTrigger("t2", makeTriggerFilterWithAttributes("some-other-type", "")),
+		makeBrokerTrigger("t3", &eventingv1beta1.TriggerFilter{SQL: "source = 'other'"}),
+		makeBrokerTrigger("t4", &eventingv1beta1.TriggerFilter{SQL: "source ="}),
+		makeBrokerTrigger("t5", nil),
+		makeOtherBrokerTrigger("t6"),
+	}
+	testCases := map[string]struct {
+		disabled         bool
+		path             string
+		expectedStatus   int
+		expectedResponse *ExplainResponse
+	}{
+		"Disabled": {
+			disabled:       true,
+			path:           "/explain/test-namespace/test-broker",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Wrong path": {
+			path:           "/explain/test-namespace",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Explain": {
+			path:           "/explain/test-namespace/test-broker",
+			expectedStatus: http.StatusOK,
+			expectedResponse: &ExplainResponse{Triggers: []TriggerExplanation{{
+				Name:        "t1",
+				Explanation: eventfilter.Explanation{Result: eventfilter.PassFilter},
+			}, {
+				Name: "t2",
+				Explanation: eventfilter.Explanation{
+					Result:    eventfilter.FailFilter,
+					Attribute: "type",
+					Reason:    `value "com.example.someevent" does not match the exact filter "some-other-type"`,
+				},
+			}, {
+				Name: "t3",
+				Explanation: eventfilter.Explanation{
+					Result: eventfilter.FailFilter,
+					Reason: "the SQL expression evaluated to false",
+				},
+			}, {
+				Name: "t4",
+				Explanation: eventfilter.Explanation{
+					Result: eventfilter.FailFilter,
+					Reason: "invalid filter: unexpected end of expression",
+				},
+			}, {
+				Name:        "t5",
+				Explanation: eventfilter.Explanation{Result: eventfilter.NoFilter},
+			}}},
+		},
+		"No Triggers": {
+			path:             "/explain/test-namespace/some-broker",
+			expectedStatus:   http.StatusOK,
+			expectedResponse: &ExplainResponse{Triggers: []TriggerExplanation{}},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			listers := reconcilertesting.NewListers(triggers)
+			r, err := NewHandler(
+				zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller())),
+				listers.GetV1Beta1TriggerLister(),
+				&mockReporter{},
+				8080)
+			if err != nil {
+				t.Fatal("Unable to create receiver:", err)
+			}
+			if !tc.disabled {
+				r.EnableExplain()
+			}
+
+			b, err := makeEvent().MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			request := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewBuffer(b))
+			request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+			responseWriter := httptest.NewRecorder()
+			r.ServeHTTP(responseWriter, request)
+
+			response := responseWriter.Result()
+			if response.StatusCode != tc.expectedStatus {
+				t.Errorf("Unexpected status. Expected %v. Actual %v.", tc.expectedStatus, response.StatusCode)
+			}
+			if tc.expectedResponse == nil {
+				return
+			}
+			got := &ExplainResponse{}
+			if err := json.NewDecoder(response.Body).Decode(got); err != nil {
+				t.Fatal("Unable to decode the response:", err)
+			}
+			if diff := cmp.Diff(tc.expectedResponse, got); diff != "" {
+				t.Error("Unexpected response (-want, +got) =", diff)
+			}
+		})
+	}
+}
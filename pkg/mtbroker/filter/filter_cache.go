@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+	"knative.dev/eventing/pkg/eventfilter/cel"
+	"knative.dev/eventing/pkg/eventfilter/cesql"
+	"knative.dev/eventing/pkg/eventfilter/data"
+)
+
+// filterCache holds the filters built from the Triggers, keyed by Trigger UID, so that
+// expressions are compiled once per Trigger generation instead of once per event.
+type filterCache struct {
+	mu      sync.RWMutex
+	filters map[types.UID]cachedFilter
+}
+
+type cachedFilter struct {
+	generation int64
+	filter     eventfilter.Filter
+}
+
+func newFilterCache() *filterCache {
+	return &filterCache{
+		filters: make(map[types.UID]cachedFilter),
+	}
+}
+
+// get returns the filter of the Trigger, building it if the cache doesn't have it
+// for the current Trigger generation.
+func (c *filterCache) get(t *eventingv1beta1.Trigger) (eventfilter.Filter, error) {
+	c.mu.RLock()
+	cf, ok := c.filters[t.UID]
+	c.mu.RUnlock()
+	if ok && cf.generation == t.Generation {
+		return cf.filter, nil
+	}
+
+	f, err := buildFilter(t.Spec.Filter)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.filters[t.UID] = cachedFilter{generation: t.Generation, filter: f}
+	c.mu.Unlock()
+	return f, nil
+}
+
+// delete removes the filter of the Trigger with the given UID.
+func (c *filterCache) delete(uid types.UID) {
+	c.mu.Lock()
+	delete(c.filters, uid)
+	c.mu.Unlock()
+}
+
+// buildFilter builds the event filter of a Trigger filter spec. All the specified
+// filter dialects must pass for the event to pass.
+func buildFilter(filter *eventingv1beta1.TriggerFilter) (eventfilter.Filter, error) {
+	var filters eventfilter.Filters
+	if filter == nil {
+		return filters, nil
+	}
+	if len(filter.Attributes) != 0 {
+		filters = append(filters, attributes.NewAttributesFilter(filter.Attributes))
+	}
+	if len(filter.Prefix) != 0 {
+		filters = append(filters, attributes.NewPrefixFilter(filter.Prefix))
+	}
+	if len(filter.Suffix) != 0 {
+		filters = append(filters, attributes.NewSuffixFilter(filter.Suffix))
+	}
+	if filter.SQL != "" {
+		f, err := cesql.NewSQLFilter(filter.SQL)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if filter.Data != "" {
+		f, err := data.NewDataFilter(filter.Data)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if filter.CEL != "" {
+		f, err := cel.NewCELFilter(filter.CEL)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if len(filter.All) != 0 {
+		allOf, err := buildFilters(filter.All)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, eventfilter.NewAllFilter(allOf...))
+	}
+	if len(filter.Any) != 0 {
+		anyOf, err := buildFilters(filter.Any)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, eventfilter.NewAnyFilter(anyOf...))
+	}
+	if filter.Not != nil {
+		f, err := buildFilter(filter.Not)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, eventfilter.NewNotFilter(f))
+	}
+	return filters, nil
+}
+
+func buildFilters(specs []eventingv1beta1.TriggerFilter) ([]eventfilter.Filter, error) {
+	filters := make([]eventfilter.Filter, 0, len(specs))
+	for i := range specs {
+		f, err := buildFilter(&specs[i])
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"testing"
+
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/eventing/pkg/eventfilter"
+)
+
+func TestFilterCache(t *testing.T) {
+	c := newFilterCache()
+	trigger := makeTrigger(&eventingv1beta1.TriggerFilter{SQL: "type = 'com.example.someevent'"})
+	trigger.Generation = 1
+
+	f, err := c.get(trigger)
+	if err != nil {
+		t.Fatal("get() =", err)
+	}
+	if got := f.Filter(context.TODO(), *makeEvent()); got != eventfilter.PassFilter {
+		t.Errorf("Filter() = %v, want %v", got, eventfilter.PassFilter)
+	}
+
+	// The cached filter is returned as long as the generation doesn't change.
+	trigger.Spec.Filter.SQL = "type = 'some-other-type'"
+	f, err = c.get(trigger)
+	if err != nil {
+		t.Fatal("get() =", err)
+	}
+	if got := f.Filter(context.TODO(), *makeEvent()); got != eventfilter.PassFilter {
+		t.Errorf("Filter() = %v, want %v", got, eventfilter.PassFilter)
+	}
+
+	// A new generation rebuilds the filter.
+	trigger.Generation = 2
+	f, err = c.get(trigger)
+	if err != nil {
+		t.Fatal("get() =", err)
+	}
+	if got := f.Filter(context.TODO(), *makeEvent()); got != eventfilter.FailFilter {
+		t.Errorf("Filter() = %v, want %v", got, eventfilter.FailFilter)
+	}
+
+	c.delete(trigger.UID)
+	if _, ok := c.filters[trigger.UID]; ok {
+		t.Error("Expected the filter to be deleted")
+	}
+
+	trigger.Spec.Filter.SQL = "type ="
+	if _, err := c.get(trigger); err == nil {
+		t.Error("Expected get() to fail on an invalid SQL expression")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
@@ -30,14 +31,19 @@ import (
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/network"
+	"knative.dev/pkg/system"
 
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1beta1"
 	"knative.dev/eventing/pkg/eventfilter"
-	"knative.dev/eventing/pkg/eventfilter/attributes"
+	"knative.dev/eventing/pkg/eventfilter/data"
 	"knative.dev/eventing/pkg/kncloudevents"
 	broker "knative.dev/eventing/pkg/mtbroker"
+	"knative.dev/eventing/pkg/reconciler/names"
 	"knative.dev/eventing/pkg/reconciler/sugar/trigger/path"
 	"knative.dev/eventing/pkg/tracing"
 	"knative.dev/eventing/pkg/utils"
@@ -60,10 +66,25 @@ type Handler struct {
 	receiver *kncloudevents.HTTPMessageReceiver
 	// sender sends requests to downstream services
 	sender *kncloudevents.HTTPMessageSender
+	// concurrencyLimiters limits the requests sent concurrently to the
+	// subscribers of the Triggers whose delivery sets maxConcurrency
+	concurrencyLimiters *kncloudevents.ConcurrencyLimiters
 	// reporter reports stats of status code and dispatch time
 	reporter StatsReporter
 
 	triggerLister eventinglisters.TriggerLister
+	// filters caches the filters built from the Triggers
+	filters *filterCache
+	// delivered remembers the Triggers that got an event in the broker dispatch mode
+	delivered *deliveredCache
+	// brokerIngressHost is the host of the Broker ingress, where the replies of the
+	// subscribers are sent in the broker dispatch mode
+	brokerIngressHost string
+	// explainEnabled enables the explain endpoint, see EnableExplain
+	explainEnabled bool
+	// eventRecorder reports the reply loops, it is only set if the hop trace
+	// is enabled, see EnableHopTrace
+	eventRecorder record.EventRecorder
 	logger        *zap.Logger
 }
 
@@ -81,17 +102,22 @@ func NewHandler(logger *zap.Logger, triggerLister eventinglisters.TriggerLister,
 	}
 
 	return &Handler{
-		receiver:      kncloudevents.NewHTTPMessageReceiver(port),
-		sender:        sender,
-		reporter:      reporter,
-		triggerLister: triggerLister,
-		logger:        logger,
+		receiver:            kncloudevents.NewHTTPMessageReceiver(port),
+		sender:              sender,
+		concurrencyLimiters: kncloudevents.NewConcurrencyLimiters(kncloudevents.DefaultConcurrencyWaitTimeout),
+		reporter:            reporter,
+		triggerLister:       triggerLister,
+		filters:             newFilterCache(),
+		delivered:           newDeliveredCache(defaultDeliveredCapacity, defaultDeliveredTTL),
+		brokerIngressHost:   network.GetServiceHostname(names.BrokerIngressName, system.Namespace()),
+		logger:              logger,
 	}, nil
 }
 
 // Start begins to receive messages for the handler.
 //
-// HTTP POST requests to the root path (/) are accepted.
+// HTTP POST requests to the paths of Triggers (/triggers/namespace/name/uid) and of
+// Brokers (/brokers/namespace/name) are accepted.
 //
 // This method will block until ctx is done.
 func (h *Handler) Start(ctx context.Context) error {
@@ -106,11 +132,27 @@ func (h *Handler) Start(ctx context.Context) error {
 // 6. write the response
 func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 
+	// The channel dispatchers may validate the filter as a webhook.
+	if kncloudevents.IsWebhookValidationRequest(request) {
+		kncloudevents.WriteWebhookValidationResponse(writer, request)
+		return
+	}
+
 	if request.Method != http.MethodPost {
 		writer.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
 
+	if h.explainEnabled && strings.HasPrefix(request.RequestURI, explainPrefix) {
+		h.serveExplain(writer, request)
+		return
+	}
+
+	if path.IsBroker(request.RequestURI) {
+		h.serveBroker(writer, request)
+		return
+	}
+
 	triggerRef, err := path.Parse(request.RequestURI)
 	if err != nil {
 		h.logger.Info("Unable to parse path as trigger", zap.Error(err), zap.String("path", request.RequestURI))
@@ -157,6 +199,7 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	if err := broker.DeleteTTL(event.Context); err != nil {
 		h.logger.Warn("Failed to delete TTL.", zap.Error(err))
 	}
+	hops := h.extractHops(event)
 
 	h.logger.Debug("Received message", zap.Any("triggerRef", triggerRef))
 
@@ -184,22 +227,26 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 
 	// Check if the event should be sent.
 	ctx = logging.WithLogger(ctx, h.logger.Sugar())
-	filterResult := filterEvent(ctx, t.Spec.Filter, *event)
+	ctx = data.WithParseErrorReporter(ctx, func(error) {
+		_ = h.reporter.ReportDataParseFailure(reportArgs)
+	})
+	filterResult := h.filterEvent(ctx, t, *event, reportArgs)
 
 	if filterResult == eventfilter.FailFilter {
 		// We do not count the event. The event will be counted in the broker ingress.
 		// If the filter didn't pass, it means that the event wasn't meant for this Trigger.
+		// The filter result is counted by filterEvent.
 		return
 	}
 
 	h.reportArrivalTime(event, reportArgs)
 
-	h.send(ctx, writer, request.Header, subscriberURI.String(), reportArgs, event, ttl)
+	h.send(ctx, writer, request.Header, subscriberURI.String(), reportArgs, t, event, ttl, hops)
 }
 
-func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers http.Header, target string, reportArgs *ReportArgs, event *cloudevents.Event, ttl int32) {
+func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers http.Header, target string, reportArgs *ReportArgs, t *eventingv1beta1.Trigger, event *cloudevents.Event, ttl int32, hops []string) {
 	// send the event to trigger's subscriber
-	response, err := h.sendEvent(ctx, headers, target, event, reportArgs)
+	response, err := h.sendEvent(ctx, headers, target, t, event, reportArgs)
 	if err != nil {
 		h.logger.Error("failed to send event", zap.Error(err))
 		writer.WriteHeader(http.StatusInternalServerError)
@@ -210,14 +257,14 @@ func (h *Handler) send(ctx context.Context, writer http.ResponseWriter, headers
 	h.logger.Debug("Successfully dispatched message", zap.Any("target", target))
 
 	// If there is an event in the response write it to the response
-	statusCode, err := h.writeResponse(ctx, writer, response, ttl, target)
+	statusCode, err := h.writeResponse(ctx, writer, response, t, ttl, hops, target)
 	if err != nil {
 		h.logger.Error("failed to write response", zap.Error(err))
 	}
 	_ = h.reporter.ReportEventCount(reportArgs, statusCode)
 }
 
-func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target string, event *cloudevents.Event, reporterArgs *ReportArgs) (*http.Response, error) {
+func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target string, t *eventingv1beta1.Trigger, event *cloudevents.Event, reporterArgs *ReportArgs) (*http.Response, error) {
 	// Send the event to the subscriber
 	req, err := h.sender.NewCloudEventRequestWithTarget(ctx, target)
 	if err != nil {
@@ -233,6 +280,20 @@ func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target str
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
 
+	// Wait for the in-flight requests to the subscriber to complete if its
+	// maximum concurrency is reached. The event fails if it waits too long,
+	// so that the channel retries it or sends it to the dead letter sink.
+	if t.Spec.Delivery != nil && t.Spec.Delivery.MaxConcurrency != nil {
+		release, queueTime, err := h.concurrencyLimiters.Acquire(ctx, target, int(*t.Spec.Delivery.MaxConcurrency))
+		if queueTime > 0 {
+			_ = h.reporter.ReportEventQueueTime(reporterArgs, queueTime)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to dispatch message: %w", err)
+		}
+		defer release()
+	}
+
 	start := time.Now()
 	resp, err := h.sender.Send(req)
 	dispatchTime := time.Since(start)
@@ -251,7 +312,7 @@ func (h *Handler) sendEvent(ctx context.Context, headers http.Header, target str
 }
 
 // The return values are the status
-func (h *Handler) writeResponse(ctx context.Context, writer http.ResponseWriter, resp *http.Response, ttl int32, target string) (int, error) {
+func (h *Handler) writeResponse(ctx context.Context, writer http.ResponseWriter, resp *http.Response, t *eventingv1beta1.Trigger, ttl int32, hops []string, target string) (int, error) {
 	response := cehttp.NewMessageFromHttpResponse(resp)
 	defer response.Finish(nil)
 
@@ -283,10 +344,9 @@ func (h *Handler) writeResponse(ctx context.Context, writer http.ResponseWriter,
 		return http.StatusBadGateway, err
 	}
 
-	// Reattach the TTL (with the same value) to the response event before sending it to the Broker.
-	if err := broker.SetTTL(event.Context, ttl); err != nil {
+	if err := h.prepareReply(event, t, ttl, hops); err != nil {
 		writer.WriteHeader(http.StatusInternalServerError)
-		return http.StatusInternalServerError, fmt.Errorf("failed to reset TTL: %w", err)
+		return http.StatusInternalServerError, err
 	}
 
 	eventResponse := binding.ToMessage(event)
@@ -324,15 +384,29 @@ func (h *Handler) getTrigger(ref path.NamespacedNameUID) (*eventingv1beta1.Trigg
 	return t, nil
 }
 
-func filterEvent(ctx context.Context, filter *eventingv1beta1.TriggerFilter, event cloudevents.Event) eventfilter.FilterResult {
-	if filter == nil {
-		return eventfilter.NoFilter
+// filterEvent applies the filter of the Trigger to the event, and reports the result.
+func (h *Handler) filterEvent(ctx context.Context, t *eventingv1beta1.Trigger, event cloudevents.Event, reportArgs *ReportArgs) eventfilter.FilterResult {
+	start := time.Now()
+	f, err := h.filters.get(t)
+	if err != nil {
+		h.logger.Warn("Failed to build the Trigger filter", zap.Error(err), zap.String("namespace", t.Namespace), zap.String("trigger", t.Name))
+		_ = h.reporter.ReportFilter(reportArgs, eventfilter.FailFilter, time.Since(start))
+		return eventfilter.FailFilter
+	}
+	res := f.Filter(ctx, event)
+	_ = h.reporter.ReportFilter(reportArgs, res, time.Since(start))
+	return res
+}
+
+// TriggerDeleted evicts the cached filter of a deleted Trigger. It is meant to be
+// registered as the delete handler of the Trigger informer.
+func (h *Handler) TriggerDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
 	}
-	var filters eventfilter.Filters
-	if filter.Attributes != nil && len(filter.Attributes) != 0 {
-		filters = append(filters, attributes.NewAttributesFilter(filter.Attributes))
+	if t, ok := obj.(*eventingv1beta1.Trigger); ok {
+		h.filters.delete(t.UID)
 	}
-	return filters.Filter(ctx, event)
 }
 
 // triggerFilterAttribute returns the filter attribute value for a given `attributeName`. If it doesn't not exist,
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -38,9 +39,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
 	"knative.dev/pkg/apis"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/kncloudevents"
 	broker "knative.dev/eventing/pkg/mtbroker"
 	reconcilertesting "knative.dev/eventing/pkg/reconciler/testing"
 )
@@ -84,12 +89,18 @@ func TestReceiver(t *testing.T) {
 		expectedEventCount          bool
 		expectedEventDispatchTime   bool
 		expectedEventProcessingTime bool
+		expectedDataParseFailure    bool
+		expectedFilterResult        eventfilter.FilterResult
 		response                    *http.Response
 	}{
 		"Not POST": {
 			request:        httptest.NewRequest(http.MethodGet, validPath, nil),
 			expectedStatus: http.StatusMethodNotAllowed,
 		},
+		"Webhook validation": {
+			request:        webhookValidationRequest(),
+			expectedStatus: http.StatusOK,
+		},
 		"Path too short": {
 			request:        httptest.NewRequest(http.MethodPost, "/test-namespace/test-trigger", nil),
 			expectedStatus: http.StatusBadRequest,
@@ -120,6 +131,7 @@ func TestReceiver(t *testing.T) {
 			expectedDispatch:          true,
 			expectedEventCount:        true,
 			expectedEventDispatchTime: true,
+			expectedFilterResult:      eventfilter.NoFilter,
 		},
 		"No TTL": {
 			triggers: []*eventingv1beta1.Trigger{
@@ -131,7 +143,8 @@ func TestReceiver(t *testing.T) {
 			triggers: []*eventingv1beta1.Trigger{
 				makeTrigger(makeTriggerFilterWithAttributes("some-other-type", "")),
 			},
-			expectedEventCount: false,
+			expectedEventCount:   false,
+			expectedFilterResult: eventfilter.FailFilter,
 		},
 		"Wrong type with attribs": {
 			triggers: []*eventingv1beta1.Trigger{
@@ -190,6 +203,7 @@ func TestReceiver(t *testing.T) {
 			expectedDispatch:          true,
 			expectedEventCount:        true,
 			expectedEventDispatchTime: true,
+			expectedFilterResult:      eventfilter.PassFilter,
 		},
 		"Dispatch succeeded - Specific with attribs": {
 			triggers: []*eventingv1beta1.Trigger{
@@ -218,6 +232,191 @@ func TestReceiver(t *testing.T) {
 			expectedEventDispatchTime:   true,
 			expectedEventProcessingTime: true,
 		},
+		"Dispatch succeeded - Prefix and Suffix": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Prefix: map[string]string{"type": "com.example."},
+					Suffix: map[string]string{"source": "context"},
+				}),
+			},
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
+		"Wrong prefix": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Prefix: map[string]string{"type": "org.example."},
+				}),
+			},
+			expectedEventCount: false,
+		},
+		"Wrong suffix": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Suffix: map[string]string{"type": ".otherevent"},
+				}),
+			},
+			expectedEventCount: false,
+		},
+		"Dispatch succeeded - SQL": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					SQL: "type = 'com.example.someevent' AND source LIKE '/my%'",
+				}),
+			},
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
+		"Wrong SQL": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					SQL: "type = 'some-other-type'",
+				}),
+			},
+			expectedEventCount: false,
+		},
+		"Invalid SQL": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					SQL: "type =",
+				}),
+			},
+			expectedEventCount:   false,
+			expectedFilterResult: eventfilter.FailFilter,
+		},
+		"Dispatch succeeded - Any filter": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Any: []eventingv1beta1.TriggerFilter{
+						{Attributes: eventingv1beta1.TriggerFilterAttributes{"type": "some-other-type"}},
+						{Attributes: eventingv1beta1.TriggerFilterAttributes{"type": eventType}},
+					},
+				}),
+			},
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
+		"Dispatch succeeded - nested All and Not": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					All: []eventingv1beta1.TriggerFilter{
+						{Prefix: map[string]string{"type": "com.example."}},
+						{Not: &eventingv1beta1.TriggerFilter{
+							Any: []eventingv1beta1.TriggerFilter{
+								{Attributes: eventingv1beta1.TriggerFilterAttributes{"source": "some-other-source"}},
+								{SQL: "type = 'some-other-type'"},
+							},
+						}},
+					},
+				}),
+			},
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
+		"Wrong Any": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Any: []eventingv1beta1.TriggerFilter{
+						{Attributes: eventingv1beta1.TriggerFilterAttributes{"type": "some-other-type"}},
+						{Attributes: eventingv1beta1.TriggerFilterAttributes{"source": "some-other-source"}},
+					},
+				}),
+			},
+			expectedEventCount: false,
+		},
+		"Wrong Not": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Not: &eventingv1beta1.TriggerFilter{
+						Attributes: eventingv1beta1.TriggerFilterAttributes{"type": eventType},
+					},
+				}),
+			},
+			expectedEventCount: false,
+		},
+		"Dispatch succeeded - Data": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Data: `$.customer.tier == "gold"`,
+				}),
+			},
+			event:                     makeEventWithData(cloudevents.ApplicationJSON, `{"customer":{"tier":"gold"}}`),
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+		},
+		"Wrong Data": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Data: `$.customer.tier == "gold"`,
+				}),
+			},
+			event:              makeEventWithData(cloudevents.ApplicationJSON, `{"customer":{"tier":"silver"}}`),
+			expectedEventCount: false,
+		},
+		"Data not JSON": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Data: `$.customer.tier == "gold"`,
+				}),
+			},
+			event:              makeEventWithData(cloudevents.TextPlain, `{"customer":{"tier":"gold"}}`),
+			expectedEventCount: false,
+		},
+		"Malformed JSON Data": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					Data: `$.customer.tier == "gold"`,
+				}),
+			},
+			request: func() *http.Request {
+				// Use the binary mode, as malformed data can't be embedded in a structured event.
+				request := httptest.NewRequest(http.MethodPost, validPath, strings.NewReader(`{"customer":`))
+				request.Header.Set("ce-specversion", event.CloudEventsVersionV1)
+				request.Header.Set("ce-id", "1234")
+				request.Header.Set("ce-type", eventType)
+				request.Header.Set("ce-source", eventSource)
+				request.Header.Set("ce-"+broker.TTLAttribute, "1")
+				request.Header.Set(cehttp.ContentType, cloudevents.ApplicationJSON)
+				return request
+			}(),
+			expectedDataParseFailure: true,
+		},
+		"Dispatch succeeded - CEL": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					CEL: `ce.type == "` + eventType + `" && data.customer.tier == "gold"`,
+				}),
+			},
+			event:                     makeEventWithData(cloudevents.ApplicationJSON, `{"customer":{"tier":"gold"}}`),
+			expectedDispatch:          true,
+			expectedEventCount:        true,
+			expectedEventDispatchTime: true,
+			expectedFilterResult:      eventfilter.PassFilter,
+		},
+		"Wrong CEL": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					CEL: `data.customer.tier == "gold"`,
+				}),
+			},
+			event:                makeEventWithData(cloudevents.ApplicationJSON, `{"customer":{"tier":"silver"}}`),
+			expectedEventCount:   false,
+			expectedFilterResult: eventfilter.FailFilter,
+		},
+		"Invalid CEL": {
+			triggers: []*eventingv1beta1.Trigger{
+				makeTrigger(&eventingv1beta1.TriggerFilter{
+					CEL: `ce.type ==`,
+				}),
+			},
+			expectedEventCount:   false,
+			expectedFilterResult: eventfilter.FailFilter,
+		},
 		"Wrong Extension with attribs": {
 			triggers: []*eventingv1beta1.Trigger{
 				makeTrigger(makeTriggerFilterWithAttributesAndExtension(eventType, eventSource, "some-other-extension-value")),
@@ -409,6 +608,12 @@ func TestReceiver(t *testing.T) {
 			if tc.expectedEventProcessingTime != reporter.eventProcessingTimeReported {
 				t.Errorf("Incorrect event processing time reported metric. Expected %v, Actual %v", tc.expectedEventProcessingTime, reporter.eventProcessingTimeReported)
 			}
+			if tc.expectedDataParseFailure != reporter.dataParseFailureReported {
+				t.Errorf("Incorrect data parse failure reported metric. Expected %v, Actual %v", tc.expectedDataParseFailure, reporter.dataParseFailureReported)
+			}
+			if tc.expectedFilterResult != "" && tc.expectedFilterResult != reporter.filterResult {
+				t.Errorf("Incorrect filter result reported metric. Expected %v, Actual %v", tc.expectedFilterResult, reporter.filterResult)
+			}
 			if tc.returnedEvent != nil {
 				if tc.returnedEvent.SpecVersion() != event.CloudEventsVersionV1 {
 					t.Errorf("Incorrect spec version. Expected %v, Actual %v", tc.returnedEvent.SpecVersion(), event.CloudEventsVersionV1)
@@ -451,6 +656,69 @@ func TestReceiver(t *testing.T) {
 	}
 }
 
+func TestReceiverMaxConcurrency(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-unblock
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer s.Close()
+
+	trig := makeTrigger(makeTriggerFilterWithAttributes("", ""))
+	trig.Spec.Delivery = &eventingduckv1.DeliverySpec{MaxConcurrency: pointer.Int32Ptr(1)}
+	trig.Status.SubscriberURI, _ = apis.ParseURL(s.URL)
+	listers := reconcilertesting.NewListers([]runtime.Object{trig})
+	reporter := &mockReporter{}
+	r, err := NewHandler(zaptest.NewLogger(t), listers.GetV1Beta1TriggerLister(), reporter, 8080)
+	if err != nil {
+		t.Fatal("Unable to create receiver:", err)
+	}
+	r.concurrencyLimiters = kncloudevents.NewConcurrencyLimiters(50 * time.Millisecond)
+
+	serve := func() int {
+		b, err := makeEvent().MarshalJSON()
+		if err != nil {
+			t.Error(err)
+			return 0
+		}
+		request := httptest.NewRequest(http.MethodPost, validPath, bytes.NewBuffer(b))
+		request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+		responseWriter := httptest.NewRecorder()
+		r.ServeHTTP(responseWriter, request)
+		return responseWriter.Result().StatusCode
+	}
+
+	done := make(chan int)
+	go func() {
+		done <- serve()
+	}()
+	<-started
+
+	// The subscriber is busy with the first event, the second one waits
+	// for too long and fails.
+	if status := serve(); status != http.StatusInternalServerError {
+		t.Errorf("Unexpected status. Expected %v. Actual %v.", http.StatusInternalServerError, status)
+	}
+	reporter.mu.Lock()
+	if !reporter.eventQueueTimeReported {
+		t.Error("Expected the event queue time to be reported")
+	}
+	reporter.mu.Unlock()
+
+	close(unblock)
+	if status := <-done; status != http.StatusAccepted {
+		t.Errorf("Unexpected status. Expected %v. Actual %v.", http.StatusAccepted, status)
+	}
+}
+
+func webhookValidationRequest() *http.Request {
+	request := httptest.NewRequest(http.MethodOptions, validPath, nil)
+	request.Header.Set(kncloudevents.WebhookRequestOriginHeader, "dispatcher")
+	return request
+}
+
 type responseWriterWithInvocationsCheck struct {
 	http.ResponseWriter
 	headersWritten *atomic.Bool
@@ -465,26 +733,58 @@ func (r *responseWriterWithInvocationsCheck) WriteHeader(statusCode int) {
 }
 
 type mockReporter struct {
+	// mu guards the fields written by the parallel dispatches to the Triggers of a Broker.
+	mu                          sync.Mutex
 	eventCountReported          bool
 	eventDispatchTimeReported   bool
 	eventProcessingTimeReported bool
+	eventQueueTimeReported      bool
+	dataParseFailureReported    bool
+	filterResult                eventfilter.FilterResult
 }
 
 func (r *mockReporter) ReportEventCount(args *ReportArgs, responseCode int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.eventCountReported = true
 	return nil
 }
 
 func (r *mockReporter) ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.eventDispatchTimeReported = true
 	return nil
 }
 
 func (r *mockReporter) ReportEventProcessingTime(args *ReportArgs, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.eventProcessingTimeReported = true
 	return nil
 }
 
+func (r *mockReporter) ReportEventQueueTime(args *ReportArgs, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.eventQueueTimeReported = true
+	return nil
+}
+
+func (r *mockReporter) ReportFilter(args *ReportArgs, result eventfilter.FilterResult, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filterResult = result
+	return nil
+}
+
+func (r *mockReporter) ReportDataParseFailure(args *ReportArgs) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dataParseFailureReported = true
+	return nil
+}
+
 type fakeHandler struct {
 	failRequest     bool
 	failStatus      int
@@ -631,6 +931,13 @@ func makeEventWithExtension(extName, extValue string) *cloudevents.Event {
 	return &e
 }
 
+func makeEventWithData(contentType, data string) *cloudevents.Event {
+	e := makeEvent()
+	e.SetDataContentType(contentType)
+	e.DataEncoded = []byte(data)
+	return e
+}
+
 func makeNonEmptyResponse() *http.Response {
 	r := &http.Response{
 		Status:     "200 OK",
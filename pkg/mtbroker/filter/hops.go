@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	broker "knative.dev/eventing/pkg/mtbroker"
+)
+
+// replyLoopReason is the reason of the Kubernetes Events reporting reply loops.
+const replyLoopReason = "ReplyLoop"
+
+// EnableHopTrace enables the hop trace of the events. The handler then appends
+// the name of the Trigger to the hops of the replies of its subscriber, and
+// reports the replies closing a loop with a Kubernetes Event on the Trigger,
+// using the given recorder.
+func (h *Handler) EnableHopTrace(recorder record.EventRecorder) {
+	h.eventRecorder = recorder
+}
+
+// extractHops removes the hops from the event received from the Broker and
+// returns them, if the hop trace is enabled.
+func (h *Handler) extractHops(event *cloudevents.Event) []string {
+	if h.eventRecorder == nil {
+		return nil
+	}
+	hops := broker.GetHops(event.Context)
+	if err := broker.DeleteHops(event.Context); err != nil {
+		h.logger.Warn("Failed to delete hops.", zap.Error(err))
+	}
+	return hops
+}
+
+// prepareReply attaches the TTL and, if the hop trace is enabled, the hops to
+// the reply of the subscriber of the Trigger.
+func (h *Handler) prepareReply(reply *cloudevents.Event, t *eventingv1beta1.Trigger, ttl int32, hops []string) error {
+	// Reattach the TTL (with the same value) to the response event before sending it to the Broker.
+	if err := broker.SetTTL(reply.Context, ttl); err != nil {
+		return fmt.Errorf("failed to reset TTL: %w", err)
+	}
+	if h.eventRecorder == nil {
+		return nil
+	}
+
+	if loop := broker.NewLoop(hops, t.Name); loop != nil {
+		path := strings.Join(loop, " -> ")
+		h.logger.Warn("Reply loop detected", zap.String("namespace", t.Namespace), zap.String("trigger", t.Name),
+			zap.String("loop", path))
+		h.eventRecorder.Eventf(t, corev1.EventTypeWarning, replyLoopReason,
+			"The replies of the subscriber loop through the Triggers %s", path)
+	}
+	// The hops are shared by the Triggers of the Broker, copy them.
+	hops = append(hops[:len(hops):len(hops)], t.Name)
+	if err := broker.SetHops(reply.Context, hops); err != nil {
+		return fmt.Errorf("failed to set hops: %w", err)
+	}
+	return nil
+}
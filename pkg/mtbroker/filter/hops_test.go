@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+
+	broker "knative.dev/eventing/pkg/mtbroker"
+	reconcilertesting "knative.dev/eventing/pkg/reconciler/testing"
+)
+
+func TestHopTrace(t *testing.T) {
+	tests := map[string]struct {
+		hops          []string
+		wantReplyHops []string
+		wantEvents    []string
+	}{
+		"first hop": {
+			wantReplyHops: []string{triggerName},
+		},
+		"no loop": {
+			hops:          []string{"other"},
+			wantReplyHops: []string{"other", triggerName},
+		},
+		"loop": {
+			hops:          []string{triggerName, "other"},
+			wantReplyHops: []string{triggerName, "other", triggerName},
+			wantEvents:    []string{"Warning ReplyLoop The replies of the subscriber loop through the Triggers test-trigger -> other -> test-trigger"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var receivedHops []string
+			subscriber := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				e, err := binding.ToEvent(context.Background(), cehttp.NewMessageFromHttpRequest(request))
+				if err != nil {
+					t.Error("Failed to extract the event:", err)
+				}
+				receivedHops = broker.GetHops(e.Context)
+				reply := makeDifferentEvent()
+				_ = broker.DeleteTTL(reply.Context)
+				_ = cehttp.WriteResponseWriter(context.Background(), binding.ToMessage(reply), http.StatusOK, writer)
+			}))
+			defer subscriber.Close()
+
+			trigger := makeTrigger(makeTriggerFilterWithAttributes("", ""))
+			trigger.Status.SubscriberURI, _ = apis.ParseURL(subscriber.URL)
+			listers := reconcilertesting.NewListers([]runtime.Object{trigger})
+			h, err := NewHandler(
+				zaptest.NewLogger(t, zaptest.WrapOptions(zap.AddCaller())),
+				listers.GetV1Beta1TriggerLister(),
+				&mockReporter{},
+				8080)
+			if err != nil {
+				t.Fatal("Unable to create handler:", err)
+			}
+			recorder := record.NewFakeRecorder(10)
+			h.EnableHopTrace(recorder)
+
+			e := makeEvent()
+			if tc.hops != nil {
+				_ = broker.SetHops(e.Context, tc.hops)
+			}
+			b, _ := e.MarshalJSON()
+			request := httptest.NewRequest(http.MethodPost, validPath, bytes.NewBuffer(b))
+			request.Header.Set(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+			responseWriter := httptest.NewRecorder()
+			h.ServeHTTP(responseWriter, request)
+
+			if receivedHops != nil {
+				t.Errorf("Expected the hops to be removed from the event sent to the subscriber, got %v", receivedHops)
+			}
+			reply, err := binding.ToEvent(context.Background(), cehttp.NewMessageFromHttpResponse(responseWriter.Result()))
+			if err != nil {
+				t.Fatal("Expected a reply:", err)
+			}
+			if diff := cmp.Diff(tc.wantReplyHops, broker.GetHops(reply.Context)); diff != "" {
+				t.Error("Unexpected reply hops (-want, +got):", diff)
+			}
+
+			close(recorder.Events)
+			var events []string
+			for e := range recorder.Events {
+				events = append(events, e)
+			}
+			if diff := cmp.Diff(tc.wantEvents, events); diff != "" {
+				t.Error("Unexpected events (-want, +got):", diff)
+			}
+		})
+	}
+}
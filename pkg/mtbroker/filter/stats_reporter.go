@@ -26,6 +26,7 @@ import (
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+	"knative.dev/eventing/pkg/eventfilter"
 	broker "knative.dev/eventing/pkg/mtbroker"
 	"knative.dev/pkg/metrics"
 	"knative.dev/pkg/metrics/metricskey"
@@ -61,6 +62,38 @@ var (
 		stats.UnitMilliseconds,
 	)
 
+	// queueTimeInMsecM records the time spent by an event waiting for the
+	// in-flight requests to the Trigger subscriber to complete, in milliseconds.
+	queueTimeInMsecM = stats.Float64(
+		"event_queue_latencies",
+		"The time spent by an event waiting for the in-flight requests to a Trigger subscriber to complete",
+		stats.UnitMilliseconds,
+	)
+
+	// dataParseFailureCountM is a counter which records the number of events
+	// whose data couldn't be parsed by a Trigger data filter.
+	dataParseFailureCountM = stats.Int64(
+		"data_parse_failure_count",
+		"Number of events whose data couldn't be parsed by a Trigger filter",
+		stats.UnitDimensionless,
+	)
+
+	// filterCountM is a counter which records the number of events evaluated
+	// by the filter of a Trigger, by filter result.
+	filterCountM = stats.Int64(
+		"filter_count",
+		"Number of events evaluated by the filter of a Trigger",
+		stats.UnitDimensionless,
+	)
+
+	// filterTimeInMsecM records the time spent evaluating the filter of a
+	// Trigger, in milliseconds.
+	filterTimeInMsecM = stats.Float64(
+		"filter_latencies",
+		"The time spent evaluating the filter of a Trigger",
+		stats.UnitMilliseconds,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -69,6 +102,7 @@ var (
 	triggerFilterTypeKey = tag.MustNewKey(metricskey.LabelFilterType)
 	responseCodeKey      = tag.MustNewKey(metricskey.LabelResponseCode)
 	responseCodeClassKey = tag.MustNewKey(metricskey.LabelResponseCodeClass)
+	filterResultKey      = tag.MustNewKey("filter_result")
 )
 
 type ReportArgs struct {
@@ -87,6 +121,9 @@ type StatsReporter interface {
 	ReportEventCount(args *ReportArgs, responseCode int) error
 	ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error
 	ReportEventProcessingTime(args *ReportArgs, d time.Duration) error
+	ReportEventQueueTime(args *ReportArgs, d time.Duration) error
+	ReportDataParseFailure(args *ReportArgs) error
+	ReportFilter(args *ReportArgs, result eventfilter.FilterResult, d time.Duration) error
 }
 
 var _ StatsReporter = (*reporter)(nil)
@@ -127,6 +164,30 @@ func register() {
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000
 			TagKeys:     []tag.Key{triggerFilterTypeKey, broker.UniqueTagKey, broker.ContainerTagKey},
 		},
+		&view.View{
+			Description: queueTimeInMsecM.Description(),
+			Measure:     queueTimeInMsecM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000
+			TagKeys:     []tag.Key{triggerFilterTypeKey, broker.UniqueTagKey, broker.ContainerTagKey},
+		},
+		&view.View{
+			Description: dataParseFailureCountM.Description(),
+			Measure:     dataParseFailureCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{triggerFilterTypeKey, broker.UniqueTagKey, broker.ContainerTagKey},
+		},
+		&view.View{
+			Description: filterCountM.Description(),
+			Measure:     filterCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{triggerFilterTypeKey, filterResultKey, broker.UniqueTagKey, broker.ContainerTagKey},
+		},
+		&view.View{
+			Description: filterTimeInMsecM.Description(),
+			Measure:     filterTimeInMsecM,
+			Aggregation: view.Distribution(metrics.Buckets125(0.01, 100)...), // 0.01, 0.02, 0.05, 0.1, ..., 50, 100
+			TagKeys:     []tag.Key{triggerFilterTypeKey, filterResultKey, broker.UniqueTagKey, broker.ContainerTagKey},
+		},
 	)
 	if err != nil {
 		log.Printf("failed to register opencensus views, %s", err)
@@ -170,6 +231,41 @@ func (r *reporter) ReportEventProcessingTime(args *ReportArgs, d time.Duration)
 	return nil
 }
 
+// ReportEventQueueTime captures the time spent waiting for the in-flight
+// requests to the Trigger subscriber to complete.
+func (r *reporter) ReportEventQueueTime(args *ReportArgs, d time.Duration) error {
+	ctx, err := r.generateTag(args)
+	if err != nil {
+		return err
+	}
+
+	// convert time.Duration in nanoseconds to milliseconds.
+	metrics.Record(ctx, queueTimeInMsecM.M(float64(d/time.Millisecond)))
+	return nil
+}
+
+// ReportDataParseFailure captures the count of events whose data couldn't be parsed.
+func (r *reporter) ReportDataParseFailure(args *ReportArgs) error {
+	ctx, err := r.generateTag(args)
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, dataParseFailureCountM.M(1))
+	return nil
+}
+
+// ReportFilter captures the result of the filter of a Trigger and the time spent evaluating it.
+func (r *reporter) ReportFilter(args *ReportArgs, result eventfilter.FilterResult, d time.Duration) error {
+	ctx, err := r.generateTag(args, tag.Insert(filterResultKey, string(result)))
+	if err != nil {
+		return err
+	}
+	// convert time.Duration in nanoseconds to fractional milliseconds, as filters are fast.
+	metrics.Record(ctx, filterCountM.M(1))
+	metrics.Record(ctx, filterTimeInMsecM.M(float64(d)/float64(time.Millisecond)))
+	return nil
+}
+
 func (r *reporter) generateTag(args *ReportArgs, tags ...tag.Mutator) (context.Context, error) {
 	ctx := metricskey.WithResource(emptyContext, resource.Resource{
 		Type: metricskey.ResourceTypeKnativeTrigger,
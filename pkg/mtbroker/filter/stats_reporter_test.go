@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"go.opencensus.io/resource"
+
+	"knative.dev/eventing/pkg/eventfilter"
 	broker "knative.dev/eventing/pkg/mtbroker"
 	"knative.dev/pkg/metrics/metricskey"
 	"knative.dev/pkg/metrics/metricstest"
@@ -90,6 +92,37 @@ func TestStatsReporter(t *testing.T) {
 	})
 	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric("event_processing_latencies", 2, wantTags))
 	metricstest.CheckDistributionData(t, "event_processing_latencies", wantTags, 2, 1000.0, 8000.0)
+
+	// test ReportEventQueueTime
+	expectSuccess(t, func() error {
+		return r.ReportEventQueueTime(args, 20*time.Millisecond)
+	})
+	expectSuccess(t, func() error {
+		return r.ReportEventQueueTime(args, 500*time.Millisecond)
+	})
+	metricstest.CheckDistributionData(t, "event_queue_latencies", wantTags, 2, 20.0, 500.0)
+
+	// test ReportDataParseFailure
+	expectSuccess(t, func() error {
+		return r.ReportDataParseFailure(args)
+	})
+	metricstest.AssertMetric(t, metricstest.IntMetric("data_parse_failure_count", 1, wantTags).WithResource(&resource))
+
+	// test ReportFilter
+	wantFilterTags := map[string]string{
+		"filter_result": "fail",
+	}
+	for k, v := range wantTags {
+		wantFilterTags[k] = v
+	}
+	expectSuccess(t, func() error {
+		return r.ReportFilter(args, eventfilter.FailFilter, 50*time.Microsecond)
+	})
+	expectSuccess(t, func() error {
+		return r.ReportFilter(args, eventfilter.FailFilter, 2*time.Millisecond)
+	})
+	metricstest.AssertMetric(t, metricstest.IntMetric("filter_count", 2, wantFilterTags).WithResource(&resource))
+	metricstest.CheckDistributionData(t, "filter_latencies", wantFilterTags, 2, 0.05, 2.0)
 }
 
 func TestReporterEmptySourceAndTypeFilter(t *testing.T) {
@@ -153,6 +186,10 @@ func resetMetrics() {
 	metricstest.Unregister(
 		"event_count",
 		"event_dispatch_latencies",
-		"event_processing_latencies")
+		"event_processing_latencies",
+		"event_queue_latencies",
+		"data_parse_failure_count",
+		"filter_count",
+		"filter_latencies")
 	register()
 }
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	// HopsAttribute is the name of the CloudEvents extension attribute used to
+	// store the names of the Triggers whose subscribers replied with the event,
+	// oldest first and separated by commas. All interactions with the attribute
+	// should be done through the GetHops and SetHops functions.
+	HopsAttribute = "knativebrokerhops"
+
+	// MaxHops is the maximum number of hops kept in the HopsAttribute. The
+	// oldest hops are dropped beyond it.
+	MaxHops = 32
+)
+
+// GetHops returns the names of the Triggers whose subscribers replied with the
+// event, oldest first.
+func GetHops(ctx cloudevents.EventContext) []string {
+	raw, err := ctx.GetExtension(HopsAttribute)
+	if err != nil {
+		return nil
+	}
+	hops, err := cetypes.ToString(raw)
+	if err != nil || hops == "" {
+		return nil
+	}
+	return strings.Split(hops, ",")
+}
+
+// SetHops sets the hops into the EventContext, keeping the most recent
+// MaxHops ones.
+func SetHops(ctx cloudevents.EventContext, hops []string) error {
+	if len(hops) > MaxHops {
+		hops = hops[len(hops)-MaxHops:]
+	}
+	return ctx.SetExtension(HopsAttribute, strings.Join(hops, ","))
+}
+
+// DeleteHops removes the hops CE extension attribute.
+func DeleteHops(ctx cloudevents.EventContext) error {
+	return ctx.SetExtension(HopsAttribute, nil)
+}
+
+// NewLoop returns the loop closed by the reply of the subscriber of the given
+// Trigger to an event with the given hops, e.g. [a b a] when the reply of the
+// subscriber of a led to b, which led to a again. It returns nil if the reply
+// doesn't close a loop, or if the hops already contain one, so that each loop
+// is reported once.
+func NewLoop(hops []string, trigger string) []string {
+	seen := make(map[string]bool, len(hops))
+	start := -1
+	for i, hop := range hops {
+		if seen[hop] {
+			return nil
+		}
+		seen[hop] = true
+		if hop == trigger {
+			start = i
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	loop := make([]string, 0, len(hops)-start+1)
+	loop = append(loop, hops[start:]...)
+	return append(loop, trigger)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"strconv"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHops(t *testing.T) {
+	event := cloudevents.NewEvent()
+	if hops := GetHops(event.Context); hops != nil {
+		t.Errorf("Expected no hops, got %v", hops)
+	}
+
+	if err := SetHops(event.Context, []string{"a", "b"}); err != nil {
+		t.Fatal("SetHops() =", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, GetHops(event.Context)); diff != "" {
+		t.Error("Unexpected hops (-want, +got):", diff)
+	}
+
+	if err := DeleteHops(event.Context); err != nil {
+		t.Fatal("DeleteHops() =", err)
+	}
+	if hops := GetHops(event.Context); hops != nil {
+		t.Errorf("Expected no hops, got %v", hops)
+	}
+
+	hops := make([]string, 0, MaxHops+2)
+	for i := 0; i < MaxHops+2; i++ {
+		hops = append(hops, strconv.Itoa(i))
+	}
+	if err := SetHops(event.Context, hops); err != nil {
+		t.Fatal("SetHops() =", err)
+	}
+	if diff := cmp.Diff(hops[2:], GetHops(event.Context)); diff != "" {
+		t.Error("Expected the oldest hops to be dropped (-want, +got):", diff)
+	}
+}
+
+func TestNewLoop(t *testing.T) {
+	tests := map[string]struct {
+		hops    []string
+		trigger string
+		want    []string
+	}{
+		"no hops": {
+			trigger: "a",
+		},
+		"no loop": {
+			hops:    []string{"a", "b"},
+			trigger: "c",
+		},
+		"self loop": {
+			hops:    []string{"a"},
+			trigger: "a",
+			want:    []string{"a", "a"},
+		},
+		"loop": {
+			hops:    []string{"x", "a", "b"},
+			trigger: "a",
+			want:    []string{"a", "b", "a"},
+		},
+		"already looped": {
+			hops:    []string{"a", "b", "a"},
+			trigger: "b",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, NewLoop(tc.hops, tc.trigger)); diff != "" {
+				t.Error("Unexpected loop (-want, +got):", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+)
+
+const (
+	// tokenReviewCacheTTL is how long the result of a TokenReview is reused for.
+	tokenReviewCacheTTL = time.Minute
+	// tokenReviewCacheSize is the number of TokenReview results kept.
+	tokenReviewCacheSize = 1000
+)
+
+// Authenticator authenticates the requests sent to the Brokers which require
+// it, see eventing.AuthModeAnnotationKey.
+type Authenticator struct {
+	// KubeClient creates the TokenReviews verifying ServiceAccount tokens.
+	KubeClient kubernetes.Interface
+	// JWTKey verifies the signature of JWT bearer tokens, see ParseJWTKey.
+	// Brokers in the jwt mode reject all requests if it isn't set.
+	JWTKey interface{}
+	// JWTIssuer is the issuer the JWT bearer tokens must be issued by, their
+	// issuer isn't verified if it is empty.
+	JWTIssuer string
+
+	// now returns the current time, it's overridden in tests.
+	now func() time.Time
+
+	// reviews caches the results of the TokenReviews, so that the API server
+	// isn't called for every event.
+	reviewsOnce sync.Once
+	reviews     *tokenReviewCache
+}
+
+// authError is an authentication or authorization failure, with the HTTP
+// status code the request is rejected with.
+type authError struct {
+	statusCode int
+	err        error
+}
+
+func (e *authError) Error() string {
+	return e.err.Error()
+}
+
+func unauthenticated(format string, args ...interface{}) error {
+	return &authError{statusCode: http.StatusUnauthorized, err: fmt.Errorf(format, args...)}
+}
+
+// Authenticate authenticates the request according to the configuration of
+// the Broker. It returns the status code the request must be rejected with,
+// 401 or 403, or 0 if the request is allowed. If the request couldn't be
+// authenticated, e.g. because the API server is unavailable, it returns 503.
+func (a *Authenticator) Authenticate(ctx context.Context, b *eventingv1.Broker, request *http.Request) (int, error) {
+	mode, ok := b.Annotations[eventing.AuthModeAnnotationKey]
+	if !ok {
+		return 0, nil
+	}
+
+	subject, err := a.authenticate(ctx, b, mode, request)
+	if err != nil {
+		var ae *authError
+		if errors.As(err, &ae) {
+			return ae.statusCode, err
+		}
+		return http.StatusServiceUnavailable, err
+	}
+
+	if allowed, ok := b.Annotations[eventing.AuthSubjectsAnnotationKey]; ok {
+		for _, s := range strings.Split(allowed, ",") {
+			if strings.TrimSpace(s) == subject {
+				return 0, nil
+			}
+		}
+		return http.StatusForbidden, fmt.Errorf("subject %q is not allowed to send events to the broker", subject)
+	}
+	return 0, nil
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, b *eventingv1.Broker, mode string, request *http.Request) (string, error) {
+	token := bearerToken(request)
+	if token == "" {
+		return "", unauthenticated("missing bearer token")
+	}
+	audience := b.Annotations[eventing.AuthAudienceAnnotationKey]
+
+	switch mode {
+	case eventing.AuthModeJWT:
+		// Without an audience, the tokens signed by the key for any Broker
+		// would be accepted.
+		if audience == "" {
+			return "", unauthenticated("the broker doesn't set the %s annotation", eventing.AuthAudienceAnnotationKey)
+		}
+		return a.authenticateJWT(token, audience)
+	case eventing.AuthModeTokenReview:
+		// Without an audience nor allowed subjects, any ServiceAccount token
+		// of the cluster would be accepted.
+		if _, ok := b.Annotations[eventing.AuthSubjectsAnnotationKey]; !ok && audience == "" {
+			return "", unauthenticated("the broker sets neither the %s nor the %s annotation", eventing.AuthAudienceAnnotationKey, eventing.AuthSubjectsAnnotationKey)
+		}
+		return a.authenticateTokenReview(ctx, token, audience)
+	default:
+		return "", unauthenticated("unknown authentication mode %q", mode)
+	}
+}
+
+func (a *Authenticator) authenticateJWT(token, audience string) (string, error) {
+	if a.JWTKey == nil {
+		return "", unauthenticated("no JWT key configured")
+	}
+	c, err := verifyJWT(token, a.JWTKey, a.currentTime())
+	if err != nil {
+		return "", unauthenticated("%w", err)
+	}
+	if a.JWTIssuer != "" && c.Issuer != a.JWTIssuer {
+		return "", unauthenticated("token is not issued by %q", a.JWTIssuer)
+	}
+	if !c.Audience.contains(audience) {
+		return "", unauthenticated("token audience doesn't include %q", audience)
+	}
+	return c.Subject, nil
+}
+
+// authenticateTokenReview authenticates the token with a TokenReview. The
+// results, failed ones included, are cached for tokenReviewCacheTTL, while the
+// failures to create the TokenReview are not.
+func (a *Authenticator) authenticateTokenReview(ctx context.Context, token, audience string) (string, error) {
+	a.reviewsOnce.Do(func() {
+		a.reviews = newTokenReviewCache(tokenReviewCacheSize, tokenReviewCacheTTL)
+	})
+	key := tokenReviewKey{token: sha256.Sum256([]byte(token)), audience: audience}
+	if r, ok := a.reviews.get(key, a.currentTime()); ok {
+		return r.subject, r.err
+	}
+
+	subject, err := a.reviewToken(ctx, token, audience)
+	var ae *authError
+	if err == nil || errors.As(err, &ae) {
+		a.reviews.add(key, subject, err, a.currentTime())
+	}
+	return subject, err
+}
+
+func (a *Authenticator) reviewToken(ctx context.Context, token, audience string) (string, error) {
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	if audience != "" {
+		review.Spec.Audiences = []string{audience}
+	}
+	review, err := a.KubeClient.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return "", unauthenticated("token not authenticated: %s", review.Status.Error)
+		}
+		return "", unauthenticated("token not authenticated")
+	}
+	return review.Status.User.Username, nil
+}
+
+func (a *Authenticator) currentTime() time.Time {
+	if a.now != nil {
+		return a.now()
+	}
+	return time.Now()
+}
+
+// tokenReviewCache is an LRU cache of the results of the TokenReviews, whose
+// entries expire after ttl.
+type tokenReviewCache struct {
+	capacity int
+	ttl      time.Duration
+
+	mu sync.Mutex
+	// entries holds the *tokenReviewEntry, the most recent first.
+	entries  *list.List
+	elements map[tokenReviewKey]*list.Element
+}
+
+// tokenReviewKey identifies a TokenReview, the token is hashed so that the
+// cache doesn't hold the tokens.
+type tokenReviewKey struct {
+	token    [sha256.Size]byte
+	audience string
+}
+
+type tokenReviewEntry struct {
+	key     tokenReviewKey
+	subject string
+	err     error
+	expires time.Time
+}
+
+func newTokenReviewCache(capacity int, ttl time.Duration) *tokenReviewCache {
+	return &tokenReviewCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  list.New(),
+		elements: make(map[tokenReviewKey]*list.Element),
+	}
+}
+
+// get returns the cached result of the TokenReview, if any.
+func (c *tokenReviewCache) get(key tokenReviewKey, now time.Time) (tokenReviewEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.elements[key]
+	if !ok {
+		return tokenReviewEntry{}, false
+	}
+	entry := e.Value.(*tokenReviewEntry)
+	if !now.Before(entry.expires) {
+		c.remove(e)
+		return tokenReviewEntry{}, false
+	}
+	c.entries.MoveToFront(e)
+	return *entry, true
+}
+
+// add caches the result of the TokenReview, evicting the least recently used
+// one if the cache is full.
+func (c *tokenReviewCache) add(key tokenReviewKey, subject string, err error, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.elements[key]; ok {
+		c.remove(e)
+	}
+	c.elements[key] = c.entries.PushFront(&tokenReviewEntry{key: key, subject: subject, err: err, expires: now.Add(c.ttl)})
+	if c.entries.Len() > c.capacity {
+		c.remove(c.entries.Back())
+	}
+}
+
+func (c *tokenReviewCache) remove(e *list.Element) {
+	c.entries.Remove(e)
+	delete(c.elements, e.Value.(*tokenReviewEntry).key)
+}
+
+func bearerToken(request *http.Request) string {
+	const prefix = "Bearer "
+	header := request.Header.Get("Authorization")
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultDedupCapacity is the default number of events remembered for each
+// Broker by the in-memory dedup store.
+const DefaultDedupCapacity = 10000
+
+// DedupKey identifies an event sent to a Broker.
+type DedupKey struct {
+	Namespace string
+	Broker    string
+	Source    string
+	ID        string
+}
+
+// DedupState is the state of an event in a DedupStore.
+type DedupState int
+
+const (
+	// DedupNew means the event wasn't received within the window. It is now
+	// in flight until its delivery is finished.
+	DedupNew DedupState = iota
+	// DedupInFlight means the event is being delivered, the outcome of the
+	// delivery is not known yet.
+	DedupInFlight
+	// DedupDelivered means the event was delivered within the window.
+	DedupDelivered
+)
+
+// DedupStore remembers the events sent to the Brokers, to detect duplicates.
+type DedupStore interface {
+	// Begin returns the state of the event at the given time. If the event
+	// is new, it is recorded as in flight and Finish must be called once it
+	// is delivered or failed.
+	Begin(ctx context.Context, key DedupKey, window time.Duration, now time.Time) (DedupState, error)
+	// Finish records the outcome of the delivery of an event started with
+	// Begin. A delivered event is a duplicate within the window after now,
+	// a failed one is forgotten so that its retries are forwarded.
+	Finish(ctx context.Context, key DedupKey, delivered bool, now time.Time) error
+}
+
+type dedupEntry struct {
+	source   string
+	id       string
+	received time.Time
+}
+
+type dedupKey struct {
+	source string
+	id     string
+}
+
+// dedupLRU is the events delivered to a Broker, the most recent first.
+type dedupLRU struct {
+	entries  *list.List
+	elements map[dedupKey]*list.Element
+}
+
+// LRUDedupStore is an in-memory DedupStore. It remembers at most a given
+// number of delivered events for each Broker, and forgets the least recently
+// delivered ones first.
+type LRUDedupStore struct {
+	capacity int
+
+	mu       sync.Mutex
+	lrus     map[types.NamespacedName]*dedupLRU
+	inFlight map[DedupKey]struct{}
+}
+
+var _ DedupStore = (*LRUDedupStore)(nil)
+
+// NewLRUDedupStore creates a LRUDedupStore remembering at most capacity
+// events for each Broker.
+func NewLRUDedupStore(capacity int) *LRUDedupStore {
+	return &LRUDedupStore{
+		capacity: capacity,
+		lrus:     make(map[types.NamespacedName]*dedupLRU),
+		inFlight: make(map[DedupKey]struct{}),
+	}
+}
+
+// Begin implements DedupStore.
+func (s *LRUDedupStore) Begin(_ context.Context, key DedupKey, window time.Duration, now time.Time) (DedupState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inFlight[key]; ok {
+		return DedupInFlight, nil
+	}
+
+	brokerKey := types.NamespacedName{Namespace: key.Namespace, Name: key.Broker}
+	if lru, ok := s.lrus[brokerKey]; ok {
+		// Forget the least recently delivered events if they were delivered
+		// before the window.
+		for e := lru.entries.Back(); e != nil && now.Sub(e.Value.(*dedupEntry).received) >= window; e = lru.entries.Back() {
+			lru.remove(e)
+		}
+		if lru.entries.Len() == 0 {
+			delete(s.lrus, brokerKey)
+		} else if _, ok := lru.elements[dedupKey{source: key.Source, id: key.ID}]; ok {
+			return DedupDelivered, nil
+		}
+	}
+
+	s.inFlight[key] = struct{}{}
+	return DedupNew, nil
+}
+
+// Finish implements DedupStore.
+func (s *LRUDedupStore) Finish(_ context.Context, key DedupKey, delivered bool, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inFlight, key)
+	if !delivered {
+		return nil
+	}
+
+	brokerKey := types.NamespacedName{Namespace: key.Namespace, Name: key.Broker}
+	lru, ok := s.lrus[brokerKey]
+	if !ok {
+		lru = &dedupLRU{
+			entries:  list.New(),
+			elements: make(map[dedupKey]*list.Element),
+		}
+		s.lrus[brokerKey] = lru
+	}
+
+	k := dedupKey{source: key.Source, id: key.ID}
+	if e, ok := lru.elements[k]; ok {
+		lru.remove(e)
+	}
+	lru.elements[k] = lru.entries.PushFront(&dedupEntry{source: key.Source, id: key.ID, received: now})
+	if lru.entries.Len() > s.capacity {
+		lru.remove(lru.entries.Back())
+	}
+	return nil
+}
+
+func (l *dedupLRU) remove(e *list.Element) {
+	entry := l.entries.Remove(e).(*dedupEntry)
+	delete(l.elements, dedupKey{source: entry.source, id: entry.id})
+}
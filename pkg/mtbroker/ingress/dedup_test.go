@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUDedupStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1e9, 0)
+	window := time.Minute
+	s := NewLRUDedupStore(2)
+
+	begin := func(key DedupKey, at time.Time) DedupState {
+		t.Helper()
+		state, err := s.Begin(ctx, key, window, at)
+		if err != nil {
+			t.Fatal("Begin() =", err)
+		}
+		return state
+	}
+	finish := func(key DedupKey, delivered bool, at time.Time) {
+		t.Helper()
+		if err := s.Finish(ctx, key, delivered, at); err != nil {
+			t.Fatal("Finish() =", err)
+		}
+	}
+	deliver := func(key DedupKey, at time.Time) {
+		t.Helper()
+		if state := begin(key, at); state != DedupNew {
+			t.Fatalf("Begin() = %v, want %v", state, DedupNew)
+		}
+		finish(key, true, at)
+	}
+
+	a := DedupKey{Namespace: "ns", Broker: "default", Source: "/source", ID: "a"}
+	b := DedupKey{Namespace: "ns", Broker: "default", Source: "/source", ID: "b"}
+	c := DedupKey{Namespace: "ns", Broker: "default", Source: "/source", ID: "c"}
+
+	if state := begin(a, now); state != DedupNew {
+		t.Errorf("Expected the first event to be new, got %v", state)
+	}
+	if state := begin(a, now); state != DedupInFlight {
+		t.Errorf("Expected the same event to be in flight until it is finished, got %v", state)
+	}
+	finish(a, false, now)
+	if state := begin(a, now); state != DedupNew {
+		t.Errorf("Expected a failed event to be forgotten, got %v", state)
+	}
+	finish(a, true, now)
+	if state := begin(a, now.Add(time.Second)); state != DedupDelivered {
+		t.Errorf("Expected the same event to be a duplicate within the window, got %v", state)
+	}
+	if state := begin(DedupKey{Namespace: "ns", Broker: "default", Source: "/other", ID: "a"}, now); state != DedupNew {
+		t.Errorf("Expected an event with another source not to be a duplicate, got %v", state)
+	}
+	if state := begin(DedupKey{Namespace: "ns", Broker: "other", Source: "/source", ID: "a"}, now); state != DedupNew {
+		t.Errorf("Expected an event sent to another Broker not to be a duplicate, got %v", state)
+	}
+	deliver(a, now.Add(window))
+
+	// b and c are more recently delivered than a, which is evicted.
+	deliver(b, now.Add(window))
+	deliver(c, now.Add(window))
+	if state := begin(a, now.Add(window+time.Second)); state != DedupNew {
+		t.Errorf("Expected the least recently delivered event to be forgotten beyond the capacity, got %v", state)
+	}
+	if state := begin(c, now.Add(2*window)); state != DedupNew {
+		t.Errorf("Expected the event not to be a duplicate after the window, got %v", state)
+	}
+}
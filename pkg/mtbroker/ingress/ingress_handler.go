@@ -18,9 +18,12 @@ package ingress
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,6 +40,7 @@ import (
 	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
 	"knative.dev/eventing/pkg/kncloudevents"
 	broker "knative.dev/eventing/pkg/mtbroker"
+	"knative.dev/eventing/pkg/reconciler/eventtype/discovery"
 	"knative.dev/eventing/pkg/tracing"
 	"knative.dev/eventing/pkg/utils"
 	"knative.dev/pkg/network"
@@ -58,19 +62,29 @@ type Handler struct {
 	Reporter StatsReporter
 	// BrokerLister gets broker objects
 	BrokerLister eventinglisters.BrokerLister
+	// Authenticator authenticates the requests sent to the Brokers which
+	// require it. Such requests are rejected if it isn't set.
+	Authenticator *Authenticator
+	// RateLimiter rate limits the events sent to each Broker. Events are not
+	// rate limited if it isn't set.
+	RateLimiter *RateLimiter
+	// SchemaValidator validates the events sent to the Brokers which require
+	// it. Events are not validated if it isn't set.
+	SchemaValidator *SchemaValidator
+	// EventTypeRecorder records the types of the events accepted by the
+	// Brokers which discover their EventTypes. EventTypes are not discovered
+	// if it isn't set.
+	EventTypeRecorder *discovery.Recorder
+	// DedupStore remembers the events sent to the Brokers which drop
+	// duplicates. Events are not deduplicated if it isn't set.
+	DedupStore DedupStore
+	// Spool persists the events which couldn't be sent to the channels and
+	// replays them. Such events are rejected if it isn't set.
+	Spool *Spool
 
 	Logger *zap.Logger
 }
 
-func (h *Handler) getBroker(name, namespace string) (*eventingv1.Broker, error) {
-	broker, err := h.BrokerLister.Brokers(namespace).Get(name)
-	if err != nil {
-		h.Logger.Warn("Broker getter failed")
-		return nil, err
-	}
-	return broker, nil
-}
-
 func guessChannelAddress(name, namespace, domain string) string {
 	url := url.URL{
 		Scheme: "http",
@@ -80,10 +94,9 @@ func guessChannelAddress(name, namespace, domain string) string {
 	return url.String()
 }
 
-func (h *Handler) getChannelAddress(name, namespace string) (string, error) {
-	broker, err := h.getBroker(name, namespace)
-	if err != nil {
-		return "", err
+func getChannelAddress(broker *eventingv1.Broker) (string, error) {
+	if broker == nil {
+		return "", errors.New("Broker not found")
 	}
 	if broker.Status.Annotations == nil {
 		return "", fmt.Errorf("Broker status annotations uninitialized")
@@ -96,10 +109,22 @@ func (h *Handler) getChannelAddress(name, namespace string) (string, error) {
 }
 
 func (h *Handler) Start(ctx context.Context) error {
+	if h.Spool != nil {
+		go h.Spool.Run(ctx, func(ctx context.Context, headers http.Header, event *cloudevents.Event, target string) int {
+			statusCode, _ := h.send(ctx, headers, event, target)
+			return statusCode
+		})
+	}
 	return h.Receiver.StartListen(ctx, h)
 }
 
 func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	// answer the abuse protection handshake of the webhook spec
+	if kncloudevents.IsWebhookValidationRequest(request) {
+		kncloudevents.WriteWebhookValidationResponse(writer, request)
+		return
+	}
+
 	// validate request method
 	if request.Method != http.MethodPost {
 		h.Logger.Warn("unexpected request method", zap.String("method", request.Method))
@@ -121,6 +146,40 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 
 	ctx := request.Context()
 
+	brokerNamespace := nsBrokerName[1]
+	brokerName := nsBrokerName[2]
+
+	// The Broker is looked up once for the whole request. It's nil if it
+	// doesn't exist, the events are then sent to the guessed channel address.
+	b, err := h.BrokerLister.Brokers(brokerNamespace).Get(brokerName)
+	if err != nil {
+		h.Logger.Warn("Broker getter failed", zap.String("namespace", brokerNamespace), zap.String("broker", brokerName), zap.Error(err))
+	}
+
+	if statusCode := h.authenticate(ctx, request, b); statusCode != 0 {
+		// The request couldn't be authenticated, which isn't a rejection.
+		if statusCode != http.StatusServiceUnavailable {
+			_ = h.Reporter.ReportAuthRejection(&ReportArgs{ns: brokerNamespace, broker: brokerName}, statusCode)
+		}
+		if statusCode == http.StatusUnauthorized {
+			writer.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		writer.WriteHeader(statusCode)
+		return
+	}
+
+	if ok, retryAfter := h.allow(brokerNamespace, brokerName, b); !ok {
+		_ = h.Reporter.ReportThrottled(&ReportArgs{ns: brokerNamespace, broker: brokerName})
+		writer.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		writer.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	if kncloudevents.IsBatchRequest(request) {
+		h.serveBatch(ctx, writer, request, brokerNamespace, brokerName, b)
+		return
+	}
+
 	message := cehttp.NewMessageFromHttpRequest(request)
 	defer message.Finish(nil)
 
@@ -131,8 +190,58 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	brokerNamespace := nsBrokerName[1]
-	brokerName := nsBrokerName[2]
+	statusCode, err := h.handleEvent(ctx, request.Header, event, brokerNamespace, brokerName, b)
+	if err != nil {
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writer.WriteHeader(statusCode)
+		_, _ = writer.Write([]byte(err.Error()))
+		return
+	}
+	writer.WriteHeader(statusCode)
+}
+
+// serveBatch sends each event of a batch to the Broker, and responds with the
+// outcome of each of them.
+func (h *Handler) serveBatch(ctx context.Context, writer http.ResponseWriter, request *http.Request, brokerNamespace, brokerName string, b *eventingv1.Broker) {
+	events, errs, err := kncloudevents.ReadBatch(writer, request)
+	if err != nil {
+		h.Logger.Warn("failed to extract events from batch request", zap.Error(err))
+		if err == kncloudevents.ErrBatchTooLarge {
+			writer.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else {
+			writer.WriteHeader(http.StatusBadRequest)
+		}
+		return
+	}
+
+	results := make([]kncloudevents.BatchResult, len(events))
+	for i, event := range events {
+		if event == nil {
+			results[i] = kncloudevents.BatchResult{Status: http.StatusBadRequest, Error: errs[i].Error()}
+			continue
+		}
+		results[i].ID = event.ID()
+		// The request itself accounted for the first event.
+		if i > 0 {
+			if ok, _ := h.allow(brokerNamespace, brokerName, b); !ok {
+				_ = h.Reporter.ReportThrottled(&ReportArgs{ns: brokerNamespace, broker: brokerName})
+				results[i].Status = http.StatusTooManyRequests
+				results[i].Error = "rate limit exceeded"
+				continue
+			}
+		}
+		results[i].Status, err = h.handleEvent(ctx, request.Header, event, brokerNamespace, brokerName, b)
+		if err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+	kncloudevents.WriteBatchResponse(writer, results)
+}
+
+// handleEvent sends the event to the Broker and reports it. It returns an
+// error describing why the event is invalid if it doesn't conform to the
+// schema of its EventType. The Broker is nil if it doesn't exist.
+func (h *Handler) handleEvent(ctx context.Context, headers http.Header, event *cloudevents.Event, brokerNamespace, brokerName string, b *eventingv1.Broker) (int, error) {
 	brokerNamespacedName := types.NamespacedName{
 		Name:      brokerName,
 		Namespace: brokerNamespace,
@@ -157,36 +266,191 @@ func (h *Handler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		eventType: event.Type(),
 	}
 
-	statusCode, dispatchTime := h.receive(ctx, request.Header, event, brokerNamespace, brokerName)
+	// Only accepted events are duplicates, the retries of the rejected ones
+	// must be forwarded.
+	delivered := false
+	if window := h.dedupWindow(b); window > 0 {
+		key := DedupKey{Namespace: brokerNamespace, Broker: brokerName, Source: event.Source(), ID: event.ID()}
+		state, err := h.DedupStore.Begin(ctx, key, window, time.Now())
+		switch {
+		case err != nil:
+			h.Logger.Warn("Failed to look up duplicate events, forwarding the event", zap.Error(err))
+		case state == DedupDelivered:
+			h.Logger.Debug("dropping duplicate event", zap.String("event.source", event.Source()), zap.String("event.id", event.ID()))
+			_ = h.Reporter.ReportDuplicate(reporterArgs)
+			return http.StatusAccepted, nil
+		case state == DedupInFlight:
+			// The outcome of the delivery of the original event isn't known
+			// yet, the sender has to retry.
+			h.Logger.Debug("rejecting duplicate of an event in flight", zap.String("event.source", event.Source()), zap.String("event.id", event.ID()))
+			return http.StatusServiceUnavailable, errors.New("the event is already being delivered")
+		default:
+			// The outcome is recorded even if the delivery panics, so that
+			// the event doesn't stay in flight forever.
+			defer func() {
+				if err := h.DedupStore.Finish(ctx, key, delivered, time.Now()); err != nil {
+					h.Logger.Warn("Failed to record the outcome of the event", zap.Error(err))
+				}
+			}()
+		}
+	}
+
+	statusCode, dispatchTime, err := h.receive(ctx, headers, event, brokerNamespace, brokerName, b)
+	delivered = statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
 	if dispatchTime > noDuration {
 		_ = h.Reporter.ReportEventDispatchTime(reporterArgs, statusCode, dispatchTime)
 	}
 	_ = h.Reporter.ReportEventCount(reporterArgs, statusCode)
 
-	writer.WriteHeader(statusCode)
+	if err != nil {
+		var sve *SchemaValidationError
+		if errors.As(err, &sve) {
+			_ = h.Reporter.ReportSchemaValidationFailure(reporterArgs)
+		}
+		return statusCode, err
+	}
+	if delivered {
+		h.recordEventType(event, b)
+	}
+	return statusCode, nil
+}
+
+// authenticate authenticates the request if the Broker requires it, and
+// returns the status code the request must be rejected with, or 0.
+func (h *Handler) authenticate(ctx context.Context, request *http.Request, b *eventingv1.Broker) int {
+	if b == nil {
+		// Requests to unknown Brokers are handled as before, nothing is
+		// protected by them.
+		return 0
+	}
+	if _, ok := b.Annotations[eventing.AuthModeAnnotationKey]; !ok {
+		return 0
+	}
+	if h.Authenticator == nil {
+		h.Logger.Warn("Rejecting request to a Broker requiring authentication, no authenticator configured",
+			zap.String("namespace", b.Namespace), zap.String("broker", b.Name))
+		return http.StatusUnauthorized
+	}
+	statusCode, err := h.Authenticator.Authenticate(ctx, b, request)
+	if err != nil {
+		h.Logger.Info("Rejecting request", zap.String("namespace", b.Namespace),
+			zap.String("broker", b.Name), zap.Int("status", statusCode), zap.Error(err))
+	}
+	return statusCode
+}
+
+// allow applies the rate limit of the Broker. Events to unknown Brokers are
+// not limited, they are rejected later on, and their bucket is dropped so
+// that made up or deleted Brokers don't hold one.
+func (h *Handler) allow(brokerNamespace, brokerName string, b *eventingv1.Broker) (bool, time.Duration) {
+	if h.RateLimiter == nil {
+		return true, 0
+	}
+	if b == nil {
+		h.RateLimiter.Forget(brokerNamespace, brokerName)
+		return true, 0
+	}
+	return h.RateLimiter.Allow(brokerNamespace, brokerName, time.Now())
 }
 
-func (h *Handler) receive(ctx context.Context, headers http.Header, event *cloudevents.Event, brokerNamespace, brokerName string) (int, time.Duration) {
+// receive sends the event to the channel of the Broker. It returns a
+// *SchemaValidationError if the event doesn't conform to the schema of its
+// EventType.
+func (h *Handler) receive(ctx context.Context, headers http.Header, event *cloudevents.Event, brokerNamespace, brokerName string, b *eventingv1.Broker) (int, time.Duration, error) {
 
 	// Setting the extension as a string as the CloudEvents sdk does not support non-string extensions.
 	event.SetExtension(broker.EventArrivalTime, cloudevents.Timestamp{Time: time.Now()})
-	if h.Defaulter != nil {
-		newEvent := h.Defaulter(ctx, *event)
+	if defaulter := h.defaulter(b); defaulter != nil {
+		newEvent := defaulter(ctx, *event)
 		event = &newEvent
 	}
 
 	if ttl, err := broker.GetTTL(event.Context); err != nil || ttl <= 0 {
 		h.Logger.Debug("dropping event based on TTL status.", zap.Int32("TTL", ttl), zap.String("event.id", event.ID()), zap.Error(err))
-		return http.StatusBadRequest, noDuration
+		return http.StatusBadRequest, noDuration, nil
 	}
 
-	channelAddress, err := h.getChannelAddress(brokerName, brokerNamespace)
+	if err := h.validateSchema(event, b); err != nil {
+		h.Logger.Debug("rejecting event not conforming to its schema", zap.String("event.id", event.ID()), zap.Error(err))
+		return http.StatusBadRequest, noDuration, err
+	}
+
+	channelAddress, err := getChannelAddress(b)
 	if err != nil {
 		h.Logger.Warn("Failed to get channel address, falling back on guess", zap.Error(err))
 		channelAddress = guessChannelAddress(brokerName, brokerNamespace, network.GetClusterDomainName())
 	}
 
-	return h.send(ctx, headers, event, channelAddress)
+	// Events sent to a channel with spooled events are spooled behind them
+	// to preserve their order.
+	if h.Spool != nil && h.Spool.Pending(channelAddress) {
+		return h.spool(headers, event, channelAddress, http.StatusServiceUnavailable), noDuration, nil
+	}
+
+	statusCode, dispatchTime := h.send(ctx, headers, event, channelAddress)
+	if h.Spool != nil && isSpoolable(statusCode) {
+		return h.spool(headers, event, channelAddress, statusCode), dispatchTime, nil
+	}
+	return statusCode, dispatchTime, nil
+}
+
+// spool durably writes the event to the spool, and returns the status code of
+// the response: 202 if the event is spooled, or the status code of the failure
+// to send it otherwise.
+func (h *Handler) spool(headers http.Header, event *cloudevents.Event, target string, statusCode int) int {
+	if err := h.Spool.Add(utils.PassThroughHeaders(headers), event, target, time.Now()); err != nil {
+		h.Logger.Warn("Failed to spool event", zap.String("event.id", event.ID()), zap.Int("status", statusCode), zap.Error(err))
+		return statusCode
+	}
+	return http.StatusAccepted
+}
+
+// defaulter returns the defaulter of the events sent to the Broker. If the
+// Broker has a default TTL, it defaults the TTL of the events to it instead.
+func (h *Handler) defaulter(b *eventingv1.Broker) client.EventDefaulter {
+	if b == nil {
+		return h.Defaulter
+	}
+	ttl, err := strconv.ParseInt(b.Annotations[eventing.DefaultTTLAnnotationKey], 10, 32)
+	if err != nil || ttl <= 0 {
+		return h.Defaulter
+	}
+	return broker.TTLDefaulter(h.Logger, int32(ttl))
+}
+
+// validateSchema validates the event against the schema of its EventType if
+// the Broker requires it.
+func (h *Handler) validateSchema(event *cloudevents.Event, b *eventingv1.Broker) error {
+	if h.SchemaValidator == nil || b == nil || b.Annotations[eventing.SchemaValidationAnnotationKey] != "true" {
+		return nil
+	}
+	return h.SchemaValidator.Validate(event, b.Namespace, b.Name)
+}
+
+// dedupWindow returns the window within which the Broker drops duplicate
+// events, or 0 if it doesn't.
+func (h *Handler) dedupWindow(b *eventingv1.Broker) time.Duration {
+	if h.DedupStore == nil || b == nil {
+		return 0
+	}
+	window, ok := b.Annotations[eventing.DedupWindowAnnotationKey]
+	if !ok {
+		return 0
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// recordEventType records the type of the event if the Broker discovers its
+// EventTypes.
+func (h *Handler) recordEventType(event *cloudevents.Event, b *eventingv1.Broker) {
+	if h.EventTypeRecorder == nil || b == nil || b.Annotations[eventing.EventTypeDiscoveryAnnotationKey] != "true" {
+		return
+	}
+	h.EventTypeRecorder.Record(b.Namespace, b.Name, event, time.Now())
 }
 
 func (h *Handler) send(ctx context.Context, headers http.Header, event *cloudevents.Event, target string) (int, time.Duration) {
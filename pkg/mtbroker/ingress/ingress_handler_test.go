@@ -18,7 +18,10 @@ package ingress
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
+	"io/ioutil"
 	nethttp "net/http"
 	"net/http/httptest"
 	"testing"
@@ -34,9 +37,12 @@ import (
 
 	"knative.dev/eventing/pkg/apis/eventing"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
 	"knative.dev/eventing/pkg/kncloudevents"
 	broker "knative.dev/eventing/pkg/mtbroker"
 	reconcilertestingv1 "knative.dev/eventing/pkg/reconciler/testing/v1"
+	reconcilertestingv1beta1 "knative.dev/eventing/pkg/reconciler/testing/v1beta1"
 )
 
 const (
@@ -60,6 +66,13 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		reporter        StatsReporter
 		defaulter       client.EventDefaulter
 		brokers         []*eventingv1.Broker
+		authenticator   *Authenticator
+		rateLimiter     *RateLimiter
+		eventTypes      []runtime.Object
+		dedupStore      DedupStore
+		spool           bool
+		spoolDepth      int
+		expectedBody    string
 	}{
 		{
 			name:       "invalid method PATCH",
@@ -111,6 +124,16 @@ func TestHandler_ServeHTTP(t *testing.T) {
 			reporter:   &mockReporter{},
 			defaulter:  broker.TTLDefaulter(logger, 100),
 		},
+		{
+			name:       "webhook validation",
+			method:     nethttp.MethodOptions,
+			uri:        "/ns/name",
+			headers:    nethttp.Header{nethttp.CanonicalHeaderKey(kncloudevents.WebhookRequestOriginHeader): []string{"eventemitter.example.com"}},
+			statusCode: nethttp.StatusOK,
+			handler:    handler(),
+			reporter:   &mockReporter{},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+		},
 		{
 			name:       "valid (happy path)",
 			method:     nethttp.MethodPost,
@@ -184,6 +207,361 @@ func TestHandler_ServeHTTP(t *testing.T) {
 				makeBroker("name", "ns"),
 			},
 		},
+		{
+			name:       "authentication required, missing token",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: nethttp.StatusUnauthorized,
+			handler:    handler(),
+			reporter:   &mockReporter{AuthRejectionStatusCode: nethttp.StatusUnauthorized},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithAuth("name", "ns", eventing.AuthModeJWT, "producer"),
+			},
+			authenticator: &Authenticator{JWTKey: testJWTKey},
+		},
+		{
+			name:   "authentication required, valid token",
+			method: nethttp.MethodPost,
+			uri:    "/ns/name",
+			body:   getValidEvent(),
+			headers: nethttp.Header{
+				"Authorization":    []string{"Bearer " + signHS256(t, testJWTKey, `{"sub":"producer","aud":"broker","exp":4102444800}`)},
+				cehttp.ContentType: []string{event.ApplicationCloudEventsJSON},
+			},
+			statusCode: senderResponseStatusCode,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithAuth("name", "ns", eventing.AuthModeJWT, "producer"),
+			},
+			authenticator: &Authenticator{JWTKey: testJWTKey},
+		},
+		{
+			name:   "authentication required, subject not allowed",
+			method: nethttp.MethodPost,
+			uri:    "/ns/name",
+			body:   getValidEvent(),
+			headers: nethttp.Header{
+				"Authorization":    []string{"Bearer " + signHS256(t, testJWTKey, `{"sub":"intruder","aud":"broker","exp":4102444800}`)},
+				cehttp.ContentType: []string{event.ApplicationCloudEventsJSON},
+			},
+			statusCode: nethttp.StatusForbidden,
+			handler:    handler(),
+			reporter:   &mockReporter{AuthRejectionStatusCode: nethttp.StatusForbidden},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithAuth("name", "ns", eventing.AuthModeJWT, "producer"),
+			},
+			authenticator: &Authenticator{JWTKey: testJWTKey},
+		},
+		{
+			name:   "authentication unavailable",
+			method: nethttp.MethodPost,
+			uri:    "/ns/name",
+			body:   getValidEvent(),
+			headers: nethttp.Header{
+				"Authorization":    []string{"Bearer token"},
+				cehttp.ContentType: []string{event.ApplicationCloudEventsJSON},
+			},
+			statusCode: nethttp.StatusServiceUnavailable,
+			handler:    handler(),
+			// The failure to review the token isn't an authentication rejection.
+			reporter:  &mockReporter{},
+			defaulter: broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithAuth("name", "ns", eventing.AuthModeTokenReview, "producer"),
+			},
+			authenticator: &Authenticator{KubeClient: failingTokenReviewClient()},
+		},
+		{
+			name:   "authentication required, no authenticator",
+			method: nethttp.MethodPost,
+			uri:    "/ns/name",
+			body:   getValidEvent(),
+			headers: nethttp.Header{
+				"Authorization":    []string{"Bearer " + signHS256(t, testJWTKey, `{"sub":"producer","aud":"broker","exp":4102444800}`)},
+				cehttp.ContentType: []string{event.ApplicationCloudEventsJSON},
+			},
+			statusCode: nethttp.StatusUnauthorized,
+			handler:    handler(),
+			reporter:   &mockReporter{AuthRejectionStatusCode: nethttp.StatusUnauthorized},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithAuth("name", "ns", eventing.AuthModeJWT, "producer"),
+			},
+		},
+		{
+			name:       "rate limited",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: nethttp.StatusTooManyRequests,
+			handler:    handler(),
+			reporter:   &mockReporter{Throttled: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			rateLimiter: exhaustedRateLimiter("ns", "name"),
+		},
+		{
+			name:       "rate limited, authentication first",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: nethttp.StatusUnauthorized,
+			handler:    handler(),
+			reporter:   &mockReporter{AuthRejectionStatusCode: nethttp.StatusUnauthorized},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithAuth("name", "ns", eventing.AuthModeJWT, "producer"),
+			},
+			authenticator: &Authenticator{JWTKey: testJWTKey},
+			rateLimiter:   exhaustedRateLimiter("ns", "name"),
+		},
+		{
+			name:       "rate limited, other broker",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: senderResponseStatusCode,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			rateLimiter: exhaustedRateLimiter("ns", "other"),
+		},
+		{
+			name:       "schema validation, valid data",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getEventWithData(`{"customer":{"tier":"gold"}}`),
+			statusCode: senderResponseStatusCode,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithSchemaValidation("name", "ns"),
+			},
+			eventTypes: []runtime.Object{
+				makeEventTypeWithSchema("name", "ns"),
+			},
+		},
+		{
+			name:       "schema validation, invalid data",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getEventWithData(`{"customer":{"tier":"bronze"}}`),
+			statusCode: nethttp.StatusBadRequest,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: nethttp.StatusBadRequest, SchemaValidationFailure: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithSchemaValidation("name", "ns"),
+			},
+			eventTypes: []runtime.Object{
+				makeEventTypeWithSchema("name", "ns"),
+			},
+			expectedBody: `the data of the event doesn't conform to the schema of EventType orders: /customer/tier: value must be one of ["gold","silver"]`,
+		},
+		{
+			name:       "schema validation disabled",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getEventWithData(`{"customer":{"tier":"bronze"}}`),
+			statusCode: senderResponseStatusCode,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			eventTypes: []runtime.Object{
+				makeEventTypeWithSchema("name", "ns"),
+			},
+		},
+		{
+			name:       "batch",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getBatch(`{"specversion": "1.0", "id": "2", "type": "type", "source": "source"}`),
+			headers:    nethttp.Header{cehttp.ContentType: []string{event.ApplicationCloudEventsBatchJSON}},
+			statusCode: senderResponseStatusCode,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			expectedBody: `{"results":[{"id":"1","status":202},{"id":"2","status":202}]}`,
+		},
+		{
+			name:       "batch, invalid event",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getBatch(`{"specversion": "1.0", "id": "2", "source": "source"}`),
+			headers:    nethttp.Header{cehttp.ContentType: []string{event.ApplicationCloudEventsBatchJSON}},
+			statusCode: nethttp.StatusMultiStatus,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			expectedBody: `{"results":[{"id":"1","status":202},{"status":400,"error":"type: MUST be a non-empty string\n"}]}`,
+		},
+		{
+			name:       "batch, rate limited",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getBatch(`{"specversion": "1.0", "id": "2", "type": "type", "source": "source"}`),
+			headers:    nethttp.Header{cehttp.ContentType: []string{event.ApplicationCloudEventsBatchJSON}},
+			statusCode: nethttp.StatusMultiStatus,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true, Throttled: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			rateLimiter:  exhaustedRateLimiter("ns", "other"),
+			expectedBody: `{"results":[{"id":"1","status":202},{"id":"2","status":429,"error":"rate limit exceeded"}]}`,
+		},
+		{
+			name:       "empty batch",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       bytes.NewBufferString(`[]`),
+			headers:    nethttp.Header{cehttp.ContentType: []string{event.ApplicationCloudEventsBatchJSON}},
+			statusCode: nethttp.StatusBadRequest,
+			handler:    handler(),
+			reporter:   &mockReporter{},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+		},
+		{
+			name:       "dedup, new event",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: senderResponseStatusCode,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithDedup("name", "ns"),
+			},
+			dedupStore: NewLRUDedupStore(DefaultDedupCapacity),
+		},
+		{
+			name:       "dedup, duplicate event",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: nethttp.StatusAccepted,
+			handler:    handler(),
+			reporter:   &mockReporter{Duplicate: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithDedup("name", "ns"),
+			},
+			dedupStore: dedupStoreWith(DedupKey{Namespace: "ns", Broker: "name", Source: "source", ID: "1234"}),
+		},
+		{
+			name:       "dedup, duplicate of an event in flight",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: nethttp.StatusServiceUnavailable,
+			handler:    handler(),
+			reporter:   &mockReporter{},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithDedup("name", "ns"),
+			},
+			dedupStore: dedupStoreInFlight(DedupKey{Namespace: "ns", Broker: "name", Source: "source", ID: "1234"}),
+		},
+		{
+			name:       "dedup disabled",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: senderResponseStatusCode,
+			handler:    handler(),
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			dedupStore: dedupStoreWith(DedupKey{Namespace: "ns", Broker: "name", Source: "source", ID: "1234"}),
+		},
+		{
+			name:   "broker default TTL",
+			method: nethttp.MethodPost,
+			uri:    "/ns/name",
+			body:   getValidEvent(),
+			headers: nethttp.Header{
+				cehttp.ContentType: []string{event.ApplicationCloudEventsJSON},
+			},
+			expectedHeaders: nethttp.Header{
+				"Ce-Knativebrokerttl": []string{"5"},
+			},
+			statusCode: senderResponseStatusCode,
+			handler:    &svc{},
+			reporter:   &mockReporter{StatusCode: senderResponseStatusCode, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBrokerWithDefaultTTL("name", "ns", "5"),
+			},
+		},
+		{
+			name:       "channel unavailable",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: nethttp.StatusServiceUnavailable,
+			handler:    statusHandler(nethttp.StatusServiceUnavailable),
+			reporter:   &mockReporter{StatusCode: nethttp.StatusServiceUnavailable, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+		},
+		{
+			name:       "channel unavailable, event spooled",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: nethttp.StatusAccepted,
+			handler:    statusHandler(nethttp.StatusServiceUnavailable),
+			reporter:   &mockReporter{StatusCode: nethttp.StatusAccepted, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			spool:      true,
+			spoolDepth: 1,
+		},
+		{
+			name:       "event rejected by channel, not spooled",
+			method:     nethttp.MethodPost,
+			uri:        "/ns/name",
+			body:       getValidEvent(),
+			statusCode: nethttp.StatusBadRequest,
+			handler:    statusHandler(nethttp.StatusBadRequest),
+			reporter:   &mockReporter{StatusCode: nethttp.StatusBadRequest, EventDispatchTimeReported: true},
+			defaulter:  broker.TTLDefaulter(logger, 100),
+			brokers: []*eventingv1.Broker{
+				makeBroker("name", "ns"),
+			},
+			spool: true,
+		},
 	}
 
 	for _, tc := range tt {
@@ -215,12 +593,23 @@ func TestHandler_ServeHTTP(t *testing.T) {
 			listers := reconcilertestingv1.NewListers(annotatedBrokers)
 			sender, _ := kncloudevents.NewHTTPMessageSenderWithTarget("")
 			h := &Handler{
-				Sender:       sender,
-				Defaulter:    tc.defaulter,
-				Reporter:     &mockReporter{},
-				Logger:       logger,
-				BrokerLister: listers.GetBrokerLister(),
+				Sender:        sender,
+				Defaulter:     tc.defaulter,
+				Reporter:      &mockReporter{},
+				Logger:        logger,
+				BrokerLister:  listers.GetBrokerLister(),
+				Authenticator: tc.authenticator,
+				RateLimiter:   tc.rateLimiter,
+				DedupStore:    tc.dedupStore,
+			}
+			if tc.spool {
+				spool, err := NewSpool(t.TempDir(), 10, &mockReporter{}, logger)
+				if err != nil {
+					t.Fatal("NewSpool() =", err)
+				}
+				h.Spool = spool
 			}
+			h.SchemaValidator = NewSchemaValidator(eventTypeIndexer(tc.eventTypes), logger)
 
 			h.ServeHTTP(recorder, request)
 
@@ -228,6 +617,15 @@ func TestHandler_ServeHTTP(t *testing.T) {
 			if result.StatusCode != tc.statusCode {
 				t.Errorf("expected status code %d got %d", tc.statusCode, result.StatusCode)
 			}
+			if tc.expectedBody != "" {
+				body, _ := ioutil.ReadAll(result.Body)
+				if diff := cmp.Diff(tc.expectedBody, string(body)); diff != "" {
+					t.Error("Unexpected body (-want +got):", diff)
+				}
+			}
+			if tc.statusCode == nethttp.StatusTooManyRequests && result.Header.Get("Retry-After") == "" {
+				t.Error("expected a Retry-After header")
+			}
 
 			if svc, ok := tc.handler.(*svc); ok {
 				for k, expValue := range tc.expectedHeaders {
@@ -239,6 +637,12 @@ func TestHandler_ServeHTTP(t *testing.T) {
 				}
 			}
 
+			if h.Spool != nil {
+				if depth := len(h.Spool.entries); depth != tc.spoolDepth {
+					t.Errorf("expected %d spooled events got %d", tc.spoolDepth, depth)
+				}
+			}
+
 			if diff := cmp.Diff(tc.reporter, h.Reporter); diff != "" {
 				t.Errorf("expected reporter state %+v got %+v - diff %s", tc.reporter, h.Reporter, diff)
 			}
@@ -246,6 +650,99 @@ func TestHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestHandler_GetsTheBrokerOnce(t *testing.T) {
+	s := httptest.NewServer(handler())
+	defer s.Close()
+
+	b := makeBroker("name", "ns")
+	b.Annotations = map[string]string{
+		eventing.DefaultTTLAnnotationKey:         "5",
+		eventing.SchemaValidationAnnotationKey:   "true",
+		eventing.DedupWindowAnnotationKey:        "10m",
+		eventing.EventTypeDiscoveryAnnotationKey: "true",
+	}
+	b.Status.Annotations = map[string]string{
+		eventing.BrokerChannelAddressStatusAnnotationKey: s.URL,
+	}
+	listers := reconcilertestingv1.NewListers([]runtime.Object{b})
+	lister := &countingBrokerLister{BrokerLister: listers.GetBrokerLister()}
+	sender, _ := kncloudevents.NewHTTPMessageSenderWithTarget("")
+	logger := zap.NewNop()
+	h := &Handler{
+		Sender:          sender,
+		Defaulter:       broker.TTLDefaulter(logger, 100),
+		Reporter:        &mockReporter{},
+		Logger:          logger,
+		BrokerLister:    lister,
+		RateLimiter:     NewRateLimiter(),
+		SchemaValidator: NewSchemaValidator(eventTypeIndexer(nil), logger),
+		DedupStore:      NewLRUDedupStore(10),
+	}
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(nethttp.MethodPost, "/ns/name", getValidEvent())
+	request.Header.Add(cehttp.ContentType, event.ApplicationCloudEventsJSON)
+	h.ServeHTTP(recorder, request)
+
+	if got := recorder.Result().StatusCode; got != senderResponseStatusCode {
+		t.Errorf("expected status code %d got %d", senderResponseStatusCode, got)
+	}
+	if lister.gets != 1 {
+		t.Errorf("expected the Broker to be fetched once, got %d", lister.gets)
+	}
+}
+
+func TestHandler_DedupFinishedOnPanic(t *testing.T) {
+	b := makeBrokerWithDedup("name", "ns")
+	store := NewLRUDedupStore(10)
+	h := &Handler{
+		Defaulter: func(context.Context, event.Event) event.Event {
+			panic("test induced panic")
+		},
+		Reporter:   &mockReporter{},
+		Logger:     zap.NewNop(),
+		DedupStore: store,
+	}
+	e := event.New()
+	e.SetID("1234")
+	e.SetSource("source")
+	e.SetType("type")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected handleEvent to panic")
+			}
+		}()
+		_, _ = h.handleEvent(context.Background(), nethttp.Header{}, &e, "ns", "name", b)
+	}()
+
+	key := DedupKey{Namespace: "ns", Broker: "name", Source: "source", ID: "1234"}
+	if state, err := store.Begin(context.Background(), key, time.Minute, time.Now()); err != nil || state != DedupNew {
+		t.Errorf("Begin() = %v, %v, want the event to be delivered again", state, err)
+	}
+}
+
+// countingBrokerLister counts the Brokers fetched from the wrapped lister.
+type countingBrokerLister struct {
+	eventinglisters.BrokerLister
+	gets int
+}
+
+func (l *countingBrokerLister) Brokers(namespace string) eventinglisters.BrokerNamespaceLister {
+	return &countingBrokerNamespaceLister{BrokerNamespaceLister: l.BrokerLister.Brokers(namespace), lister: l}
+}
+
+type countingBrokerNamespaceLister struct {
+	eventinglisters.BrokerNamespaceLister
+	lister *countingBrokerLister
+}
+
+func (l *countingBrokerNamespaceLister) Get(name string) (*eventingv1.Broker, error) {
+	l.lister.gets++
+	return l.BrokerNamespaceLister.Get(name)
+}
+
 type svc struct {
 	receivedHeaders nethttp.Header
 }
@@ -261,9 +758,20 @@ func handler() nethttp.Handler {
 	})
 }
 
+func statusHandler(statusCode int) nethttp.Handler {
+	return nethttp.HandlerFunc(func(writer nethttp.ResponseWriter, request *nethttp.Request) {
+		writer.WriteHeader(statusCode)
+	})
+}
+
 type mockReporter struct {
 	StatusCode                int
 	EventDispatchTimeReported bool
+	AuthRejectionStatusCode   int
+	Throttled                 bool
+	SchemaValidationFailure   bool
+	Duplicate                 bool
+	SpoolDepth                int
 }
 
 func (r *mockReporter) ReportEventCount(_ *ReportArgs, responseCode int) error {
@@ -276,6 +784,31 @@ func (r *mockReporter) ReportEventDispatchTime(_ *ReportArgs, _ int, _ time.Dura
 	return nil
 }
 
+func (r *mockReporter) ReportAuthRejection(_ *ReportArgs, responseCode int) error {
+	r.AuthRejectionStatusCode = responseCode
+	return nil
+}
+
+func (r *mockReporter) ReportThrottled(_ *ReportArgs) error {
+	r.Throttled = true
+	return nil
+}
+
+func (r *mockReporter) ReportSchemaValidationFailure(_ *ReportArgs) error {
+	r.SchemaValidationFailure = true
+	return nil
+}
+
+func (r *mockReporter) ReportDuplicate(_ *ReportArgs) error {
+	r.Duplicate = true
+	return nil
+}
+
+func (r *mockReporter) ReportSpool(depth int, _ time.Duration) error {
+	r.SpoolDepth = depth
+	return nil
+}
+
 func getValidEvent() io.Reader {
 	e := event.New()
 	e.SetType("type")
@@ -285,6 +818,21 @@ func getValidEvent() io.Reader {
 	return bytes.NewBuffer(b)
 }
 
+func getEventWithData(data string) io.Reader {
+	e := event.New()
+	e.SetType("type")
+	e.SetSource("source")
+	e.SetID("1234")
+	_ = e.SetData(event.ApplicationJSON, json.RawMessage(data))
+	b, _ := e.MarshalJSON()
+	return bytes.NewBuffer(b)
+}
+
+// getBatch returns a batch of a valid event followed by the given one.
+func getBatch(e string) io.Reader {
+	return bytes.NewBufferString(`[{"specversion": "1.0", "id": "1", "type": "type", "source": "source"},` + e + `]`)
+}
+
 func makeBroker(name, namespace string) *eventingv1.Broker {
 	return &eventingv1.Broker{
 		TypeMeta: metav1.TypeMeta{
@@ -298,3 +846,68 @@ func makeBroker(name, namespace string) *eventingv1.Broker {
 		Spec: eventingv1.BrokerSpec{},
 	}
 }
+
+func makeBrokerWithAuth(name, namespace, mode, subjects string) *eventingv1.Broker {
+	b := makeBroker(name, namespace)
+	b.Annotations = map[string]string{
+		eventing.AuthModeAnnotationKey:     mode,
+		eventing.AuthAudienceAnnotationKey: "broker",
+		eventing.AuthSubjectsAnnotationKey: subjects,
+	}
+	return b
+}
+
+// exhaustedRateLimiter returns a RateLimiter allowing one event per minute to
+// each Broker, which already accepted one event for the given Broker.
+func exhaustedRateLimiter(namespace, name string) *RateLimiter {
+	r := NewRateLimiter()
+	r.SetLimits(&RateLimits{ClusterDefault: &RateLimit{EventsPerSecond: 1.0 / 60}})
+	r.Allow(namespace, name, time.Now())
+	return r
+}
+
+func makeBrokerWithSchemaValidation(name, namespace string) *eventingv1.Broker {
+	b := makeBroker(name, namespace)
+	b.Annotations = map[string]string{
+		eventing.SchemaValidationAnnotationKey: "true",
+	}
+	return b
+}
+
+func makeBrokerWithDefaultTTL(name, namespace, ttl string) *eventingv1.Broker {
+	b := makeBroker(name, namespace)
+	b.Annotations = map[string]string{
+		eventing.DefaultTTLAnnotationKey: ttl,
+	}
+	return b
+}
+
+func makeBrokerWithDedup(name, namespace string) *eventingv1.Broker {
+	b := makeBroker(name, namespace)
+	b.Annotations = map[string]string{
+		eventing.DedupWindowAnnotationKey: "10m",
+	}
+	return b
+}
+
+// dedupStoreWith returns a DedupStore which delivered the given event.
+func dedupStoreWith(key DedupKey) DedupStore {
+	s := dedupStoreInFlight(key)
+	_ = s.Finish(context.Background(), key, true, time.Now())
+	return s
+}
+
+// dedupStoreInFlight returns a DedupStore delivering the given event.
+func dedupStoreInFlight(key DedupKey) *LRUDedupStore {
+	s := NewLRUDedupStore(DefaultDedupCapacity)
+	_, _ = s.Begin(context.Background(), key, time.Minute, time.Now())
+	return s
+}
+
+func makeEventTypeWithSchema(brokerName, namespace string) *eventingv1beta1.EventType {
+	return reconcilertestingv1beta1.NewEventType("orders", namespace,
+		reconcilertestingv1beta1.WithEventTypeType("type"),
+		reconcilertestingv1beta1.WithEventTypeBroker(brokerName),
+		reconcilertestingv1beta1.WithEventTypeSchemaData(`{"type": "object", "properties": {"customer": {"properties": {"tier": {"enum": ["gold", "silver"]}}}}}`),
+	)
+}
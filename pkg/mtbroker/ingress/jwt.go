@@ -0,0 +1,236 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// minRSAKeyBits is the minimum size of the RSA keys verifying RS256 tokens.
+const minRSAKeyBits = 2048
+
+// ParseJWTKey parses the key verifying the signature of JWT bearer tokens.
+// PEM encoded RSA and ECDSA P-256 public keys verify RS256 and ES256 tokens
+// respectively, any other content is used as the secret of HS256 tokens.
+func ParseJWTKey(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		secret := bytes.TrimSpace(data)
+		if len(secret) == 0 {
+			return nil, errors.New("empty JWT key")
+		}
+		return secret, nil
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPublicKey(key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPublicKey(key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// checkPublicKey checks that the public key can verify the tokens of its
+// algorithm, see keyAlgorithm.
+func checkPublicKey(key interface{}) error {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return fmt.Errorf("the RSA key is %d bits, at least %d are required", k.N.BitLen(), minRSAKeyBits)
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return fmt.Errorf("the ECDSA key is on the %s curve, ES256 requires P-256", k.Curve.Params().Name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// keyAlgorithm returns the only algorithm of the tokens the key verifies, so
+// that the algorithm of the token header can't make the key be used in
+// another way, e.g. an RSA public key as an HMAC secret.
+func keyAlgorithm(key interface{}) (string, error) {
+	switch key.(type) {
+	case []byte:
+		return "HS256", nil
+	case *rsa.PublicKey:
+		return "RS256", nil
+	case *ecdsa.PublicKey:
+		return "ES256", nil
+	default:
+		return "", fmt.Errorf("unsupported JWT key type %T", key)
+	}
+}
+
+// claims are the registered JWT claims the ingress verifies.
+type claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+}
+
+// audience is the JWT audience claim, which is either a string or an array
+// of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	var l []string
+	if err := json.Unmarshal(b, &l); err != nil {
+		return err
+	}
+	*a = l
+	return nil
+}
+
+func (a audience) contains(aud string) bool {
+	for _, s := range a {
+		if s == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyJWT verifies the signature and the time validity of the token, and
+// returns its claims. Tokens must expire.
+func verifyJWT(token string, key interface{}, now time.Time) (*claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+	var header struct {
+		Algorithm string   `json:"alg"`
+		Critical  []string `json:"crit"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	algorithm, err := keyAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+	if header.Algorithm != algorithm {
+		return nil, fmt.Errorf("unsupported token algorithm %q, the key only verifies %s tokens", header.Algorithm, algorithm)
+	}
+	// None of the extensions are supported, see RFC 7515 section 4.1.11.
+	if len(header.Critical) != 0 {
+		return nil, fmt.Errorf("unsupported critical token header parameters %v", header.Critical)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	if err := verifySignature(parts[0]+"."+parts[1], signature, key); err != nil {
+		return nil, err
+	}
+
+	c := &claims{}
+	if err := decodeSegment(parts[1], c); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if c.ExpiresAt == nil {
+		return nil, errors.New("token has no expiration time")
+	}
+	if !now.Before(time.Unix(int64(*c.ExpiresAt), 0)) {
+		return nil, errors.New("token is expired")
+	}
+	if c.NotBefore != nil && now.Before(time.Unix(int64(*c.NotBefore), 0)) {
+		return nil, errors.New("token is not valid yet")
+	}
+	return c, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// verifySignature verifies the signature with the key, the algorithm of the
+// token must have been checked against the key already.
+func verifySignature(signed string, signature []byte, key interface{}) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		// The key may not come from ParseJWTKey, the curve determines the
+		// size of the signature.
+		if k.Curve != elliptic.P256() || len(signature) != 64 {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported JWT key type %T", key)
+}
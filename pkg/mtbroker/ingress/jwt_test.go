@@ -0,0 +1,274 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+var testJWTKey = []byte("secret")
+
+func signHS256(t *testing.T, key []byte, claims string) string {
+	t.Helper()
+	return signHS256WithHeader(t, key, `{"alg":"HS256","typ":"JWT"}`, claims)
+}
+
+func signHS256WithHeader(t *testing.T, key []byte, header, claims string) string {
+	t.Helper()
+	signed := encodeSegment(header) + "." + encodeSegment(claims)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims string) string {
+	t.Helper()
+	signed := encodeSegment(`{"alg":"RS256","typ":"JWT"}`) + "." + encodeSegment(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal("Failed to sign token:", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, claims string) string {
+	t.Helper()
+	signed := encodeSegment(`{"alg":"ES256","typ":"JWT"}`) + "." + encodeSegment(claims)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal("Failed to sign token:", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func TestVerifyJWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal("Failed to generate RSA key:", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("Failed to generate ECDSA key:", err)
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal("Failed to generate ECDSA key:", err)
+	}
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal("Failed to marshal RSA key:", err)
+	}
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rsaDER})
+	now := time.Unix(1617271200, 0)
+
+	tests := map[string]struct {
+		token   string
+		key     interface{}
+		wantSub string
+		wantErr bool
+	}{
+		"HS256": {
+			token:   signHS256(t, testJWTKey, `{"sub":"producer","exp":1617274800}`),
+			key:     testJWTKey,
+			wantSub: "producer",
+		},
+		"RS256": {
+			token:   signRS256(t, rsaKey, `{"sub":"producer","exp":1617274800}`),
+			key:     &rsaKey.PublicKey,
+			wantSub: "producer",
+		},
+		"ES256": {
+			token:   signES256(t, ecKey, `{"sub":"producer","exp":1617274800}`),
+			key:     &ecKey.PublicKey,
+			wantSub: "producer",
+		},
+		"wrong secret": {
+			token:   signHS256(t, []byte("other"), `{"sub":"producer","exp":1617274800}`),
+			key:     testJWTKey,
+			wantErr: true,
+		},
+		"algorithm not matching the key": {
+			token:   signHS256(t, testJWTKey, `{"sub":"producer","exp":1617274800}`),
+			key:     &rsaKey.PublicKey,
+			wantErr: true,
+		},
+		"unsigned": {
+			token:   encodeSegment(`{"alg":"none"}`) + "." + encodeSegment(`{"sub":"producer","exp":1617274800}`) + ".",
+			key:     testJWTKey,
+			wantErr: true,
+		},
+		"unsigned with a public key": {
+			token:   encodeSegment(`{"alg":"none"}`) + "." + encodeSegment(`{"sub":"producer","exp":1617274800}`) + ".",
+			key:     &rsaKey.PublicKey,
+			wantErr: true,
+		},
+		"HS256 signed with the RSA public key": {
+			token:   signHS256(t, rsaPEM, `{"sub":"producer","exp":1617274800}`),
+			key:     &rsaKey.PublicKey,
+			wantErr: true,
+		},
+		"HS256 signed with the ECDSA public key": {
+			token:   signHS256(t, elliptic.Marshal(elliptic.P256(), ecKey.X, ecKey.Y), `{"sub":"producer","exp":1617274800}`),
+			key:     &ecKey.PublicKey,
+			wantErr: true,
+		},
+		"RS256 with a secret": {
+			token:   signRS256(t, rsaKey, `{"sub":"producer","exp":1617274800}`),
+			key:     testJWTKey,
+			wantErr: true,
+		},
+		"RS256 with an ECDSA key": {
+			token:   signRS256(t, rsaKey, `{"sub":"producer","exp":1617274800}`),
+			key:     &ecKey.PublicKey,
+			wantErr: true,
+		},
+		"ES256 with an RSA key": {
+			token:   signES256(t, ecKey, `{"sub":"producer","exp":1617274800}`),
+			key:     &rsaKey.PublicKey,
+			wantErr: true,
+		},
+		"ES256 with a key on another curve": {
+			token:   signES256(t, ecKey, `{"sub":"producer","exp":1617274800}`),
+			key:     &p384Key.PublicKey,
+			wantErr: true,
+		},
+		"unsupported critical header": {
+			token:   signHS256WithHeader(t, testJWTKey, `{"alg":"HS256","crit":["exp"]}`, `{"sub":"producer","exp":1617274800}`),
+			key:     testJWTKey,
+			wantErr: true,
+		},
+		"expired": {
+			token:   signHS256(t, testJWTKey, `{"sub":"producer","exp":1617267600}`),
+			key:     testJWTKey,
+			wantErr: true,
+		},
+		"not valid yet": {
+			token:   signHS256(t, testJWTKey, `{"sub":"producer","exp":1617278400,"nbf":1617274800}`),
+			key:     testJWTKey,
+			wantErr: true,
+		},
+		"without expiration": {
+			token:   signHS256(t, testJWTKey, `{"sub":"producer"}`),
+			key:     testJWTKey,
+			wantErr: true,
+		},
+		"malformed": {
+			token:   "token",
+			key:     testJWTKey,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := verifyJWT(tc.token, tc.key, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("verifyJWT() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && c.Subject != tc.wantSub {
+				t.Errorf("Subject = %q, want %q", c.Subject, tc.wantSub)
+			}
+		})
+	}
+}
+
+func TestParseJWTKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal("Failed to generate RSA key:", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal("Failed to marshal RSA key:", err)
+	}
+
+	key, err := ParseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal("ParseJWTKey() =", err)
+	}
+	if _, ok := key.(*rsa.PublicKey); !ok {
+		t.Errorf("ParseJWTKey() = %T, want *rsa.PublicKey", key)
+	}
+
+	key, err = ParseJWTKey([]byte("secret\n"))
+	if err != nil {
+		t.Fatal("ParseJWTKey() =", err)
+	}
+	if s, ok := key.([]byte); !ok || string(s) != "secret" {
+		t.Errorf("ParseJWTKey() = %v, want the secret", key)
+	}
+
+	if _, err := ParseJWTKey([]byte("\n")); err == nil {
+		t.Error("Expected ParseJWTKey() to fail for an empty key")
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("Failed to generate ECDSA key:", err)
+	}
+	der, err = x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatal("Failed to marshal ECDSA key:", err)
+	}
+	key, err = ParseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal("ParseJWTKey() =", err)
+	}
+	if _, ok := key.(*ecdsa.PublicKey); !ok {
+		t.Errorf("ParseJWTKey() = %T, want *ecdsa.PublicKey", key)
+	}
+
+	// ES256 tokens are signed with P-256 keys only.
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal("Failed to generate ECDSA key:", err)
+	}
+	der, err = x509.MarshalPKIXPublicKey(&p384Key.PublicKey)
+	if err != nil {
+		t.Fatal("Failed to marshal ECDSA key:", err)
+	}
+	if _, err := ParseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})); err == nil {
+		t.Error("Expected ParseJWTKey() to fail for a P-384 key")
+	}
+
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal("Failed to generate RSA key:", err)
+	}
+	if _, err := ParseJWTKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&smallKey.PublicKey)})); err == nil {
+		t.Error("Expected ParseJWTKey() to fail for a 1024 bits RSA key")
+	}
+}
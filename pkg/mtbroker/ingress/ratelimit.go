@@ -0,0 +1,245 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RateLimitsConfigName is the name of the ConfigMap holding the rate
+	// limits of the Brokers.
+	RateLimitsConfigName = "config-br-ingress-rate-limits"
+
+	// RateLimitsKey is the name of the key holding the rate limits in the
+	// ConfigMap.
+	RateLimitsKey = "rate-limits"
+
+	// rateLimiterIdleTimeout is how long the bucket of a Broker is kept
+	// after its last event, provided it refilled in the meantime.
+	rateLimiterIdleTimeout = 10 * time.Minute
+)
+
+// RateLimits are the rate limits applied to the events sent to each Broker.
+type RateLimits struct {
+	// NamespaceDefaults are the rate limits of the Brokers of each
+	// namespace, keyed by namespace.
+	NamespaceDefaults map[string]*RateLimit `json:"namespaceDefaults,omitempty"`
+
+	// ClusterDefault is the rate limit of the Brokers of the namespaces that
+	// are not in NamespaceDefaults.
+	ClusterDefault *RateLimit `json:"clusterDefault,omitempty"`
+}
+
+// RateLimit configures the token bucket limiting the events sent to a Broker.
+type RateLimit struct {
+	// EventsPerSecond is the sustained rate of events, 0 disables rate
+	// limiting.
+	EventsPerSecond float64 `json:"eventsPerSecond,omitempty"`
+
+	// Burst is the number of events accepted at once, it defaults to
+	// EventsPerSecond rounded up.
+	Burst int `json:"burst,omitempty"`
+}
+
+// NewRateLimitsFromConfigMap creates the RateLimits from the supplied ConfigMap.
+// Brokers are not rate limited if the ConfigMap doesn't set any limit.
+func NewRateLimitsFromConfigMap(config *corev1.ConfigMap) (*RateLimits, error) {
+	limits := &RateLimits{}
+	value, present := config.Data[RateLimitsKey]
+	if !present || value == "" {
+		return limits, nil
+	}
+	j, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("ConfigMap's value could not be converted to JSON: %w", err)
+	}
+	if err := json.Unmarshal(j, limits); err != nil {
+		return nil, fmt.Errorf("failed to parse the rate limits: %w", err)
+	}
+	if err := limits.ClusterDefault.validate(); err != nil {
+		return nil, fmt.Errorf("invalid clusterDefault: %w", err)
+	}
+	for ns, l := range limits.NamespaceDefaults {
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("invalid namespaceDefaults for %q: %w", ns, err)
+		}
+	}
+	return limits, nil
+}
+
+func (l *RateLimit) validate() error {
+	if l == nil {
+		return nil
+	}
+	if l.EventsPerSecond < 0 {
+		return fmt.Errorf("eventsPerSecond must not be negative, was %v", l.EventsPerSecond)
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, was %d", l.Burst)
+	}
+	return nil
+}
+
+// Get returns the rate limit of the Brokers of the namespace, or nil if they
+// are not rate limited.
+func (r *RateLimits) Get(namespace string) *RateLimit {
+	if r == nil {
+		return nil
+	}
+	l, present := r.NamespaceDefaults[namespace]
+	if !present {
+		l = r.ClusterDefault
+	}
+	if l == nil || l.EventsPerSecond <= 0 {
+		return nil
+	}
+	return l
+}
+
+func (l *RateLimit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return int(math.Ceil(l.EventsPerSecond))
+}
+
+// RateLimiter rate limits the events sent to each Broker with a token bucket,
+// keyed by the namespace and the name of the Broker. The buckets of the
+// Brokers which didn't receive events for a while are evicted.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limits    *RateLimits
+	limiters  map[types.NamespacedName]*brokerLimiter
+	lastPrune time.Time
+}
+
+type brokerLimiter struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// refilled reports whether the bucket is full again at now, in which case
+// dropping it doesn't change the outcome of the next events.
+func (l *brokerLimiter) refilled(now time.Time) bool {
+	idle := now.Sub(l.lastUsed)
+	if idle < rateLimiterIdleTimeout {
+		return false
+	}
+	if l.limiter.Limit() == rate.Inf {
+		return true
+	}
+	fill := time.Duration(float64(l.limiter.Burst()) / float64(l.limiter.Limit()) * float64(time.Second))
+	return idle >= fill
+}
+
+// NewRateLimiter creates a RateLimiter which doesn't limit any Broker until
+// the limits are set.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		limiters: make(map[types.NamespacedName]*brokerLimiter),
+	}
+}
+
+// Forget drops the bucket of the Broker, to be called once the Broker is
+// deleted.
+func (r *RateLimiter) Forget(namespace, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.limiters, types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+// prune drops the buckets of the Brokers idle for long enough. It must be
+// called with the lock held.
+func (r *RateLimiter) prune(now time.Time) {
+	if now.Sub(r.lastPrune) < rateLimiterIdleTimeout {
+		return
+	}
+	r.lastPrune = now
+	for key, l := range r.limiters {
+		if l.refilled(now) {
+			delete(r.limiters, key)
+		}
+	}
+}
+
+// SetLimits sets the rate limits. The tokens left in the buckets of the
+// Brokers are preserved.
+func (r *RateLimiter) SetLimits(limits *RateLimits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limits = limits
+}
+
+// UpdateFromConfigMap returns a function updating the rate limits from the
+// ConfigMap, to be used with a ConfigMap watcher.
+func (r *RateLimiter) UpdateFromConfigMap(logger *zap.Logger) func(configMap *corev1.ConfigMap) {
+	return func(configMap *corev1.ConfigMap) {
+		limits, err := NewRateLimitsFromConfigMap(configMap)
+		if err != nil {
+			logger.Error("Failed to parse the rate limits, keeping the current ones", zap.Error(err))
+			return
+		}
+		r.SetLimits(limits)
+		logger.Info("Updated the rate limits", zap.Any("limits", limits))
+	}
+}
+
+// Allow reports whether an event can be sent to the Broker now. If it can't,
+// it also returns how long the sender should wait before retrying. Callers
+// must make sure the Broker exists, every Broker gets its own bucket.
+func (r *RateLimiter) Allow(namespace, name string, now time.Time) (bool, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prune(now)
+
+	l := r.limits.Get(namespace)
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if l == nil {
+		delete(r.limiters, key)
+		return true, 0
+	}
+
+	entry, ok := r.limiters[key]
+	if !ok {
+		entry = &brokerLimiter{limiter: rate.NewLimiter(rate.Limit(l.EventsPerSecond), l.burst())}
+		r.limiters[key] = entry
+	}
+	entry.lastUsed = now
+	limiter := entry.limiter
+	if limiter.Limit() != rate.Limit(l.EventsPerSecond) || limiter.Burst() != l.burst() {
+		limiter.SetLimitAt(now, rate.Limit(l.EventsPerSecond))
+		limiter.SetBurstAt(now, l.burst())
+	}
+
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
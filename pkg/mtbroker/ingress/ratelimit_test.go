@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestNewRateLimitsFromConfigMap(t *testing.T) {
+	tests := map[string]struct {
+		data    map[string]string
+		want    *RateLimits
+		wantErr bool
+	}{
+		"no limits": {
+			want: &RateLimits{},
+		},
+		"limits": {
+			data: map[string]string{RateLimitsKey: `
+clusterDefault:
+  eventsPerSecond: 100
+  burst: 200
+namespaceDefaults:
+  noisy:
+    eventsPerSecond: 0.5
+`},
+			want: &RateLimits{
+				ClusterDefault: &RateLimit{EventsPerSecond: 100, Burst: 200},
+				NamespaceDefaults: map[string]*RateLimit{
+					"noisy": {EventsPerSecond: 0.5},
+				},
+			},
+		},
+		"negative rate": {
+			data:    map[string]string{RateLimitsKey: "clusterDefault: {eventsPerSecond: -1}"},
+			wantErr: true,
+		},
+		"negative burst": {
+			data:    map[string]string{RateLimitsKey: "namespaceDefaults: {noisy: {eventsPerSecond: 1, burst: -1}}"},
+			wantErr: true,
+		},
+		"malformed": {
+			data:    map[string]string{RateLimitsKey: "clusterDefault: 100"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewRateLimitsFromConfigMap(&corev1.ConfigMap{Data: tc.data})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewRateLimitsFromConfigMap() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("Unexpected rate limits (-want +got):", diff)
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1617271200, 0)
+	r := NewRateLimiter()
+
+	// No limits.
+	for i := 0; i < 10; i++ {
+		if ok, _ := r.Allow("ns", "broker", now); !ok {
+			t.Fatal("Expected the event to be allowed without limits")
+		}
+	}
+
+	r.UpdateFromConfigMap(zap.NewNop())(&corev1.ConfigMap{Data: map[string]string{RateLimitsKey: `
+clusterDefault:
+  eventsPerSecond: 10
+  burst: 2
+namespaceDefaults:
+  unlimited:
+    eventsPerSecond: 0
+  slow:
+    eventsPerSecond: 0.5
+`}})
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := r.Allow("ns", "broker", now); !ok {
+			t.Fatalf("Expected event %d to be allowed by the burst", i)
+		}
+	}
+	ok, retryAfter := r.Allow("ns", "broker", now)
+	if ok {
+		t.Fatal("Expected the event to be throttled")
+	}
+	if retryAfter != 100*time.Millisecond {
+		t.Errorf("Retry after = %v, want 100ms", retryAfter)
+	}
+	if ok, _ := r.Allow("ns", "broker", now.Add(100*time.Millisecond)); !ok {
+		t.Error("Expected the event to be allowed after refill")
+	}
+
+	// Each Broker has its own bucket.
+	if ok, _ := r.Allow("ns", "other", now); !ok {
+		t.Error("Expected the event to another Broker to be allowed")
+	}
+
+	// Namespace overrides.
+	for i := 0; i < 10; i++ {
+		if ok, _ := r.Allow("unlimited", "broker", now); !ok {
+			t.Fatal("Expected the event to be allowed in the unlimited namespace")
+		}
+	}
+	if ok, _ := r.Allow("slow", "broker", now); !ok {
+		t.Error("Expected the first event to be allowed in the slow namespace")
+	}
+	if ok, retryAfter := r.Allow("slow", "broker", now); ok || retryAfter != 2*time.Second {
+		t.Errorf("Allow() = %v, %v, want false, 2s", ok, retryAfter)
+	}
+
+	// Invalid limits are ignored.
+	r.UpdateFromConfigMap(zap.NewNop())(&corev1.ConfigMap{Data: map[string]string{RateLimitsKey: "clusterDefault: {eventsPerSecond: -1}"}})
+	if ok, _ := r.Allow("ns", "broker", now.Add(100*time.Millisecond)); ok {
+		t.Error("Expected the previous limits to be kept")
+	}
+}
+
+func TestRateLimiterEviction(t *testing.T) {
+	now := time.Unix(1617271200, 0)
+	r := NewRateLimiter()
+	r.SetLimits(&RateLimits{ClusterDefault: &RateLimit{EventsPerSecond: 1}})
+
+	r.Allow("ns", "idle", now)
+	r.Allow("ns", "busy", now)
+	r.Allow("ns", "deleted", now)
+	r.Forget("ns", "deleted")
+	if _, ok := r.limiters[types.NamespacedName{Namespace: "ns", Name: "deleted"}]; ok {
+		t.Error("Expected the bucket of the deleted Broker to be dropped")
+	}
+
+	later := now.Add(rateLimiterIdleTimeout / 2)
+	r.Allow("ns", "busy", later)
+	r.Allow("ns", "busy", later.Add(rateLimiterIdleTimeout/2))
+	if _, ok := r.limiters[types.NamespacedName{Namespace: "ns", Name: "idle"}]; ok {
+		t.Error("Expected the bucket of the idle Broker to be evicted")
+	}
+	if _, ok := r.limiters[types.NamespacedName{Namespace: "ns", Name: "busy"}]; !ok {
+		t.Error("Expected the bucket of the busy Broker to be kept")
+	}
+}
+
+func TestRateLimiterEvictionKeepsEmptyBuckets(t *testing.T) {
+	now := time.Unix(1617271200, 0)
+	r := NewRateLimiter()
+	// The bucket takes an hour to refill.
+	r.SetLimits(&RateLimits{ClusterDefault: &RateLimit{EventsPerSecond: 1.0 / 3600}})
+
+	r.Allow("ns", "broker", now)
+	later := now.Add(2 * rateLimiterIdleTimeout)
+	if ok, _ := r.Allow("ns", "other", later); !ok {
+		t.Fatal("Expected the event to another Broker to be allowed")
+	}
+	if ok, _ := r.Allow("ns", "broker", later); ok {
+		t.Error("Expected the bucket which didn't refill to be kept")
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/eventing/pkg/eventfilter/data"
+	"knative.dev/eventing/pkg/jsonschema"
+)
+
+// EventTypeBrokerTypeIndex is the name of the index of the EventTypes by
+// namespace, Broker and type.
+const EventTypeBrokerTypeIndex = "brokerType"
+
+// EventTypeIndexers are the indexers the EventType informer needs for the
+// SchemaValidator.
+var EventTypeIndexers = cache.Indexers{
+	EventTypeBrokerTypeIndex: func(obj interface{}) ([]string, error) {
+		et, ok := obj.(*eventingv1beta1.EventType)
+		if !ok {
+			return nil, nil
+		}
+		return []string{brokerTypeKey(et.Namespace, et.Spec.Broker, et.Spec.Type)}, nil
+	},
+}
+
+func brokerTypeKey(namespace, broker, eventType string) string {
+	// Namespaces and Broker names can't contain slashes.
+	return namespace + "/" + broker + "/" + eventType
+}
+
+// SchemaValidator validates the data of events against the JSON Schema in the
+// SchemaData of their EventType.
+type SchemaValidator struct {
+	eventTypes cache.Indexer
+	logger     *zap.Logger
+
+	mu sync.RWMutex
+	// schemas are the compiled schemas, keyed by EventType UID.
+	schemas map[types.UID]*compiledSchema
+}
+
+type compiledSchema struct {
+	generation int64
+	schema     *jsonschema.Schema
+}
+
+// NewSchemaValidator creates a SchemaValidator looking the EventTypes up in
+// the indexer, which must have the EventTypeIndexers.
+func NewSchemaValidator(eventTypes cache.Indexer, logger *zap.Logger) *SchemaValidator {
+	return &SchemaValidator{
+		eventTypes: eventTypes,
+		logger:     logger,
+		schemas:    make(map[types.UID]*compiledSchema),
+	}
+}
+
+// EventTypeDeleted forgets the compiled schema of the deleted EventType, to be
+// used as the DeleteFunc of the EventType informer.
+func (v *SchemaValidator) EventTypeDeleted(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if et, ok := obj.(*eventingv1beta1.EventType); ok {
+		v.mu.Lock()
+		delete(v.schemas, et.UID)
+		v.mu.Unlock()
+	}
+}
+
+// SchemaValidationError describes why the data of an event doesn't conform to
+// the schema of its EventType.
+type SchemaValidationError struct {
+	err error
+}
+
+func (e *SchemaValidationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *SchemaValidationError) Unwrap() error {
+	return e.err
+}
+
+// Validate validates the data of the event sent to the Broker against the
+// schema of the matching EventType, if any. It returns a *SchemaValidationError
+// if the event doesn't conform to the schema.
+func (v *SchemaValidator) Validate(event *cloudevents.Event, brokerNamespace, brokerName string) error {
+	et, err := v.getEventType(event, brokerNamespace, brokerName)
+	if err != nil || et == nil || et.Spec.SchemaData == "" {
+		// Events of unknown types are not validated.
+		return nil
+	}
+	schema := v.getSchema(et)
+	if schema == nil {
+		return nil
+	}
+
+	if !data.IsJSON(event.DataMediaType()) {
+		return &SchemaValidationError{err: fmt.Errorf("the data of the event must be JSON to be validated against the schema of EventType %s, got %q", et.Name, event.DataContentType())}
+	}
+	document := event.Data()
+	if len(document) == 0 {
+		document = []byte("null")
+	}
+	if err := schema.Validate(document); err != nil {
+		return &SchemaValidationError{err: fmt.Errorf("the data of the event doesn't conform to the schema of EventType %s: %w", et.Name, err)}
+	}
+	return nil
+}
+
+// getEventType returns the EventType of the Broker matching the type and the
+// source of the event. EventTypes with the same source are preferred over the
+// ones without a source.
+func (v *SchemaValidator) getEventType(event *cloudevents.Event, brokerNamespace, brokerName string) (*eventingv1beta1.EventType, error) {
+	objs, err := v.eventTypes.ByIndex(EventTypeBrokerTypeIndex, brokerTypeKey(brokerNamespace, brokerName, event.Type()))
+	if err != nil {
+		return nil, err
+	}
+	var match *eventingv1beta1.EventType
+	for _, obj := range objs {
+		et, ok := obj.(*eventingv1beta1.EventType)
+		if !ok {
+			continue
+		}
+		if et.Spec.Source == nil {
+			if match == nil {
+				match = et
+			}
+			continue
+		}
+		if et.Spec.Source.String() == event.Source() {
+			return et, nil
+		}
+	}
+	return match, nil
+}
+
+// getSchema returns the compiled schema of the EventType, or nil if its
+// SchemaData is not a valid JSON Schema, which the EventType reconciler reports
+// in the SchemaValid condition of the EventType.
+func (v *SchemaValidator) getSchema(et *eventingv1beta1.EventType) *jsonschema.Schema {
+	v.mu.RLock()
+	cs, ok := v.schemas[et.UID]
+	v.mu.RUnlock()
+	if ok && cs.generation == et.Generation {
+		return cs.schema
+	}
+
+	schema, err := jsonschema.Compile([]byte(et.Spec.SchemaData))
+	if err != nil {
+		v.logger.Warn("Not validating events against the invalid JSON Schema of the EventType",
+			zap.String("namespace", et.Namespace), zap.String("name", et.Name), zap.Error(err))
+	}
+	v.mu.Lock()
+	v.schemas[et.UID] = &compiledSchema{generation: et.Generation, schema: schema}
+	v.mu.Unlock()
+	return schema
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	reconcilertestingv1beta1 "knative.dev/eventing/pkg/reconciler/testing/v1beta1"
+)
+
+const tierSchema = `{"type": "object", "required": ["tier"], "properties": {"tier": {"enum": ["gold", "silver"]}}}`
+
+func TestSchemaValidator_Validate(t *testing.T) {
+	tests := map[string]struct {
+		eventTypes  []runtime.Object
+		contentType string
+		data        string
+		wantErr     string
+	}{
+		"no EventType": {
+			data: `{}`,
+		},
+		"valid": {
+			eventTypes: []runtime.Object{
+				makeEventType("orders", "broker", "", tierSchema),
+			},
+			data: `{"tier": "gold"}`,
+		},
+		"invalid": {
+			eventTypes: []runtime.Object{
+				makeEventType("orders", "broker", "", tierSchema),
+			},
+			data:    `{"tier": "bronze"}`,
+			wantErr: `the data of the event doesn't conform to the schema of EventType orders: /tier: value must be one of ["gold","silver"]`,
+		},
+		"no data": {
+			eventTypes: []runtime.Object{
+				makeEventType("orders", "broker", "", tierSchema),
+			},
+			wantErr: "the data of the event doesn't conform to the schema of EventType orders: /: expected object, got null",
+		},
+		"not JSON": {
+			eventTypes: []runtime.Object{
+				makeEventType("orders", "broker", "", tierSchema),
+			},
+			contentType: "text/plain",
+			data:        `tier=gold`,
+			wantErr:     `the data of the event must be JSON to be validated against the schema of EventType orders, got "text/plain"`,
+		},
+		"EventType of another Broker": {
+			eventTypes: []runtime.Object{
+				makeEventType("orders", "other", "", tierSchema),
+			},
+			data: `{"tier": "bronze"}`,
+		},
+		"EventType with the same source preferred": {
+			eventTypes: []runtime.Object{
+				makeEventType("any-source", "broker", "", `{"type": "object"}`),
+				makeEventType("same-source", "broker", "/orders", tierSchema),
+				makeEventType("other-source", "broker", "/other", `{"type": "object"}`),
+			},
+			data:    `{"tier": "bronze"}`,
+			wantErr: `the data of the event doesn't conform to the schema of EventType same-source: /tier: value must be one of ["gold","silver"]`,
+		},
+		"EventType without SchemaData": {
+			eventTypes: []runtime.Object{
+				makeEventType("orders", "broker", "", ""),
+			},
+			data: `{"tier": "bronze"}`,
+		},
+		"invalid JSON Schema": {
+			eventTypes: []runtime.Object{
+				makeEventType("orders", "broker", "", `syntax = "proto3";`),
+			},
+			data: `{"tier": "bronze"}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := NewSchemaValidator(eventTypeIndexer(tc.eventTypes), zap.NewNop())
+
+			e := cloudevents.NewEvent()
+			e.SetID("1234")
+			e.SetType("com.example.order")
+			e.SetSource("/orders")
+			if tc.data != "" {
+				contentType := tc.contentType
+				if contentType == "" {
+					contentType = cloudevents.ApplicationJSON
+				}
+				e.SetDataContentType(contentType)
+				e.DataEncoded = []byte(tc.data)
+			}
+
+			err := v.Validate(&e, "ns", "broker")
+			if got := errorString(err); got != tc.wantErr {
+				t.Errorf("Validate() = %q, want %q", got, tc.wantErr)
+			}
+			var sve *SchemaValidationError
+			if err != nil && !errors.As(err, &sve) {
+				t.Errorf("Validate() = %T, want a *SchemaValidationError", err)
+			}
+		})
+	}
+}
+
+func TestSchemaValidator_UpdatedEventType(t *testing.T) {
+	et := makeEventType("orders", "broker", "", tierSchema)
+	v := NewSchemaValidator(eventTypeIndexer([]runtime.Object{et}), zap.NewNop())
+
+	e := cloudevents.NewEvent()
+	e.SetID("1234")
+	e.SetType("com.example.order")
+	e.SetSource("/orders")
+	_ = e.SetData(cloudevents.ApplicationJSON, map[string]string{"tier": "bronze"})
+
+	if err := v.Validate(&e, "ns", "broker"); err == nil {
+		t.Fatal("Expected Validate() to fail")
+	}
+
+	// The indexer returns the cached object, update the schema in place.
+	et.Spec.SchemaData = `{"type": "object"}`
+	et.Generation++
+	if err := v.Validate(&e, "ns", "broker"); err != nil {
+		t.Error("Validate() =", err)
+	}
+}
+
+func TestSchemaValidator_DeletedEventType(t *testing.T) {
+	et := makeEventType("orders", "broker", "", tierSchema)
+	indexer := eventTypeIndexer([]runtime.Object{et})
+	v := NewSchemaValidator(indexer, zap.NewNop())
+
+	e := cloudevents.NewEvent()
+	e.SetID("1234")
+	e.SetType("com.example.order")
+	e.SetSource("/orders")
+	_ = e.SetData(cloudevents.ApplicationJSON, map[string]string{"tier": "bronze"})
+
+	if err := v.Validate(&e, "ns", "broker"); err == nil {
+		t.Fatal("Expected Validate() to fail")
+	}
+	if len(v.schemas) != 1 {
+		t.Fatalf("Expected the schema to be cached, got %d schemas", len(v.schemas))
+	}
+
+	_ = indexer.Delete(et)
+	v.EventTypeDeleted(cache.DeletedFinalStateUnknown{Key: "ns/orders", Obj: et})
+	if len(v.schemas) != 0 {
+		t.Errorf("Expected the schema of the deleted EventType to be forgotten, got %d schemas", len(v.schemas))
+	}
+	if err := v.Validate(&e, "ns", "broker"); err != nil {
+		t.Error("Validate() =", err)
+	}
+}
+
+// eventTypeIndexer returns an indexer of the EventTypes, as the EventType
+// informer of broker-ingress.
+func eventTypeIndexer(objs []runtime.Object) cache.Indexer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, EventTypeIndexers)
+	for _, obj := range objs {
+		_ = indexer.Add(obj)
+	}
+	return indexer
+}
+
+func makeEventType(name, brokerName, source, schemaData string) *eventingv1beta1.EventType {
+	opts := []reconcilertestingv1beta1.EventTypeOption{
+		reconcilertestingv1beta1.WithEventTypeType("com.example.order"),
+		reconcilertestingv1beta1.WithEventTypeBroker(brokerName),
+		reconcilertestingv1beta1.WithEventTypeSchemaData(schemaData),
+	}
+	if source != "" {
+		url, _ := apis.ParseURL(source)
+		opts = append(opts, reconcilertestingv1beta1.WithEventTypeSource(url))
+	}
+	et := reconcilertestingv1beta1.NewEventType(name, "ns", opts...)
+	et.UID = types.UID(name + "-uid")
+	return et
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// spoolFileSuffix is the suffix of the files of the spooled events.
+	spoolFileSuffix = ".json"
+
+	// spoolMinBackoff and spoolMaxBackoff bound the delay between two
+	// attempts to replay the spooled events.
+	spoolMinBackoff = time.Second
+	spoolMaxBackoff = time.Minute
+)
+
+// ErrSpoolFull is returned when spooling an event while the spool holds the
+// maximum number of events.
+var ErrSpoolFull = errors.New("the spool is full")
+
+// spooledEvent is the content of the file of a spooled event.
+type spooledEvent struct {
+	// Target is the address of the channel the event is sent to.
+	Target string `json:"target"`
+	// Headers are the headers passed through to the channel.
+	Headers http.Header `json:"headers,omitempty"`
+	// Spooled is the time the event was spooled at.
+	Spooled time.Time          `json:"spooled"`
+	Event   *cloudevents.Event `json:"event"`
+}
+
+// spoolEntry is a spooled event whose content is on disk.
+type spoolEntry struct {
+	seq     uint64
+	target  string
+	spooled time.Time
+}
+
+// SpoolSendFunc sends a spooled event to its target, and returns the status
+// code of the response.
+type SpoolSendFunc func(ctx context.Context, headers http.Header, event *cloudevents.Event, target string) int
+
+// Spool is a bounded on-disk write-ahead log of the events which couldn't be
+// sent to the channels of the Brokers. The events are replayed in order for
+// each channel, with a backoff, until the channel accepts them.
+type Spool struct {
+	dir       string
+	maxEvents int
+	reporter  StatsReporter
+	logger    *zap.Logger
+
+	mu      sync.Mutex
+	entries []spoolEntry
+	// pending counts the spooled events of each target.
+	pending map[string]int
+	nextSeq uint64
+	// notify wakes up the replay when an event is spooled.
+	notify chan struct{}
+}
+
+// NewSpool creates a Spool storing at most maxEvents events in the directory.
+// The events already spooled in the directory, e.g. before a restart, are
+// replayed first.
+func NewSpool(dir string, maxEvents int, reporter StatsReporter, logger *zap.Logger) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create the spool directory: %w", err)
+	}
+	s := &Spool{
+		dir:       dir,
+		maxEvents: maxEvents,
+		reporter:  reporter,
+		logger:    logger,
+		pending:   make(map[string]int),
+		nextSeq:   1,
+		notify:    make(chan struct{}, 1),
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the spool directory: %w", err)
+	}
+	for _, f := range files {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(f.Name(), spoolFileSuffix), 10, 64)
+		if err != nil || !strings.HasSuffix(f.Name(), spoolFileSuffix) {
+			// Temporary files of events which weren't completely spooled.
+			continue
+		}
+		se, err := s.read(seq)
+		if err != nil {
+			logger.Warn("Dropping unreadable spooled event", zap.String("file", f.Name()), zap.Error(err))
+			_ = os.Remove(s.path(seq))
+			continue
+		}
+		s.entries = append(s.entries, spoolEntry{seq: seq, target: se.Target, spooled: se.Spooled})
+		s.pending[se.Target]++
+		if seq >= s.nextSeq {
+			s.nextSeq = seq + 1
+		}
+	}
+	sort.Slice(s.entries, func(i, j int) bool { return s.entries[i].seq < s.entries[j].seq })
+	return s, nil
+}
+
+// Pending returns whether events sent to the target are spooled. New events
+// sent to the target must then be spooled too, to preserve their order.
+func (s *Spool) Pending(target string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending[target] > 0
+}
+
+// Add durably writes the event to the spool. It returns ErrSpoolFull if the
+// spool holds the maximum number of events.
+func (s *Spool) Add(headers http.Header, event *cloudevents.Event, target string, now time.Time) error {
+	s.mu.Lock()
+	if len(s.entries) >= s.maxEvents {
+		s.mu.Unlock()
+		return ErrSpoolFull
+	}
+	seq := s.nextSeq
+	s.nextSeq++
+	s.mu.Unlock()
+
+	b, err := json.Marshal(spooledEvent{Target: target, Headers: headers, Spooled: now, Event: event})
+	if err != nil {
+		return fmt.Errorf("failed to encode the event: %w", err)
+	}
+	if err := s.write(seq, b); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	// Writes may complete out of order, keep the entries sorted.
+	i := sort.Search(len(s.entries), func(i int) bool { return s.entries[i].seq > seq })
+	s.entries = append(s.entries, spoolEntry{})
+	copy(s.entries[i+1:], s.entries[i:])
+	s.entries[i] = spoolEntry{seq: seq, target: target, spooled: now}
+	s.pending[target]++
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run replays the spooled events until the context is done.
+func (s *Spool) Run(ctx context.Context, send SpoolSendFunc) {
+	backoff := spoolMinBackoff
+	for {
+		s.reportDepth(time.Now())
+		progress, remaining := s.replay(ctx, send)
+		if progress {
+			backoff = spoolMinBackoff
+		} else if remaining {
+			backoff *= 2
+			if backoff > spoolMaxBackoff {
+				backoff = spoolMaxBackoff
+			}
+		}
+
+		var retry <-chan time.Time
+		if remaining {
+			retry = time.After(backoff)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.notify:
+		case <-retry:
+		}
+	}
+}
+
+// replay sends the spooled events in order. Once an event fails to be sent
+// to a target, the following events of the target are kept for the next
+// attempt. It returns whether any event was sent, and whether events remain.
+func (s *Spool) replay(ctx context.Context, send SpoolSendFunc) (progress bool, remaining bool) {
+	s.mu.Lock()
+	entries := make([]spoolEntry, len(s.entries))
+	copy(entries, s.entries)
+	s.mu.Unlock()
+
+	failed := make(map[string]bool)
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return progress, true
+		}
+		if failed[e.target] {
+			remaining = true
+			continue
+		}
+		se, err := s.read(e.seq)
+		if err != nil {
+			s.logger.Warn("Dropping unreadable spooled event", zap.Uint64("seq", e.seq), zap.Error(err))
+			s.remove(e)
+			continue
+		}
+
+		statusCode := send(ctx, se.Headers, se.Event, se.Target)
+		switch {
+		case statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices:
+			s.remove(e)
+			progress = true
+		case !isSpoolable(statusCode) && statusCode != http.StatusTooManyRequests:
+			s.logger.Warn("Dropping spooled event rejected by the channel", zap.String("target", se.Target),
+				zap.String("event.id", se.Event.ID()), zap.Int("status", statusCode))
+			s.remove(e)
+			progress = true
+		default:
+			failed[e.target] = true
+			remaining = true
+		}
+	}
+	return progress, remaining
+}
+
+func (s *Spool) remove(e spoolEntry) {
+	if err := os.Remove(s.path(e.seq)); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("Failed to remove spooled event", zap.Uint64("seq", e.seq), zap.Error(err))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.entries {
+		if s.entries[i].seq == e.seq {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			break
+		}
+	}
+	if s.pending[e.target]--; s.pending[e.target] <= 0 {
+		delete(s.pending, e.target)
+	}
+}
+
+// reportDepth reports the number of spooled events and the age of the oldest.
+func (s *Spool) reportDepth(now time.Time) {
+	s.mu.Lock()
+	depth := len(s.entries)
+	var age time.Duration
+	if depth > 0 {
+		age = now.Sub(s.entries[0].spooled)
+	}
+	s.mu.Unlock()
+
+	_ = s.reporter.ReportSpool(depth, age)
+}
+
+func (s *Spool) path(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, spoolFileSuffix))
+}
+
+// write writes the file of the event to a temporary file first, so that
+// partially written events are never replayed.
+func (s *Spool) write(seq uint64, b []byte) error {
+	tmp, err := ioutil.TempFile(s.dir, "tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create the spool file: %w", err)
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write the spool file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to sync the spool file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to close the spool file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(seq)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to rename the spool file: %w", err)
+	}
+	// Sync the directory so that the rename is durable too.
+	dir, err := os.Open(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to open the spool directory: %w", err)
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("failed to sync the spool directory: %w", err)
+	}
+	return nil
+}
+
+func (s *Spool) read(seq uint64) (*spooledEvent, error) {
+	b, err := ioutil.ReadFile(s.path(seq))
+	if err != nil {
+		return nil, err
+	}
+	se := &spooledEvent{}
+	if err := json.Unmarshal(b, se); err != nil {
+		return nil, err
+	}
+	if se.Event == nil {
+		return nil, errors.New("the spooled event has no event")
+	}
+	return se, nil
+}
+
+// isSpoolable returns whether an event whose sending to the channel failed
+// with the status code can be accepted if it is spooled, i.e. whether the
+// failure is likely to be transient.
+func isSpoolable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+)
+
+func spoolEvent(id string) *cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID(id)
+	e.SetType("type")
+	e.SetSource("source")
+	return &e
+}
+
+func TestSpool(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	dir := t.TempDir()
+	now := time.Unix(1e9, 0)
+
+	s, err := NewSpool(dir, 3, &mockReporter{}, logger)
+	if err != nil {
+		t.Fatal("NewSpool() =", err)
+	}
+	for i, id := range []string{"1", "2", "3"} {
+		target := "http://a"
+		if id == "2" {
+			target = "http://b"
+		}
+		if err := s.Add(http.Header{"Traceparent": []string{id}}, spoolEvent(id), target, now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal("Add() =", err)
+		}
+	}
+	if err := s.Add(nil, spoolEvent("4"), "http://a", now); err != ErrSpoolFull {
+		t.Errorf("Add() = %v, want %v", err, ErrSpoolFull)
+	}
+	if !s.Pending("http://a") || !s.Pending("http://b") || s.Pending("http://c") {
+		t.Error("Expected events to be pending for the targets of the spooled events only")
+	}
+
+	// The spooled events are reloaded, e.g. after a restart.
+	ioutil.WriteFile(dir+"/tmp-partial", []byte("{"), 0600)
+	s, err = NewSpool(dir, 3, &mockReporter{}, logger)
+	if err != nil {
+		t.Fatal("NewSpool() =", err)
+	}
+
+	reporter := &mockReporter{}
+	s.reporter = reporter
+	s.reportDepth(now.Add(10 * time.Second))
+	if reporter.SpoolDepth != 3 {
+		t.Errorf("Reported spool depth = %d, want 3", reporter.SpoolDepth)
+	}
+
+	var sent []string
+	statusCodes := map[string]int{"http://a": http.StatusServiceUnavailable, "http://b": http.StatusAccepted}
+	send := func(_ context.Context, headers http.Header, event *cloudevents.Event, target string) int {
+		if got := headers.Get("Traceparent"); got != event.ID() {
+			t.Errorf("Unexpected headers of event %s: %v", event.ID(), headers)
+		}
+		sent = append(sent, event.ID())
+		return statusCodes[target]
+	}
+
+	// The events of a failing target are retried in order, without blocking
+	// the other targets.
+	progress, remaining := s.replay(ctx, send)
+	if !progress || !remaining {
+		t.Errorf("replay() = %v, %v, want true, true", progress, remaining)
+	}
+	if diff := cmp.Diff([]string{"1", "2"}, sent); diff != "" {
+		t.Error("Unexpected sent events (-want +got):", diff)
+	}
+
+	sent = nil
+	statusCodes["http://a"] = http.StatusAccepted
+	progress, remaining = s.replay(ctx, send)
+	if !progress || remaining {
+		t.Errorf("replay() = %v, %v, want true, false", progress, remaining)
+	}
+	if diff := cmp.Diff([]string{"1", "3"}, sent); diff != "" {
+		t.Error("Unexpected sent events (-want +got):", diff)
+	}
+	if s.Pending("http://a") || s.Pending("http://b") {
+		t.Error("Expected no pending events once replayed")
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("Expected only the partial file to remain in the spool directory, got %d files", len(files))
+	}
+}
+
+func TestSpoolDropsRejectedEvents(t *testing.T) {
+	s, err := NewSpool(t.TempDir(), 10, &mockReporter{}, zap.NewNop())
+	if err != nil {
+		t.Fatal("NewSpool() =", err)
+	}
+	if err := s.Add(nil, spoolEvent("1"), "http://a", time.Now()); err != nil {
+		t.Fatal("Add() =", err)
+	}
+
+	progress, remaining := s.replay(context.Background(), func(context.Context, http.Header, *cloudevents.Event, string) int {
+		return http.StatusBadRequest
+	})
+	if !progress || remaining {
+		t.Errorf("replay() = %v, %v, want true, false", progress, remaining)
+	}
+	if s.Pending("http://a") {
+		t.Error("Expected the rejected event to be dropped")
+	}
+}
+
+func TestSpoolRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s, err := NewSpool(t.TempDir(), 10, &mockReporter{}, zap.NewNop())
+	if err != nil {
+		t.Fatal("NewSpool() =", err)
+	}
+	sent := make(chan string, 1)
+	go s.Run(ctx, func(_ context.Context, _ http.Header, event *cloudevents.Event, _ string) int {
+		sent <- event.ID()
+		return http.StatusAccepted
+	})
+
+	if err := s.Add(nil, spoolEvent("1"), "http://a", time.Now()); err != nil {
+		t.Fatal("Add() =", err)
+	}
+	select {
+	case id := <-sent:
+		if id != "1" {
+			t.Errorf("Replayed event %s, want 1", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the spooled event to be replayed")
+	}
+}
@@ -19,6 +19,7 @@ package ingress
 import (
 	"context"
 	"log"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -48,6 +49,55 @@ var (
 		stats.UnitMilliseconds,
 	)
 
+	// authRejectionCountM is a counter which records the number of requests
+	// rejected by a Broker because they are not authenticated or authorized.
+	authRejectionCountM = stats.Int64(
+		"auth_rejection_count",
+		"Number of requests rejected by a Broker because they are not authenticated or authorized",
+		stats.UnitDimensionless,
+	)
+
+	// throttledCountM is a counter which records the number of requests
+	// rejected by a Broker because they exceed its rate limit.
+	throttledCountM = stats.Int64(
+		"throttled_count",
+		"Number of requests rejected by a Broker because they exceed its rate limit",
+		stats.UnitDimensionless,
+	)
+
+	// schemaValidationFailureCountM is a counter which records the number of
+	// events rejected by a Broker because they don't conform to the schema of
+	// their EventType.
+	schemaValidationFailureCountM = stats.Int64(
+		"schema_validation_failure_count",
+		"Number of events rejected by a Broker because they don't conform to the schema of their EventType",
+		stats.UnitDimensionless,
+	)
+
+	// duplicateCountM is a counter which records the number of duplicate
+	// events dropped by a Broker.
+	duplicateCountM = stats.Int64(
+		"duplicate_count",
+		"Number of duplicate events dropped by a Broker",
+		stats.UnitDimensionless,
+	)
+
+	// spoolDepthM records the number of events spooled by the ingress
+	// because they couldn't be sent to the channels.
+	spoolDepthM = stats.Int64(
+		"spool_depth",
+		"Number of events spooled because they couldn't be sent to a Channel",
+		stats.UnitDimensionless,
+	)
+
+	// spoolOldestAgeM records the age of the oldest event spooled by the
+	// ingress, in seconds.
+	spoolOldestAgeM = stats.Float64(
+		"spool_oldest_age",
+		"The age of the oldest event spooled because it couldn't be sent to a Channel",
+		stats.UnitSeconds,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -72,6 +122,11 @@ func init() {
 type StatsReporter interface {
 	ReportEventCount(args *ReportArgs, responseCode int) error
 	ReportEventDispatchTime(args *ReportArgs, responseCode int, d time.Duration) error
+	ReportAuthRejection(args *ReportArgs, responseCode int) error
+	ReportThrottled(args *ReportArgs) error
+	ReportSchemaValidationFailure(args *ReportArgs) error
+	ReportDuplicate(args *ReportArgs) error
+	ReportSpool(depth int, oldestAge time.Duration) error
 }
 
 var _ StatsReporter = (*reporter)(nil)
@@ -113,6 +168,60 @@ func register() {
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 500, 1000, 5000, 10000
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Description: authRejectionCountM.Description(),
+			Measure:     authRejectionCountM,
+			Aggregation: view.Count(),
+			// The event isn't extracted from rejected requests.
+			TagKeys: []tag.Key{
+				responseCodeKey,
+				responseCodeClassKey,
+				broker.ContainerTagKey,
+				broker.UniqueTagKey},
+		},
+		&view.View{
+			Description: throttledCountM.Description(),
+			Measure:     throttledCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				broker.ContainerTagKey,
+				broker.UniqueTagKey},
+		},
+		&view.View{
+			Description: schemaValidationFailureCountM.Description(),
+			Measure:     schemaValidationFailureCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				eventTypeKey,
+				broker.ContainerTagKey,
+				broker.UniqueTagKey},
+		},
+		&view.View{
+			Description: duplicateCountM.Description(),
+			Measure:     duplicateCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				eventTypeKey,
+				broker.ContainerTagKey,
+				broker.UniqueTagKey},
+		},
+		&view.View{
+			Description: spoolDepthM.Description(),
+			Measure:     spoolDepthM,
+			Aggregation: view.LastValue(),
+			// The spool is shared by all the Brokers.
+			TagKeys: []tag.Key{
+				broker.ContainerTagKey,
+				broker.UniqueTagKey},
+		},
+		&view.View{
+			Description: spoolOldestAgeM.Description(),
+			Measure:     spoolOldestAgeM,
+			Aggregation: view.LastValue(),
+			TagKeys: []tag.Key{
+				broker.ContainerTagKey,
+				broker.UniqueTagKey},
+		},
 	)
 	if err != nil {
 		log.Printf("failed to register opencensus views, %s", err)
@@ -140,6 +249,64 @@ func (r *reporter) ReportEventDispatchTime(args *ReportArgs, responseCode int, d
 	return nil
 }
 
+// ReportAuthRejection captures the rejection of a request by the
+// authentication of the Broker.
+func (r *reporter) ReportAuthRejection(args *ReportArgs, responseCode int) error {
+	ctx, err := r.generateTag(args, responseCode)
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, authRejectionCountM.M(1))
+	return nil
+}
+
+// ReportThrottled captures the rejection of a request exceeding the rate
+// limit of the Broker.
+func (r *reporter) ReportThrottled(args *ReportArgs) error {
+	ctx, err := r.generateTag(args, http.StatusTooManyRequests)
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, throttledCountM.M(1))
+	return nil
+}
+
+// ReportSchemaValidationFailure captures the rejection of an event not
+// conforming to the schema of its EventType.
+func (r *reporter) ReportSchemaValidationFailure(args *ReportArgs) error {
+	ctx, err := r.generateTag(args, http.StatusBadRequest)
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, schemaValidationFailureCountM.M(1))
+	return nil
+}
+
+// ReportDuplicate captures the drop of a duplicate event.
+func (r *reporter) ReportDuplicate(args *ReportArgs) error {
+	ctx, err := r.generateTag(args, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, duplicateCountM.M(1))
+	return nil
+}
+
+// ReportSpool captures the number of spooled events and the age of the
+// oldest one.
+func (r *reporter) ReportSpool(depth int, oldestAge time.Duration) error {
+	ctx, err := tag.New(
+		emptyContext,
+		tag.Insert(broker.ContainerTagKey, r.container),
+		tag.Insert(broker.UniqueTagKey, r.uniqueName))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, spoolDepthM.M(int64(depth)))
+	metrics.Record(ctx, spoolOldestAgeM.M(oldestAge.Seconds()))
+	return nil
+}
+
 func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
 	ctx := metricskey.WithResource(emptyContext, resource.Resource{
 		Type: metricskey.ResourceTypeKnativeBroker,
@@ -73,6 +73,63 @@ func TestStatsReporter(t *testing.T) {
 	})
 	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric("event_dispatch_latencies", 2, wantTags))
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
+
+	// test ReportAuthRejection
+	expectSuccess(t, func() error {
+		return r.ReportAuthRejection(&ReportArgs{ns: "testns", broker: "testbroker"}, http.StatusUnauthorized)
+	})
+	metricstest.AssertMetric(t, metricstest.IntMetric("auth_rejection_count", 1, map[string]string{
+		metricskey.LabelResponseCode:      "401",
+		metricskey.LabelResponseCodeClass: "4xx",
+		broker.LabelUniqueName:            "testpod",
+		broker.LabelContainerName:         "testcontainer",
+	}).WithResource(&resource))
+
+	// test ReportThrottled
+	expectSuccess(t, func() error {
+		return r.ReportThrottled(&ReportArgs{ns: "testns", broker: "testbroker"})
+	})
+	expectSuccess(t, func() error {
+		return r.ReportThrottled(&ReportArgs{ns: "testns", broker: "testbroker"})
+	})
+	metricstest.AssertMetric(t, metricstest.IntMetric("throttled_count", 2, map[string]string{
+		broker.LabelUniqueName:    "testpod",
+		broker.LabelContainerName: "testcontainer",
+	}).WithResource(&resource))
+
+	// test ReportSchemaValidationFailure
+	expectSuccess(t, func() error {
+		return r.ReportSchemaValidationFailure(args)
+	})
+	metricstest.AssertMetric(t, metricstest.IntMetric("schema_validation_failure_count", 1, map[string]string{
+		metricskey.LabelEventType: "testeventtype",
+		broker.LabelUniqueName:    "testpod",
+		broker.LabelContainerName: "testcontainer",
+	}).WithResource(&resource))
+
+	// test ReportDuplicate
+	expectSuccess(t, func() error {
+		return r.ReportDuplicate(args)
+	})
+	metricstest.AssertMetric(t, metricstest.IntMetric("duplicate_count", 1, map[string]string{
+		metricskey.LabelEventType: "testeventtype",
+		broker.LabelUniqueName:    "testpod",
+		broker.LabelContainerName: "testcontainer",
+	}).WithResource(&resource))
+
+	// test ReportSpool
+	expectSuccess(t, func() error {
+		return r.ReportSpool(5, 3*time.Second)
+	})
+	expectSuccess(t, func() error {
+		return r.ReportSpool(2, 1500*time.Millisecond)
+	})
+	spoolTags := map[string]string{
+		broker.LabelUniqueName:    "testpod",
+		broker.LabelContainerName: "testcontainer",
+	}
+	metricstest.AssertMetric(t, metricstest.IntMetric("spool_depth", 2, spoolTags))
+	metricstest.AssertMetric(t, metricstest.FloatMetric("spool_oldest_age", 1.5, spoolTags))
 }
 
 func expectSuccess(t *testing.T, f func() error) {
@@ -90,6 +147,12 @@ func resetMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
 	metricstest.Unregister(
 		"event_count",
-		"event_dispatch_latencies")
+		"event_dispatch_latencies",
+		"auth_rejection_count",
+		"throttled_count",
+		"schema_validation_failure_count",
+		"duplicate_count",
+		"spool_depth",
+		"spool_oldest_age")
 	register()
 }
@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package discovery creates EventTypes from the events observed by the Broker
+// ingress, and keeps track of the last time they were observed.
+package discovery
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	clientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	eventtypeinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1beta1/eventtype"
+	listers "knative.dev/eventing/pkg/client/listers/eventing/v1beta1"
+)
+
+// Reconciler writes the EventTypes observed by the local ingress replica. It is
+// leader-aware: only the replica leading the bucket of an EventType writes it,
+// so that the replicas don't race to create and refresh the same EventTypes.
+type Reconciler struct {
+	reconciler.LeaderAwareFuncs
+
+	eventingClientSet clientset.Interface
+	eventTypeLister   listers.EventTypeLister
+	recorder          *Recorder
+}
+
+// Check that our Reconciler implements controller.Reconciler and
+// reconciler.LeaderAware.
+var _ controller.Reconciler = (*Reconciler)(nil)
+var _ reconciler.LeaderAware = (*Reconciler)(nil)
+
+// NewController initializes the controller creating and refreshing the
+// EventTypes observed by the recorder.
+func NewController(ctx context.Context, recorder *Recorder) *controller.Impl {
+	r := newReconciler(eventingclient.Get(ctx), eventtypeinformer.Get(ctx).Lister(), recorder)
+	impl := controller.NewImpl(r, logging.FromContext(ctx), "EventTypeDiscovery")
+	recorder.setEnqueueFunc(impl.EnqueueKey)
+	return impl
+}
+
+func newReconciler(eventingClientSet clientset.Interface, eventTypeLister listers.EventTypeLister, recorder *Recorder) *Reconciler {
+	return &Reconciler{
+		LeaderAwareFuncs: reconciler.LeaderAwareFuncs{
+			// The observations recorded before the promotion were dropped,
+			// hand them over again.
+			PromoteFunc: func(bkt reconciler.Bucket, enq func(reconciler.Bucket, types.NamespacedName)) error {
+				for _, key := range recorder.keys() {
+					enq(bkt, key)
+				}
+				return nil
+			},
+		},
+		eventingClientSet: eventingClientSet,
+		eventTypeLister:   eventTypeLister,
+		recorder:          recorder,
+	}
+}
+
+// Reconcile refreshes the last seen time of the EventType matching the
+// observation with the given key, and creates it if there is none.
+func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Invalid resource key", zap.String("key", key))
+		return nil
+	}
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	if !r.IsLeaderFor(nn) {
+		// Another replica writes this EventType.
+		return nil
+	}
+	o, seen, ok := r.recorder.get(nn)
+	if !ok {
+		// The observation expired.
+		return nil
+	}
+	// The last seen time is stored with a precision of a second.
+	lastSeen := metav1.NewTime(seen).Rfc3339Copy()
+
+	ets, err := r.eventTypeLister.EventTypes(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, et := range ets {
+		if matches(et, o) {
+			return r.refresh(ctx, et, lastSeen)
+		}
+	}
+
+	et, err := makeEventType(o)
+	if err != nil {
+		return controller.NewPermanentError(err)
+	}
+	et, err = r.eventingClientSet.EventingV1beta1().EventTypes(namespace).Create(ctx, et, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create EventType: %w", err)
+	}
+	logging.FromContext(ctx).Infow("Discovered EventType", zap.String("namespace", namespace), zap.String("name", et.Name),
+		zap.String("broker", o.Broker), zap.String("type", o.Type), zap.String("source", o.Source))
+	return r.refresh(ctx, et, lastSeen)
+}
+
+// refresh sets the last seen time of the EventType, unless it is already
+// more recent.
+func (r *Reconciler) refresh(ctx context.Context, et *v1beta1.EventType, lastSeen metav1.Time) error {
+	if et.Status.LastSeen != nil && !et.Status.LastSeen.Before(&lastSeen) {
+		return nil
+	}
+	et = et.DeepCopy()
+	et.Status.LastSeen = &lastSeen
+	_, err := r.eventingClientSet.EventingV1beta1().EventTypes(et.Namespace).UpdateStatus(ctx, et, metav1.UpdateOptions{})
+	return err
+}
+
+// matches returns whether the EventType describes the observed events.
+func matches(et *v1beta1.EventType, o Observation) bool {
+	return et.Spec.Broker == o.Broker &&
+		et.Spec.Type == o.Type &&
+		et.Spec.Source.String() == o.Source &&
+		et.Spec.Schema.String() == o.Schema
+}
+
+func makeEventType(o Observation) (*v1beta1.EventType, error) {
+	source, err := parseURL(o.Source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source %q: %w", o.Source, err)
+	}
+	schema, err := parseURL(o.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %q: %w", o.Schema, err)
+	}
+	return &v1beta1.EventType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      o.Name(),
+			Namespace: o.Namespace,
+			Labels: map[string]string{
+				eventing.AutoDiscoveredLabelKey: "true",
+			},
+		},
+		Spec: v1beta1.EventTypeSpec{
+			Type:   o.Type,
+			Source: source,
+			Schema: schema,
+			Broker: o.Broker,
+		},
+	}, nil
+}
+
+func parseURL(u string) (*apis.URL, error) {
+	if u == "" {
+		return nil, nil
+	}
+	return apis.ParseURL(u)
+}
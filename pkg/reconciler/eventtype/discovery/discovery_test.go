@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/reconciler"
+	. "knative.dev/pkg/reconciler/testing"
+
+	"knative.dev/eventing/pkg/apis/eventing"
+	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	fakeclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
+	. "knative.dev/eventing/pkg/reconciler/testing/v1beta1"
+)
+
+const (
+	testNS     = "test-namespace"
+	brokerName = "test-broker"
+	eventType  = "dev.knative.test"
+)
+
+var (
+	lastSeen    = time.Unix(1e9, 0)
+	observation = Observation{
+		Namespace: testNS,
+		Broker:    brokerName,
+		Type:      eventType,
+		Source:    "/test",
+		Schema:    "https://example.com/schema.json",
+	}
+	testKey = fmt.Sprintf("%s/%s", testNS, observation.Name())
+)
+
+func init() {
+	// Add types to scheme
+	_ = v1beta1.AddToScheme(scheme.Scheme)
+}
+
+func TestReconcile(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "observation expired",
+		Key:  testNS + "/not-observed",
+	}, {
+		Name: "EventType created",
+		Key:  testKey,
+		WantCreates: []runtime.Object{
+			makeDiscoveredEventType(),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: makeDiscoveredEventType(WithEventTypeLastSeen(lastSeen)),
+		}},
+	}, {
+		Name: "EventType creation fails",
+		Key:  testKey,
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("create", "eventtypes"),
+		},
+		WantErr: true,
+		WantCreates: []runtime.Object{
+			makeDiscoveredEventType(),
+		},
+	}, {
+		Name: "matching EventType refreshed",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			makeTestEventType("test-eventtype", WithEventTypeLastSeen(lastSeen.Add(-time.Hour))),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: makeTestEventType("test-eventtype", WithEventTypeLastSeen(lastSeen)),
+		}},
+	}, {
+		Name: "matching EventType up to date",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			makeTestEventType("test-eventtype", WithEventTypeLastSeen(lastSeen)),
+		},
+	}, {
+		Name: "EventType of another Broker",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			makeTestEventType("test-eventtype", WithEventTypeBroker("other")),
+		},
+		WantCreates: []runtime.Object{
+			makeDiscoveredEventType(),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: makeDiscoveredEventType(WithEventTypeLastSeen(lastSeen)),
+		}},
+	}}
+
+	logger := logtesting.TestLogger(t)
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		recorder := NewRecorder(time.Minute, 10)
+		recorder.Record(testNS, brokerName, newEvent(eventType), lastSeen)
+		r := newReconciler(fakeeventingclient.Get(ctx), listers.GetEventTypeLister(), recorder)
+		r.Promote(reconciler.UniversalBucket(), func(reconciler.Bucket, types.NamespacedName) {})
+		return r
+	}, false, logger))
+}
+
+func TestReconcileNotLeader(t *testing.T) {
+	recorder := NewRecorder(time.Minute, 10)
+	recorder.Record(testNS, brokerName, newEvent(eventType), lastSeen)
+	client := fakeclientset.NewSimpleClientset()
+	listers := NewListers(nil)
+	r := newReconciler(client, listers.GetEventTypeLister(), recorder)
+
+	if err := r.Reconcile(logtesting.TestContextWithLogger(t), testKey); err != nil {
+		t.Fatal("Reconcile() =", err)
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("Expected no action from a replica which is not the leader, got %v", actions)
+	}
+}
+
+func TestPromote(t *testing.T) {
+	recorder := NewRecorder(time.Minute, 10)
+	recorder.Record(testNS, brokerName, newEvent(eventType), lastSeen)
+	listers := NewListers(nil)
+	r := newReconciler(fakeclientset.NewSimpleClientset(), listers.GetEventTypeLister(), recorder)
+
+	var enqueued []types.NamespacedName
+	err := r.Promote(reconciler.UniversalBucket(), func(_ reconciler.Bucket, key types.NamespacedName) {
+		enqueued = append(enqueued, key)
+	})
+	if err != nil {
+		t.Fatal("Promote() =", err)
+	}
+	want := []types.NamespacedName{{Namespace: testNS, Name: observation.Name()}}
+	if diff := cmp.Diff(want, enqueued); diff != "" {
+		t.Error("Unexpected enqueued keys (-want, +got):", diff)
+	}
+}
+
+func makeTestEventType(name string, o ...EventTypeOption) *v1beta1.EventType {
+	source, _ := apis.ParseURL(observation.Source)
+	schema, _ := apis.ParseURL(observation.Schema)
+	return NewEventType(name, testNS, append([]EventTypeOption{
+		WithEventTypeType(eventType),
+		WithEventTypeSource(source),
+		WithEventTypeSchema(schema),
+		WithEventTypeBroker(brokerName),
+	}, o...)...)
+}
+
+func makeDiscoveredEventType(o ...EventTypeOption) *v1beta1.EventType {
+	return makeTestEventType(observation.Name(), append([]EventTypeOption{
+		WithEventTypeLabels(map[string]string{eventing.AutoDiscoveredLabelKey: "true"}),
+	}, o...)...)
+}
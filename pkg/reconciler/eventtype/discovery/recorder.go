@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"crypto/md5" //nolint:gosec // No strong cryptography needed.
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// DefaultRefreshInterval is the default minimum interval between two
+	// refreshes of the same EventType.
+	DefaultRefreshInterval = time.Minute
+
+	// DefaultMaxObservations is the default maximum number of EventTypes
+	// tracked by a Recorder.
+	DefaultMaxObservations = 10000
+)
+
+// Observation is an EventType observed by a Broker ingress.
+type Observation struct {
+	Namespace string
+	Broker    string
+	Type      string
+	Source    string
+	Schema    string
+}
+
+// Name returns the name of the EventType discovered from the observation.
+// It is the hash of the observed fields, so that all the ingress replicas
+// agree on it.
+func (o Observation) Name() string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(o.Broker+o.Type+o.Source+o.Schema))) //nolint:gosec // No strong cryptography needed.
+}
+
+type record struct {
+	observation Observation
+	lastSeen    time.Time
+	// lastEnqueued is the last time the observation was handed to the
+	// controller.
+	lastEnqueued time.Time
+}
+
+// Recorder aggregates the EventTypes observed by a Broker ingress. Each of
+// them is handed to the discovery controller at most once per refresh
+// interval, however many events of that type are received.
+type Recorder struct {
+	refreshInterval time.Duration
+	maxObservations int
+
+	mu      sync.Mutex
+	records map[types.NamespacedName]*record
+	enqueue func(types.NamespacedName)
+}
+
+// NewRecorder creates a Recorder refreshing each EventType at most once per
+// refreshInterval and tracking at most maxObservations EventTypes.
+func NewRecorder(refreshInterval time.Duration, maxObservations int) *Recorder {
+	return &Recorder{
+		refreshInterval: refreshInterval,
+		maxObservations: maxObservations,
+		records:         make(map[types.NamespacedName]*record),
+	}
+}
+
+// Record records that the event was sent to the given Broker at the given
+// time.
+func (r *Recorder) Record(namespace, broker string, event *cloudevents.Event, now time.Time) {
+	o := Observation{
+		Namespace: namespace,
+		Broker:    broker,
+		Type:      event.Type(),
+		Source:    event.Source(),
+		Schema:    event.DataSchema(),
+	}
+	key := types.NamespacedName{Namespace: namespace, Name: o.Name()}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.records[key]
+	if !ok {
+		if len(r.records) >= r.maxObservations {
+			r.prune(now)
+		}
+		if len(r.records) >= r.maxObservations {
+			// Too many EventTypes are being observed, the new ones are
+			// ignored until the old ones expire.
+			return
+		}
+		rec = &record{observation: o}
+		r.records[key] = rec
+	}
+	rec.lastSeen = now
+
+	if r.enqueue != nil && now.Sub(rec.lastEnqueued) >= r.refreshInterval {
+		rec.lastEnqueued = now
+		r.enqueue(key)
+	}
+}
+
+// prune forgets the EventTypes which were not observed during the last
+// refresh interval.
+func (r *Recorder) prune(now time.Time) {
+	for key, rec := range r.records {
+		if now.Sub(rec.lastSeen) >= r.refreshInterval {
+			delete(r.records, key)
+		}
+	}
+}
+
+// get returns the observation of the EventType with the given key and the
+// last time it was observed.
+func (r *Recorder) get(key types.NamespacedName) (Observation, time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.records[key]
+	if !ok {
+		return Observation{}, time.Time{}, false
+	}
+	return rec.observation, rec.lastSeen, true
+}
+
+// keys returns the keys of the observed EventTypes.
+func (r *Recorder) keys() []types.NamespacedName {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]types.NamespacedName, 0, len(r.records))
+	for key := range r.records {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// setEnqueueFunc sets the function handing the observed EventTypes to the
+// controller.
+func (r *Recorder) setEnqueueFunc(enqueue func(types.NamespacedName)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.enqueue = enqueue
+}
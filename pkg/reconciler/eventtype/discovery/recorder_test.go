@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newEvent(eventType string) *cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID("1234")
+	e.SetType(eventType)
+	e.SetSource("/test")
+	e.SetDataSchema("https://example.com/schema.json")
+	return &e
+}
+
+func TestRecorder(t *testing.T) {
+	var enqueued []types.NamespacedName
+	r := NewRecorder(time.Minute, 2)
+	r.setEnqueueFunc(func(key types.NamespacedName) {
+		enqueued = append(enqueued, key)
+	})
+
+	now := time.Unix(1e9, 0)
+	r.Record("ns", "default", newEvent("a"), now)
+	r.Record("ns", "default", newEvent("a"), now.Add(time.Second))
+	r.Record("ns", "default", newEvent("a"), now.Add(30*time.Second))
+	if len(enqueued) != 1 {
+		t.Fatalf("Expected 1 enqueued observation within the refresh interval, got %v", enqueued)
+	}
+
+	want := Observation{
+		Namespace: "ns",
+		Broker:    "default",
+		Type:      "a",
+		Source:    "/test",
+		Schema:    "https://example.com/schema.json",
+	}
+	if want := (types.NamespacedName{Namespace: "ns", Name: want.Name()}); enqueued[0] != want {
+		t.Errorf("Expected key %v, got %v", want, enqueued[0])
+	}
+	o, lastSeen, ok := r.get(enqueued[0])
+	if !ok {
+		t.Fatalf("Expected observation %v to be recorded", enqueued[0])
+	}
+	if o != want {
+		t.Errorf("Expected observation %+v, got %+v", want, o)
+	}
+	if want := now.Add(30 * time.Second); !lastSeen.Equal(want) {
+		t.Errorf("Expected last seen %v, got %v", want, lastSeen)
+	}
+
+	r.Record("ns", "default", newEvent("a"), now.Add(time.Minute))
+	if len(enqueued) != 2 {
+		t.Fatalf("Expected the observation to be enqueued again after the refresh interval, got %v", enqueued)
+	}
+
+	// Other brokers observe other EventTypes.
+	r.Record("ns", "other", newEvent("a"), now.Add(time.Minute))
+	if len(enqueued) != 3 || enqueued[2] == enqueued[0] {
+		t.Fatalf("Expected a new observation to be enqueued, got %v", enqueued)
+	}
+
+	// The recorder is full.
+	r.Record("ns", "default", newEvent("b"), now.Add(time.Minute))
+	if len(enqueued) != 3 {
+		t.Fatalf("Expected the observation to be ignored, got %v", enqueued)
+	}
+
+	// The old observations expired.
+	r.Record("ns", "default", newEvent("b"), now.Add(3*time.Minute))
+	if len(enqueued) != 4 {
+		t.Fatalf("Expected the observation to be enqueued, got %v", enqueued)
+	}
+	if _, _, ok := r.get(enqueued[0]); ok {
+		t.Errorf("Expected observation %v to be expired", enqueued[0])
+	}
+}
+
+func TestRecorderWithoutController(t *testing.T) {
+	r := NewRecorder(time.Minute, 10)
+	r.Record("ns", "default", newEvent("a"), time.Unix(1e9, 0))
+
+	var enqueued []types.NamespacedName
+	r.setEnqueueFunc(func(key types.NamespacedName) {
+		enqueued = append(enqueued, key)
+	})
+	r.Record("ns", "default", newEvent("a"), time.Unix(1e9, 0).Add(time.Second))
+	if len(enqueued) != 1 {
+		t.Errorf("Expected the observation to be enqueued once the controller is set, got %v", enqueued)
+	}
+}
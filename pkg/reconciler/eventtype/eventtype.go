@@ -29,6 +29,7 @@ import (
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	eventtypereconciler "knative.dev/eventing/pkg/client/injection/reconciler/eventing/v1beta1/eventtype"
 	listers "knative.dev/eventing/pkg/client/listers/eventing/v1beta1"
+	"knative.dev/eventing/pkg/jsonschema"
 )
 
 type Reconciler struct {
@@ -44,10 +45,13 @@ var brokerGVK = v1beta1.SchemeGroupVersion.WithKind("Broker")
 var _ eventtypereconciler.Interface = (*Reconciler)(nil)
 
 // ReconcileKind implements Interface.ReconcileKind.
-// 1. Verify the Broker exists.
-// 2. Verify the Broker is ready.
+// 1. Verify the SchemaData is a valid JSON Schema, if any.
+// 2. Verify the Broker exists.
+// 3. Verify the Broker is ready.
 // TODO remove https://github.com/knative/eventing/issues/2750
 func (r *Reconciler) ReconcileKind(ctx context.Context, et *v1beta1.EventType) pkgreconciler.Event {
+	checkSchema(et)
+
 	b, err := r.getBroker(et)
 	if err != nil {
 		if apierrs.IsNotFound(err) {
@@ -83,3 +87,18 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, et *v1beta1.EventType) p
 func (r *Reconciler) getBroker(et *v1beta1.EventType) (*v1beta1.Broker, error) {
 	return r.brokerLister.Brokers(et.Namespace).Get(et.Spec.Broker)
 }
+
+// checkSchema reports whether the SchemaData of the EventType compiles as a
+// JSON Schema. The Broker ingress doesn't validate the events against the
+// schemas which don't.
+func checkSchema(et *v1beta1.EventType) {
+	if et.Spec.SchemaData == "" {
+		et.Status.ClearSchemaValid()
+		return
+	}
+	if _, err := jsonschema.Compile([]byte(et.Spec.SchemaData)); err != nil {
+		et.Status.MarkSchemaInvalid("InvalidJSONSchema", "The events are not validated against the SchemaData: %v", err)
+		return
+	}
+	et.Status.MarkSchemaValid()
+}
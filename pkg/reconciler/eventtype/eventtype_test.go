@@ -168,6 +168,56 @@ func TestReconcile(t *testing.T) {
 				WithEventTypeBrokerReady,
 			),
 		}},
+	}, {
+		Name: "Valid JSON Schema",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewEventType(eventTypeName, testNS,
+				WithEventTypeType(eventTypeType),
+				WithEventTypeSource(eventTypeSource),
+				WithEventTypeBroker(eventTypeBroker),
+				WithEventTypeSchemaData(`{"type": "object"}`),
+			),
+			NewBroker(eventTypeBroker, testNS,
+				WithBrokerReady,
+			),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewEventType(eventTypeName, testNS,
+				WithEventTypeType(eventTypeType),
+				WithEventTypeSource(eventTypeSource),
+				WithEventTypeBroker(eventTypeBroker),
+				WithEventTypeSchemaData(`{"type": "object"}`),
+				WithEventTypeSchemaValid,
+				WithEventTypeBrokerExists,
+				WithEventTypeBrokerReady,
+			),
+		}},
+	}, {
+		Name: "Invalid JSON Schema, still ready",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewEventType(eventTypeName, testNS,
+				WithEventTypeType(eventTypeType),
+				WithEventTypeSource(eventTypeSource),
+				WithEventTypeBroker(eventTypeBroker),
+				WithEventTypeSchemaData(`{"type": "record"}`),
+			),
+			NewBroker(eventTypeBroker, testNS,
+				WithBrokerReady,
+			),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewEventType(eventTypeName, testNS,
+				WithEventTypeType(eventTypeType),
+				WithEventTypeSource(eventTypeSource),
+				WithEventTypeBroker(eventTypeBroker),
+				WithEventTypeSchemaData(`{"type": "record"}`),
+				WithEventTypeSchemaInvalid("InvalidJSONSchema", `The events are not validated against the SchemaData: #/type: unknown type "record"`),
+				WithEventTypeBrokerExists,
+				WithEventTypeBrokerReady,
+			),
+		}},
 	}}
 
 	logger := logtesting.TestLogger(t)
@@ -67,6 +67,21 @@ type envConfig struct {
 	MaxIdleConns int `envconfig:"MAX_IDLE_CONNS" required:"true"`
 	// MaxIdleConnsPerHost refers to the max idle connections per host, as in net/http/transport.
 	MaxIdleConnsPerHost int `envconfig:"MAX_IDLE_CONNS_PER_HOST" required:"true"`
+
+	// WebhookValidationOrigin enables the abuse protection handshake with the
+	// subscribers, on behalf of the given origin, when set.
+	WebhookValidationOrigin string `envconfig:"WEBHOOK_VALIDATION_ORIGIN"`
+
+	// CircuitBreakerFailureThreshold enables the circuit breakers of the
+	// subscribers, opening after the given number of consecutive failures,
+	// when greater than 0.
+	CircuitBreakerFailureThreshold int `envconfig:"CIRCUIT_BREAKER_FAILURE_THRESHOLD" default:"0"`
+	// CircuitBreakerOpenTimeout is how long a circuit stays open before
+	// letting trial requests through.
+	CircuitBreakerOpenTimeout time.Duration `envconfig:"CIRCUIT_BREAKER_OPEN_TIMEOUT" default:"30s"`
+	// CircuitBreakerHalfOpenRequests is the number of trial requests which
+	// must succeed to close a circuit again.
+	CircuitBreakerHalfOpenRequests int `envconfig:"CIRCUIT_BREAKER_HALF_OPEN_REQUESTS" default:"1"`
 }
 
 // NewController initializes the controller and is called by the generated code.
@@ -119,6 +134,20 @@ func NewController(
 		reporter:                   reporter,
 		messagingClientSet:         eventingclient.Get(ctx).MessagingV1(),
 	}
+	if env.WebhookValidationOrigin != "" {
+		sender, err := kncloudevents.NewHTTPMessageSenderWithTarget("")
+		if err != nil {
+			logger.Panicw("Failed to create the webhook validation sender", zap.Error(err))
+		}
+		r.webhookValidator = kncloudevents.NewWebhookValidator(sender.Client, env.WebhookValidationOrigin)
+	}
+	if env.CircuitBreakerFailureThreshold > 0 {
+		r.circuitBreakers = channel.NewCircuitBreakers(channel.CircuitBreakerConfig{
+			FailureThreshold: env.CircuitBreakerFailureThreshold,
+			OpenTimeout:      env.CircuitBreakerOpenTimeout,
+			HalfOpenRequests: env.CircuitBreakerHalfOpenRequests,
+		}, reporter, logger.Desugar())
+	}
 	impl := inmemorychannelreconciler.NewImpl(ctx, r, func(impl *controller.Impl) controller.Options {
 		return controller.Options{SkipStatusUpdates: true, FinalizerName: finalizerName}
 	})
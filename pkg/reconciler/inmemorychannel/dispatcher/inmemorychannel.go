@@ -48,6 +48,12 @@ type Reconciler struct {
 	multiChannelMessageHandler multichannelfanout.MultiChannelMessageHandler
 	reporter                   channel.StatsReporter
 	messagingClientSet         messagingv1.MessagingV1Interface
+	// webhookValidator validates the subscribers as webhooks before
+	// dispatching events to them, nil if it is disabled.
+	webhookValidator *kncloudevents.WebhookValidator
+	// circuitBreakers are shared by the dispatchers of all the channels,
+	// nil if they are disabled.
+	circuitBreakers *channel.CircuitBreakers
 }
 
 // Check the interfaces Reconciler should implement
@@ -89,9 +95,16 @@ func (r *Reconciler) reconcile(ctx context.Context, imc *v1.InMemoryChannel) rec
 	handler := r.multiChannelMessageHandler.GetChannelHandler(config.HostName)
 	if handler == nil {
 		// No handler yet, create one.
+		dispatcher := channel.NewMessageDispatcher(logging.FromContext(ctx).Desugar())
+		if r.webhookValidator != nil {
+			dispatcher.EnableWebhookValidation(r.webhookValidator)
+		}
+		if r.circuitBreakers != nil {
+			dispatcher.EnableCircuitBreakers(r.circuitBreakers)
+		}
 		fanoutHandler, err := fanout.NewFanoutMessageHandler(
 			logging.FromContext(ctx).Desugar(),
-			channel.NewMessageDispatcher(logging.FromContext(ctx).Desugar()),
+			dispatcher,
 			config.FanoutConfig,
 			r.reporter,
 		)
@@ -109,6 +122,10 @@ func (r *Reconciler) reconcile(ctx context.Context, imc *v1.InMemoryChannel) rec
 			logging.FromContext(ctx).Info("Updating fanout config: ", zap.String("Diff", diff))
 			handler.SetSubscriptions(ctx, config.FanoutConfig.Subscriptions)
 		}
+		if partitionKeyExtension := config.FanoutConfig.PartitionKeyExtension; partitionKeyExtension != handler.GetPartitionKeyExtension(ctx) {
+			logging.FromContext(ctx).Info("Updating fanout partition key extension: ", zap.String("partitionKeyExtension", partitionKeyExtension))
+			handler.SetPartitionKeyExtension(ctx, partitionKeyExtension)
+		}
 	}
 
 	return nil
@@ -159,13 +176,19 @@ func newConfigForInMemoryChannel(imc *v1.InMemoryChannel) (*multichannelfanout.C
 		subs[i] = *conf
 	}
 
+	var partitionKeyExtension string
+	if imc.Spec.Ordering != nil {
+		partitionKeyExtension = imc.Spec.Ordering.PartitionKeyExtension
+	}
+
 	return &multichannelfanout.ChannelConfig{
 		Namespace: imc.Namespace,
 		Name:      imc.Name,
 		HostName:  imc.Status.Address.URL.Host,
 		FanoutConfig: fanout.Config{
-			AsyncHandler:  true,
-			Subscriptions: subs,
+			AsyncHandler:          true,
+			Subscriptions:         subs,
+			PartitionKeyExtension: partitionKeyExtension,
 		},
 	}, nil
 }
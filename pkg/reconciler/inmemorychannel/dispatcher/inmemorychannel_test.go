@@ -328,10 +328,11 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 	}
 
 	testCases := map[string]struct {
-		imc        *v1.InMemoryChannel
-		subs       []fanout.Subscription
-		wantSubs   []fanout.Subscription
-		wantResult reconciler.Event
+		imc                       *v1.InMemoryChannel
+		subs                      []fanout.Subscription
+		wantSubs                  []fanout.Subscription
+		wantPartitionKeyExtension string
+		wantResult                reconciler.Event
 	}{
 		"with no existing subscribers, 2 added": {
 			imc: NewInMemoryChannel(imcName, testNS,
@@ -433,6 +434,23 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 					RetryConfig: &kncloudevents.RetryConfig{RetryMax: 3, BackoffPolicy: &linear}},
 			},
 		},
+		"with ordering": {
+			imc: NewInMemoryChannel(imcName, testNS,
+				WithInitInMemoryChannelConditions,
+				WithInMemoryChannelDeploymentReady(),
+				WithInMemoryChannelServiceReady(),
+				WithInMemoryChannelEndpointsReady(),
+				WithInMemoryChannelChannelServiceReady(),
+				WithInMemoryChannelSubscribers([]eventingduckv1.SubscriberSpec{subscriber1}),
+				WithInMemoryChannelOrdering("partitionkey"),
+				WithInMemoryChannelAddress(channelServiceAddress)),
+			subs: []fanout.Subscription{*subscription1},
+			wantSubs: []fanout.Subscription{
+				{Subscriber: apis.HTTP("call1").URL(),
+					Reply: apis.HTTP("sink2").URL()},
+			},
+			wantPartitionKeyExtension: "partitionkey",
+		},
 	}
 	for n, tc := range testCases {
 		ctx, fakeEventingClient := fakeeventingclient.With(context.Background(), tc.imc)
@@ -464,6 +482,9 @@ func TestReconciler_ReconcileKind(t *testing.T) {
 				if diff := cmp.Diff(tc.wantSubs, channelHandler.GetSubscriptions(context.TODO()), cmpopts.IgnoreFields(kncloudevents.RetryConfig{}, "Backoff", "CheckRetry")); diff != "" {
 					t.Error("unexpected subs (+want/-got)", diff)
 				}
+				if got := channelHandler.GetPartitionKeyExtension(context.TODO()); got != tc.wantPartitionKeyExtension {
+					t.Errorf("Unexpected partition key extension %q, want %q", got, tc.wantPartitionKeyExtension)
+				}
 			})
 		}
 	}
@@ -37,7 +37,7 @@ func NewSubscription(t *eventingv1.Trigger, brokerTrigger, brokerRef *corev1.Obj
 	return &messagingv1.Subscription{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: t.Namespace,
-			Name:      kmeta.ChildName(fmt.Sprintf("%s-%s-", t.Spec.Broker, t.Name), string(t.GetUID())),
+			Name:      SubscriptionName(t),
 			OwnerReferences: []metav1.OwnerReference{
 				*kmeta.NewControllerRef(t),
 			},
@@ -65,6 +65,47 @@ func NewSubscription(t *eventingv1.Trigger, brokerTrigger, brokerRef *corev1.Obj
 	}
 }
 
+// NewBrokerSubscription returns a placeholder subscription for broker 'b', from brokerTrigger to
+// 'uri'. It has no reply, as the filter evaluates all of the Broker's Triggers for each event and
+// sends their replies to the Broker's ingress itself.
+func NewBrokerSubscription(b *eventingv1.Broker, brokerTrigger *corev1.ObjectReference, uri *apis.URL, delivery *eventingduckv1.DeliverySpec) *messagingv1.Subscription {
+	return &messagingv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: b.Namespace,
+			Name:      BrokerSubscriptionName(b),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(b),
+			},
+			Labels: map[string]string{
+				eventing.BrokerLabelKey: b.Name,
+			},
+		},
+		Spec: messagingv1.SubscriptionSpec{
+			Channel: corev1.ObjectReference{
+				APIVersion: brokerTrigger.APIVersion,
+				Kind:       brokerTrigger.Kind,
+				Name:       brokerTrigger.Name,
+			},
+			Subscriber: &duckv1.Destination{
+				URI: uri,
+			},
+			Delivery: delivery,
+		},
+	}
+}
+
+// SubscriptionName returns the name of the Subscription linking the Trigger 't' to the Broker's
+// Channel.
+func SubscriptionName(t *eventingv1.Trigger) string {
+	return kmeta.ChildName(fmt.Sprintf("%s-%s-", t.Spec.Broker, t.Name), string(t.GetUID()))
+}
+
+// BrokerSubscriptionName returns the name of the Subscription linking all the Triggers of the
+// Broker 'b' to its Channel.
+func BrokerSubscriptionName(b *eventingv1.Broker) string {
+	return kmeta.ChildName(fmt.Sprintf("%s-triggers-", b.Name), string(b.GetUID()))
+}
+
 // SubscriptionLabels generates the labels present on the Subscription linking this Trigger to the
 // Broker's Channels.
 func SubscriptionLabels(t *eventingv1.Trigger) map[string]string {
@@ -103,3 +103,59 @@ func TestNewSubscription(t *testing.T) {
 		t.Error("unexpected diff (-want, +got) =", diff)
 	}
 }
+
+func TestNewBrokerSubscription(t *testing.T) {
+	var TrueValue = true
+	broker := &eventingv1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "t-namespace",
+			Name:      "broker-name",
+		},
+	}
+	triggerChannelRef := &corev1.ObjectReference{
+		Name:       "tc-name",
+		Kind:       "tc-kind",
+		APIVersion: "tc-apiVersion",
+	}
+	delivery := &eventingduckv1.DeliverySpec{
+		DeadLetterSink: &duckv1.Destination{
+			URI: apis.HTTP("dlc.example.com"),
+		},
+	}
+	got := NewBrokerSubscription(broker, triggerChannelRef, apis.HTTP("example.com"), delivery)
+	want := &messagingv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "t-namespace",
+			Name:      "broker-name-triggers-",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "eventing.knative.dev/v1",
+				Kind:               "Broker",
+				Name:               "broker-name",
+				Controller:         &TrueValue,
+				BlockOwnerDeletion: &TrueValue,
+			}},
+			Labels: map[string]string{
+				eventing.BrokerLabelKey: "broker-name",
+			},
+		},
+		Spec: messagingv1.SubscriptionSpec{
+			Channel: corev1.ObjectReference{
+				Name:       "tc-name",
+				Kind:       "tc-kind",
+				APIVersion: "tc-apiVersion",
+			},
+			Subscriber: &duckv1.Destination{
+				URI: apis.HTTP("example.com"),
+			},
+			Delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{
+					URI: apis.HTTP("dlc.example.com"),
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("unexpected diff (-want, +got) =", diff)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/eventing/pkg/apis/eventing"
@@ -71,24 +72,27 @@ func NewController(
 
 	triggerInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
+	// enqueueTriggers enqueues all the Triggers of the given Broker.
+	enqueueTriggers := func(namespace, brokerName string) {
+		selector := labels.SelectorFromSet(map[string]string{eventing.BrokerLabelKey: brokerName})
+		triggers, err := triggerInformer.Lister().Triggers(namespace).List(selector)
+		if err != nil {
+			logger.Warn("Failed to list triggers", zap.String("namespace", namespace), zap.String("broker", brokerName), zap.Error(err))
+			return
+		}
+
+		for _, trigger := range triggers {
+			impl.Enqueue(trigger)
+		}
+	}
+
 	// Filter Brokers and enqueue associated Triggers
 	brokerFilter := pkgreconciler.AnnotationFilterFunc(brokerreconciler.ClassAnnotationKey, eventing.MTChannelBrokerClassValue, false /*allowUnset*/)
 	brokerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: brokerFilter,
 		Handler: controller.HandleAll(func(obj interface{}) {
-
 			if broker, ok := obj.(*eventingv1.Broker); ok {
-
-				selector := labels.SelectorFromSet(map[string]string{eventing.BrokerLabelKey: broker.Name})
-				triggers, err := triggerInformer.Lister().Triggers(broker.Namespace).List(selector)
-				if err != nil {
-					logger.Warn("Failed to list triggers", zap.Any("broker", broker), zap.Error(err))
-					return
-				}
-
-				for _, trigger := range triggers {
-					impl.Enqueue(trigger)
-				}
+				enqueueTriggers(broker.Namespace, broker.Name)
 			}
 		}),
 	})
@@ -99,5 +103,17 @@ func NewController(
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	// Reconcile the Triggers of a Broker when the Broker's Subscription changes
+	subscriptionInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGK(eventingv1.Kind("Broker")),
+		Handler: controller.HandleAll(func(obj interface{}) {
+			if sub, ok := obj.(metav1.Object); ok {
+				if owner := metav1.GetControllerOf(sub); owner != nil {
+					enqueueTriggers(sub.GetNamespace(), owner.Name)
+				}
+			}
+		}),
+	})
+
 	return impl
 }
@@ -149,11 +149,6 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, t *eventingv1.Trigger) p
 // subscribeToBrokerChannel subscribes service 'svc' to the Broker's channels.
 func (r *Reconciler) subscribeToBrokerChannel(ctx context.Context, b *eventingv1.Broker, t *eventingv1.Trigger, brokerTrigger *corev1.ObjectReference) (*messagingv1.Subscription, error) {
 	recorder := controller.GetEventRecorder(ctx)
-	uri := &apis.URL{
-		Scheme: "http",
-		Host:   network.GetServiceHostname("broker-filter", system.Namespace()),
-		Path:   path.Generate(t),
-	}
 	// Note that we have to hard code the brokerGKV stuff as sometimes typemeta is not
 	// filled in. So instead of b.TypeMeta.Kind and b.TypeMeta.APIVersion, we have to
 	// do it this way.
@@ -163,7 +158,31 @@ func (r *Reconciler) subscribeToBrokerChannel(ctx context.Context, b *eventingv1
 		Name:       b.Name,
 		Namespace:  b.Namespace,
 	}
-	expected := resources.NewSubscription(t, brokerTrigger, brokerObjRef, uri, b.Spec.Delivery)
+
+	// In the broker dispatch mode a single Subscription, owned by the Broker, delivers
+	// every event once to the filter, which then evaluates all the Triggers of the Broker.
+	// Only one of the two kinds of Subscription may exist at a time, otherwise the
+	// subscribers would get the events twice.
+	// The single Subscription delivers with the Broker's delivery spec, so the Triggers that
+	// set their own are rejected rather than silently delivered with another one.
+	var expected *messagingv1.Subscription
+	var owner metav1.Object
+	if b.Annotations[eventing.DispatchModeAnnotationKey] == eventing.DispatchModeBroker {
+		if t.Spec.Delivery != nil {
+			return nil, fmt.Errorf("trigger %q sets its own delivery, which is not supported in the broker dispatch mode of broker %q", t.Name, b.Name)
+		}
+		expected = resources.NewBrokerSubscription(b, brokerTrigger, filterURI(path.GenerateBroker(b)), b.Spec.Delivery)
+		owner = b
+		if err := r.deleteSubscription(ctx, t, resources.SubscriptionName(t), t); err != nil {
+			return nil, err
+		}
+	} else {
+		expected = resources.NewSubscription(t, brokerTrigger, brokerObjRef, filterURI(path.Generate(t)), b.Spec.Delivery)
+		owner = t
+		if err := r.deleteSubscription(ctx, t, resources.BrokerSubscriptionName(b), b); err != nil {
+			return nil, err
+		}
+	}
 
 	sub, err := r.subscriptionLister.Subscriptions(t.Namespace).Get(expected.Name)
 	// If the resource doesn't exist, we'll create it.
@@ -178,7 +197,11 @@ func (r *Reconciler) subscribeToBrokerChannel(ctx context.Context, b *eventingv1
 		logging.FromContext(ctx).Errorw("Failed to get subscription", zap.Error(err))
 		recorder.Eventf(t, corev1.EventTypeWarning, subscriptionGetFailed, "Getting the Trigger's Subscription failed: %v", err)
 		return nil, err
-	} else if !metav1.IsControlledBy(sub, t) {
+	} else if !metav1.IsControlledBy(sub, owner) {
+		if owner == b {
+			t.Status.MarkNotSubscribed("SubscriptionNotOwnedByBroker", "broker %q does not own subscription %q", b.Name, sub.Name)
+			return nil, fmt.Errorf("broker %q does not own subscription %q", b.Name, sub.Name)
+		}
 		t.Status.MarkNotSubscribed("SubscriptionNotOwnedByTrigger", "trigger %q does not own subscription %q", t.Name, sub.Name)
 		return nil, fmt.Errorf("trigger %q does not own subscription %q", t.Name, sub.Name)
 	} else if sub, err = r.reconcileSubscription(ctx, t, expected, sub); err != nil {
@@ -189,6 +212,35 @@ func (r *Reconciler) subscribeToBrokerChannel(ctx context.Context, b *eventingv1
 	return sub, nil
 }
 
+// deleteSubscription deletes the Subscription 'name' if it exists and is controlled by 'owner'.
+func (r *Reconciler) deleteSubscription(ctx context.Context, t *eventingv1.Trigger, name string, owner metav1.Object) error {
+	sub, err := r.subscriptionLister.Subscriptions(t.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(sub, owner) {
+		return nil
+	}
+	logging.FromContext(ctx).Infow("Deleting subscription of the other dispatch mode", zap.String("namespace", sub.Namespace), zap.String("name", sub.Name))
+	err = r.eventingClientSet.MessagingV1().Subscriptions(t.Namespace).Delete(ctx, sub.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrs.IsNotFound(err) {
+		controller.GetEventRecorder(ctx).Eventf(t, corev1.EventTypeWarning, subscriptionDeleteFailed, "Delete Trigger's subscription failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// filterURI returns the URI of the broker filter for the given path.
+func filterURI(p string) *apis.URL {
+	return &apis.URL{
+		Scheme: "http",
+		Host:   network.GetServiceHostname("broker-filter", system.Namespace()),
+		Path:   p,
+	}
+}
+
 func (r *Reconciler) reconcileSubscription(ctx context.Context, t *eventingv1.Trigger, expected, actual *messagingv1.Subscription) (*messagingv1.Subscription, error) {
 	// Update Subscription if it has changed.
 	if equality.Semantic.DeepDerivative(expected.Spec, actual.Spec) {
@@ -110,7 +110,8 @@ var (
 	filterServiceName  = "broker-filter"
 	ingressServiceName = "broker-ingress"
 
-	subscriptionName = fmt.Sprintf("%s-%s-%s", brokerName, triggerName, triggerUID)
+	subscriptionName       = fmt.Sprintf("%s-%s-%s", brokerName, triggerName, triggerUID)
+	brokerSubscriptionName = fmt.Sprintf("%s-triggers-", brokerName)
 
 	subscriberAPIVersion = fmt.Sprintf("%s/%s", subscriberGroup, subscriberVersion)
 	subscriberGVK        = metav1.GroupVersionKind{
@@ -514,6 +515,122 @@ func TestReconcile(t *testing.T) {
 					WithTriggerDependencyReady(),
 				),
 			}},
+		}, {
+			Name: "Broker dispatch mode, Broker subscription created",
+			Key:  testKey,
+			Objects: allBrokerObjectsInBrokerDispatchModePlus(
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithInitTriggerConditions,
+				)),
+			WantErr: false,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					// The first reconciliation will initialize the status conditions.
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady(),
+					WithTriggerSubscriptionNotConfigured(),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedSucceeded(),
+					WithTriggerDependencyReady(),
+				),
+			}},
+			WantCreates: []runtime.Object{
+				makeBrokerFilterSubscription(),
+			},
+		}, {
+			Name: "Broker dispatch mode, Trigger delivery rejected",
+			Key:  testKey,
+			Objects: allBrokerObjectsInBrokerDispatchModePlus(
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerDelivery(&eventingduckv1.DeliverySpec{}),
+					WithInitTriggerConditions,
+				)),
+			WantErr: true,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithTriggerDelivery(&eventingduckv1.DeliverySpec{}),
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady(),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedSucceeded(),
+					WithTriggerNotSubscribed("NotSubscribed", `trigger "test-trigger" sets its own delivery, which is not supported in the broker dispatch mode of broker "test-broker"`),
+				),
+			}},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeWarning, "InternalError", `trigger "test-trigger" sets its own delivery, which is not supported in the broker dispatch mode of broker "test-broker"`),
+			},
+		}, {
+			Name: "Broker dispatch mode, Trigger subscription deleted",
+			Key:  testKey,
+			Objects: allBrokerObjectsInBrokerDispatchModePlus(
+				makeReadySubscription(),
+				makeReadyBrokerSubscription(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithInitTriggerConditions,
+				)),
+			WantErr: false,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					// The first reconciliation will initialize the status conditions.
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady(),
+					WithTriggerSubscribed(),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedSucceeded(),
+					WithTriggerDependencyReady(),
+				),
+			}},
+			WantDeletes: []clientgotesting.DeleteActionImpl{{
+				ActionImpl: clientgotesting.ActionImpl{
+					Namespace: testNS,
+					Resource:  v1.SchemeGroupVersion.WithResource("subscriptions"),
+				},
+				Name: subscriptionName,
+			}},
+		}, {
+			Name: "Trigger dispatch mode, Broker subscription deleted",
+			Key:  testKey,
+			Objects: allBrokerObjectsReadyPlus(
+				makeReadySubscription(),
+				makeReadyBrokerSubscription(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					WithInitTriggerConditions,
+				)),
+			WantErr: false,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(triggerUID),
+					WithTriggerSubscriberURI(subscriberURI),
+					// The first reconciliation will initialize the status conditions.
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady(),
+					WithTriggerSubscribed(),
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedSucceeded(),
+					WithTriggerDependencyReady(),
+				),
+			}},
+			WantDeletes: []clientgotesting.DeleteActionImpl{{
+				ActionImpl: clientgotesting.ActionImpl{
+					Namespace: testNS,
+					Resource:  v1.SchemeGroupVersion.WithResource("subscriptions"),
+				},
+				Name: brokerSubscriptionName,
+			}},
 		}, {
 			Name: "Dependency doesn't exist",
 			Key:  testKey,
@@ -783,6 +900,22 @@ func makeFilterSubscription() *messagingv1.Subscription {
 	return resources.NewSubscription(makeTrigger(), createTriggerChannelRef(), makeBrokerRef(), makeServiceURI(), makeEmptyDelivery())
 }
 
+func makeBrokerFilterSubscription() *messagingv1.Subscription {
+	uri := &apis.URL{
+		Scheme: "http",
+		Host:   network.GetServiceHostname("broker-filter", systemNS),
+		Path:   fmt.Sprintf("/brokers/%s/%s", testNS, brokerName),
+	}
+	b := NewBroker(brokerName, testNS, WithBrokerDispatchMode(eventing.DispatchModeBroker))
+	return resources.NewBrokerSubscription(b, createTriggerChannelRef(), uri, makeEmptyDelivery())
+}
+
+func makeReadyBrokerSubscription() *messagingv1.Subscription {
+	s := makeBrokerFilterSubscription()
+	s.Status = *eventingv1.TestHelper.ReadySubscriptionStatus()
+	return s
+}
+
 func makeTrigger() *eventingv1.Trigger {
 	return &eventingv1.Trigger{
 		TypeMeta: metav1.TypeMeta{
@@ -848,6 +981,12 @@ func allBrokerObjectsReadyPlus(objs ...runtime.Object) []runtime.Object {
 	return append(brokerObjs[:], objs...)
 }
 
+func allBrokerObjectsInBrokerDispatchModePlus(objs ...runtime.Object) []runtime.Object {
+	brokerObjs := allBrokerObjectsReadyPlus(objs...)
+	WithBrokerDispatchMode(eventing.DispatchModeBroker)(brokerObjs[0].(*eventingv1.Broker))
+	return brokerObjs
+}
+
 // Just so we can test subscription updates
 func makeDifferentReadySubscription() *messagingv1.Subscription {
 	s := makeFilterSubscription()
@@ -588,13 +588,17 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1alpha1.Channelabl
 				},
 			}
 		}
-		if channel.Spec.Delivery.BackoffDelay != nil || channel.Spec.Delivery.Retry != nil || channel.Spec.Delivery.BackoffPolicy != nil {
+		if channel.Spec.Delivery.BackoffDelay != nil || channel.Spec.Delivery.Retry != nil || channel.Spec.Delivery.BackoffPolicy != nil || channel.Spec.Delivery.BackoffMaxDelay != nil || channel.Spec.Delivery.Timeout != nil || channel.Spec.Delivery.RetryableStatusCodes != nil || channel.Spec.Delivery.MaxConcurrency != nil {
 			if delivery == nil {
 				delivery = &eventingduckv1beta1.DeliverySpec{}
 			}
 			delivery.BackoffPolicy = channel.Spec.Delivery.BackoffPolicy
 			delivery.Retry = channel.Spec.Delivery.Retry
 			delivery.BackoffDelay = channel.Spec.Delivery.BackoffDelay
+			delivery.BackoffMaxDelay = channel.Spec.Delivery.BackoffMaxDelay
+			delivery.Timeout = channel.Spec.Delivery.Timeout
+			delivery.RetryableStatusCodes = channel.Spec.Delivery.RetryableStatusCodes
+			delivery.MaxConcurrency = channel.Spec.Delivery.MaxConcurrency
 		}
 		return
 	}
@@ -608,13 +612,17 @@ func deliverySpec(sub *v1.Subscription, channel *eventingduckv1alpha1.Channelabl
 			},
 		}
 	}
-	if sub.Spec.Delivery != nil && (sub.Spec.Delivery.BackoffDelay != nil || sub.Spec.Delivery.Retry != nil || sub.Spec.Delivery.BackoffPolicy != nil) {
+	if sub.Spec.Delivery != nil && (sub.Spec.Delivery.BackoffDelay != nil || sub.Spec.Delivery.Retry != nil || sub.Spec.Delivery.BackoffPolicy != nil || sub.Spec.Delivery.BackoffMaxDelay != nil || sub.Spec.Delivery.Timeout != nil || sub.Spec.Delivery.RetryableStatusCodes != nil || sub.Spec.Delivery.MaxConcurrency != nil) {
 		if delivery == nil {
 			delivery = &eventingduckv1beta1.DeliverySpec{}
 		}
 		delivery.BackoffPolicy = (*eventingduckv1beta1.BackoffPolicyType)(sub.Spec.Delivery.BackoffPolicy)
 		delivery.Retry = sub.Spec.Delivery.Retry
 		delivery.BackoffDelay = sub.Spec.Delivery.BackoffDelay
+		delivery.BackoffMaxDelay = sub.Spec.Delivery.BackoffMaxDelay
+		delivery.Timeout = sub.Spec.Delivery.Timeout
+		delivery.RetryableStatusCodes = sub.Spec.Delivery.RetryableStatusCodes
+		delivery.MaxConcurrency = sub.Spec.Delivery.MaxConcurrency
 	}
 	return
 }
@@ -25,7 +25,8 @@ import (
 )
 
 const (
-	prefix = "triggers"
+	prefix       = "triggers"
+	brokerPrefix = "brokers"
 )
 
 // Generate generates the Path portion of a URI to send events to the given Trigger.
@@ -59,3 +60,34 @@ func Parse(path string) (NamespacedNameUID, error) {
 		UID: types.UID(parts[4]),
 	}, nil
 }
+
+// GenerateBroker generates the Path portion of a URI to send events to all the Triggers
+// of the given Broker at once.
+func GenerateBroker(b *v1.Broker) string {
+	return fmt.Sprintf("/%s/%s/%s", brokerPrefix, b.Namespace, b.Name)
+}
+
+// IsBroker reports whether the Path portion of a URI addresses all the Triggers of a
+// Broker rather than a single Trigger.
+func IsBroker(path string) bool {
+	return strings.HasPrefix(path, "/"+brokerPrefix+"/")
+}
+
+// ParseBroker parses the Path portion of a URI to determine which Broker the request
+// corresponds to. It is expected to be in the form "/brokers/namespace/name".
+func ParseBroker(path string) (types.NamespacedName, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 {
+		return types.NamespacedName{}, fmt.Errorf("incorrect number of parts in the path, expected 4, actual %d, '%s'", len(parts), path)
+	}
+	if parts[0] != "" {
+		return types.NamespacedName{}, fmt.Errorf("text before the first slash, actual '%s'", path)
+	}
+	if parts[1] != brokerPrefix {
+		return types.NamespacedName{}, fmt.Errorf("incorrect prefix, expected '%s', actual '%s'", brokerPrefix, path)
+	}
+	return types.NamespacedName{
+		Namespace: parts[2],
+		Name:      parts[3],
+	}, nil
+}
@@ -157,6 +157,18 @@ func WithBrokerClass(bc string) BrokerOption {
 	}
 }
 
+// WithBrokerDispatchMode sets the dispatch mode annotation of the Broker.
+func WithBrokerDispatchMode(mode string) BrokerOption {
+	return func(b *v1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[eventing.DispatchModeAnnotationKey] = mode
+		b.SetAnnotations(annotations)
+	}
+}
+
 func WithChannelAddressAnnotation(address string) BrokerOption {
 	return func(b *v1.Broker) {
 		if b.Status.Annotations == nil {
@@ -77,6 +77,12 @@ func WithInMemoryChannelSubscribers(subscribers []duckv1.SubscriberSpec) InMemor
 	}
 }
 
+func WithInMemoryChannelOrdering(partitionKeyExtension string) InMemoryChannelOption {
+	return func(imc *v1.InMemoryChannel) {
+		imc.Spec.Ordering = &v1.InMemoryChannelOrdering{PartitionKeyExtension: partitionKeyExtension}
+	}
+}
+
 func WithInMemoryChannelDeploymentFailed(reason, message string) InMemoryChannelOption {
 	return func(imc *v1.InMemoryChannel) {
 		imc.Status.MarkDispatcherFailed(reason, message)
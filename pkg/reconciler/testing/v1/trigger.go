@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	v1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -224,3 +225,10 @@ func WithTriggerUID(uid string) TriggerOption {
 		t.UID = types.UID(uid)
 	}
 }
+
+// WithTriggerDelivery sets the delivery spec of the Trigger.
+func WithTriggerDelivery(delivery *eventingduckv1.DeliverySpec) TriggerOption {
+	return func(t *v1.Trigger) {
+		t.Spec.Delivery = delivery
+	}
+}
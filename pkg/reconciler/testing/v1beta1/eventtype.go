@@ -63,6 +63,12 @@ func WithEventTypeBroker(broker string) EventTypeOption {
 	}
 }
 
+func WithEventTypeSchemaData(schemaData string) EventTypeOption {
+	return func(et *v1beta1.EventType) {
+		et.Spec.SchemaData = schemaData
+	}
+}
+
 func WithEventTypeDescription(description string) EventTypeOption {
 	return func(et *v1beta1.EventType) {
 		et.Spec.Description = description
@@ -114,3 +120,28 @@ func WithEventTypeBrokerUnknown(reason, message string) EventTypeOption {
 func WithEventTypeBrokerReady(et *v1beta1.EventType) {
 	et.Status.MarkBrokerReady()
 }
+
+// WithEventTypeSchemaValid calls .Status.MarkSchemaValid on the EventType.
+func WithEventTypeSchemaValid(et *v1beta1.EventType) {
+	et.Status.MarkSchemaValid()
+}
+
+func WithEventTypeSchemaInvalid(reason, message string) EventTypeOption {
+	return func(et *v1beta1.EventType) {
+		et.Status.MarkSchemaInvalid(reason, message)
+	}
+}
+
+func WithEventTypeSchema(schema *apis.URL) EventTypeOption {
+	return func(et *v1beta1.EventType) {
+		et.Spec.Schema = schema
+	}
+}
+
+// WithEventTypeLastSeen sets .Status.LastSeen on the EventType.
+func WithEventTypeLastSeen(lastSeen time.Time) EventTypeOption {
+	return func(et *v1beta1.EventType) {
+		t := metav1.NewTime(lastSeen)
+		et.Status.LastSeen = &t
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redrive sends the events of a dead letter sink back to their
+// destination, once it is able to handle them.
+package redrive
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/eventing/pkg/channel/attributes"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// knativeErrorExtensionPrefix is the prefix of the extensions added to the
+// events sent to a dead letter sink, see the attributes package.
+const knativeErrorExtensionPrefix = "knativeerror"
+
+// Filter selects the events to redrive.
+type Filter struct {
+	// Types are the types of the events to redrive, all of them if empty.
+	Types []string
+	// Since and Until bound the time the events were dead lettered, i.e.
+	// the time of their last attempt, or their own time if they don't carry
+	// it. They are ignored when zero.
+	Since time.Time
+	Until time.Time
+}
+
+// Match returns true if event is selected by f.
+func (f Filter) Match(event cloudevents.Event) bool {
+	if len(f.Types) > 0 && !sets.NewString(f.Types...).Has(event.Type()) {
+		return false
+	}
+	if f.Since.IsZero() && f.Until.IsZero() {
+		return true
+	}
+	t := deadLetterTime(event)
+	if !f.Since.IsZero() && t.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && t.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// StripErrorExtensions removes the extensions added to event when it was
+// sent to the dead letter sink.
+func StripErrorExtensions(event *cloudevents.Event) {
+	for name := range event.Extensions() {
+		if strings.HasPrefix(name, knativeErrorExtensionPrefix) {
+			event.SetExtension(name, nil)
+		}
+	}
+}
+
+// Result summarizes a redrive.
+type Result struct {
+	// Sent is the number of events sent, or which would have been sent in
+	// dry run mode.
+	Sent int
+	// Skipped is the number of events not selected by the filter.
+	Skipped int
+	// Failed is the number of events which couldn't be sent.
+	Failed int
+}
+
+// Redriver sends dead lettered events to a target, e.g. the address of a
+// Broker or the subscriber of a Trigger.
+type Redriver struct {
+	sender  *kncloudevents.HTTPMessageSender
+	target  string
+	limiter *rate.Limiter
+	dryRun  bool
+	logger  *zap.Logger
+}
+
+// NewRedriver creates a Redriver sending at most eventsPerSecond events per
+// second to target, or as fast as possible if eventsPerSecond is not greater
+// than 0. In dry run mode, the events are logged instead of being sent.
+func NewRedriver(target string, eventsPerSecond float64, dryRun bool, logger *zap.Logger) (*Redriver, error) {
+	sender, err := kncloudevents.NewHTTPMessageSenderWithTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	limit := rate.Inf
+	if eventsPerSecond > 0 {
+		limit = rate.Limit(eventsPerSecond)
+	}
+	return &Redriver{
+		sender:  sender,
+		target:  target,
+		limiter: rate.NewLimiter(limit, 1),
+		dryRun:  dryRun,
+		logger:  logger,
+	}, nil
+}
+
+// Redrive sends the events selected by filter to the target, without their
+// dead letter extensions. It carries on when an event can't be sent, and
+// only stops when ctx is done.
+func (r *Redriver) Redrive(ctx context.Context, events []cloudevents.Event, filter Filter) (Result, error) {
+	var result Result
+	for _, event := range events {
+		if !filter.Match(event) {
+			result.Skipped++
+			continue
+		}
+		event = event.Clone()
+		StripErrorExtensions(&event)
+
+		logger := r.logger.With(zap.String("id", event.ID()), zap.String("source", event.Source()), zap.String("type", event.Type()))
+		if r.dryRun {
+			logger.Info("Would redrive event", zap.String("target", r.target))
+			result.Sent++
+			continue
+		}
+
+		if err := r.limiter.Wait(ctx); err != nil {
+			return result, err
+		}
+		if err := r.send(ctx, event); err != nil {
+			logger.Warn("Failed to redrive event", zap.Error(err))
+			result.Failed++
+			continue
+		}
+		logger.Debug("Redrove event")
+		result.Sent++
+	}
+	return result, nil
+}
+
+func (r *Redriver) send(ctx context.Context, event cloudevents.Event) error {
+	req, err := r.sender.NewCloudEventRequest(ctx)
+	if err != nil {
+		return err
+	}
+	if err := kncloudevents.WriteHTTPRequestWithAdditionalHeaders(ctx, binding.ToMessage(&event), req, nil); err != nil {
+		return err
+	}
+	resp, err := r.sender.Send(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < nethttp.StatusOK || resp.StatusCode >= nethttp.StatusMultipleChoices {
+		return fmt.Errorf("unexpected HTTP response, expected 2xx, got %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// deadLetterTime returns the time of the last attempt to send event before
+// it was dead lettered, or the time of the event if it doesn't carry it.
+func deadLetterTime(event cloudevents.Event) time.Time {
+	if v, ok := event.Extensions()[attributes.KnativeErrorLastAttemptExtensionKey]; ok {
+		if t, err := types.ToTime(v); err == nil {
+			return t
+		}
+	}
+	return event.Time()
+}
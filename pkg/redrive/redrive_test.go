@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redrive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap/zaptest"
+
+	"knative.dev/eventing/pkg/channel/attributes"
+)
+
+func TestFilter(t *testing.T) {
+	now := time.Now()
+	event := func(eventType string, lastAttempt time.Time) cloudevents.Event {
+		e := cloudevents.NewEvent()
+		e.SetType(eventType)
+		e.SetTime(now.Add(-time.Hour))
+		if !lastAttempt.IsZero() {
+			e.SetExtension(attributes.KnativeErrorLastAttemptExtensionKey, lastAttempt)
+		}
+		return e
+	}
+	tests := map[string]struct {
+		filter Filter
+		event  cloudevents.Event
+		want   bool
+	}{
+		"no filter": {
+			event: event("dev.knative.a", time.Time{}),
+			want:  true,
+		},
+		"type": {
+			filter: Filter{Types: []string{"dev.knative.a", "dev.knative.b"}},
+			event:  event("dev.knative.b", time.Time{}),
+			want:   true,
+		},
+		"other type": {
+			filter: Filter{Types: []string{"dev.knative.a"}},
+			event:  event("dev.knative.c", time.Time{}),
+		},
+		"last attempt in range": {
+			filter: Filter{Since: now.Add(-time.Minute), Until: now.Add(time.Minute)},
+			event:  event("dev.knative.a", now),
+			want:   true,
+		},
+		"last attempt too early": {
+			filter: Filter{Since: now.Add(-time.Minute)},
+			event:  event("dev.knative.a", now.Add(-2*time.Minute)),
+		},
+		"event time too early": {
+			filter: Filter{Since: now.Add(-time.Minute)},
+			event:  event("dev.knative.a", time.Time{}),
+		},
+		"event time in range": {
+			filter: Filter{Until: now},
+			event:  event("dev.knative.a", time.Time{}),
+			want:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.filter.Match(tc.event); got != tc.want {
+				t.Errorf("Match() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRedrive(t *testing.T) {
+	var received []cloudevents.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := binding.ToEvent(context.Background(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Error("Failed to decode the redriven event:", err)
+		}
+		if event.Type() == "dev.knative.fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received = append(received, *event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	newEvent := func(id, eventType string) cloudevents.Event {
+		e := cloudevents.NewEvent()
+		e.SetID(id)
+		e.SetSource("/source")
+		e.SetType(eventType)
+		e.SetExtension("custom", "value")
+		e.SetExtension(attributes.KnativeErrorCodeExtensionKey, 500)
+		e.SetExtension(attributes.KnativeErrorDataExtensionKey, "failed")
+		e.SetExtension(attributes.KnativeErrorStageExtensionKey, attributes.KnativeErrorStageSubscriber)
+		return e
+	}
+	events := []cloudevents.Event{
+		newEvent("1", "dev.knative.a"),
+		newEvent("2", "dev.knative.b"),
+		newEvent("3", "dev.knative.fail"),
+		newEvent("4", "dev.knative.a"),
+	}
+	filter := Filter{Types: []string{"dev.knative.a", "dev.knative.fail"}}
+
+	t.Run("dry run", func(t *testing.T) {
+		r, err := NewRedriver(server.URL, 0, true, zaptest.NewLogger(t))
+		if err != nil {
+			t.Fatal("NewRedriver() =", err)
+		}
+		result, err := r.Redrive(context.Background(), events, filter)
+		if err != nil {
+			t.Fatal("Redrive() =", err)
+		}
+		if want := (Result{Sent: 3, Skipped: 1}); result != want {
+			t.Errorf("Redrive() = %+v, want %+v", result, want)
+		}
+		if len(received) != 0 {
+			t.Errorf("Expected no event to be sent, got %d", len(received))
+		}
+	})
+
+	t.Run("send", func(t *testing.T) {
+		r, err := NewRedriver(server.URL, 1000, false, zaptest.NewLogger(t))
+		if err != nil {
+			t.Fatal("NewRedriver() =", err)
+		}
+		result, err := r.Redrive(context.Background(), events, filter)
+		if err != nil {
+			t.Fatal("Redrive() =", err)
+		}
+		if want := (Result{Sent: 2, Skipped: 1, Failed: 1}); result != want {
+			t.Errorf("Redrive() = %+v, want %+v", result, want)
+		}
+		if len(received) != 2 || received[0].ID() != "1" || received[1].ID() != "4" {
+			t.Fatalf("Expected events 1 and 4 to be sent, got %v", received)
+		}
+		for _, event := range received {
+			extensions := event.Extensions()
+			if len(extensions) != 1 || extensions["custom"] != "value" {
+				t.Errorf("Expected only the custom extension, got %v", extensions)
+			}
+		}
+		// The original events are left untouched.
+		if _, ok := events[0].Extensions()[attributes.KnativeErrorCodeExtensionKey]; !ok {
+			t.Error("Expected the original event to keep its extensions")
+		}
+	})
+}
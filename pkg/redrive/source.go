@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redrive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	nethttp "net/http"
+	"os"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// record is an entry of an export wrapping the event, like those of the
+// recordevents test image.
+type record struct {
+	Event *cloudevents.Event `json:"event"`
+}
+
+// ReadEvents decodes the events of an export, either a JSON array or JSON
+// lines. Each entry is either a CloudEvent in the JSON format, or a record
+// holding the CloudEvent in its "event" field. Records without an event are
+// skipped.
+func ReadEvents(r io.Reader) ([]cloudevents.Event, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []json.RawMessage
+	if first == '[' {
+		if err := json.NewDecoder(br).Decode(&entries); err != nil {
+			return nil, fmt.Errorf("failed to decode the events: %w", err)
+		}
+	} else {
+		decoder := json.NewDecoder(br)
+		for {
+			var entry json.RawMessage
+			if err := decoder.Decode(&entry); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to decode event %d: %w", len(entries), err)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	events := make([]cloudevents.Event, 0, len(entries))
+	for i, entry := range entries {
+		event, err := decodeEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode event %d: %w", i, err)
+		}
+		if event != nil {
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+// LoadEvents reads the events of the export at location, fetched with client
+// if it is an http or https URL, read from the standard input if it is "-",
+// and read from the file at this path otherwise.
+func LoadEvents(ctx context.Context, client *nethttp.Client, location string) ([]cloudevents.Event, error) {
+	if location == "-" {
+		return ReadEvents(os.Stdin)
+	}
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ReadEvents(f)
+	}
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("failed to fetch %s: unexpected status code %d: %s", location, resp.StatusCode, body)
+	}
+	return ReadEvents(resp.Body)
+}
+
+func decodeEntry(entry json.RawMessage) (*cloudevents.Event, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(entry, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["specversion"]; !ok {
+		var r record
+		if err := json.Unmarshal(entry, &r); err != nil {
+			return nil, err
+		}
+		return r.Event, nil
+	}
+	event := cloudevents.NewEvent()
+	if err := json.Unmarshal(entry, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// peekNonSpace skips the leading white space of br and returns the next byte
+// without consuming it.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+		if _, err := br.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+}
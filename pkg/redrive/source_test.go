@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redrive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	event1 = `{"specversion":"1.0","id":"1","source":"/source","type":"dev.knative.a","knativeerrorcode":500}`
+	event2 = `{"specversion":"1.0","id":"2","source":"/source","type":"dev.knative.b"}`
+)
+
+func TestReadEvents(t *testing.T) {
+	tests := map[string]string{
+		"array":         "[" + event1 + "," + event2 + "]",
+		"json lines":    event1 + "\n" + event2 + "\n",
+		"records":       fmt.Sprintf(`[{"kind":"Received","event":%s},{"kind":"Received","error":"bad request"},{"kind":"Received","event":%s}]`, event1, event2),
+		"leading space": "\n  " + event1 + event2,
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			events, err := ReadEvents(strings.NewReader(in))
+			if err != nil {
+				t.Fatal("ReadEvents() =", err)
+			}
+			if len(events) != 2 || events[0].ID() != "1" || events[1].ID() != "2" {
+				t.Errorf("ReadEvents() = %v, want events 1 and 2", events)
+			}
+		})
+	}
+
+	if events, err := ReadEvents(strings.NewReader("")); err != nil || len(events) != 0 {
+		t.Errorf("ReadEvents() = %v, %v, want no events", events, err)
+	}
+	if _, err := ReadEvents(strings.NewReader("{not json")); err == nil {
+		t.Error("ReadEvents() = nil, want an error")
+	}
+}
+
+func TestLoadEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/events" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(event1 + "\n" + event2))
+	}))
+	defer server.Close()
+
+	events, err := LoadEvents(context.Background(), server.Client(), server.URL+"/events")
+	if err != nil {
+		t.Fatal("LoadEvents() =", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("LoadEvents() = %v, want 2 events", events)
+	}
+
+	if _, err := LoadEvents(context.Background(), server.Client(), server.URL+"/other"); err == nil {
+		t.Error("LoadEvents() = nil, want an error")
+	}
+}